@@ -0,0 +1,78 @@
+// Package filelock provides a cross-process mutex on a lock file, shared by
+// the state files webauto processes update together (rate limits, proxy
+// rotation, healed selectors).
+package filelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// pollInterval is the delay between lock attempts while another process
+// holds the lock
+const pollInterval = 20 * time.Millisecond
+
+// errLocked is returned by tryLock when another handle holds the lock
+var errLocked = errors.New("lock is held")
+
+// Lock is a held lock. The operating system releases it when the holding
+// process exits, so a crashed process never leaves a stale lock behind and
+// lock files are never deleted.
+type Lock struct {
+	file *os.File
+}
+
+// Acquire blocks until the lock on path is held or ctx is done. The lock
+// file is created if needed. Every call opens its own handle, so goroutines
+// of one process exclude each other like separate processes do.
+func Acquire(ctx context.Context, path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	for {
+		err := tryLock(file)
+		if err == nil {
+			return &Lock{file: file}, nil
+		}
+		if !errors.Is(err, errLocked) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// TryAcquire takes the lock on path if no other handle holds it. It returns
+// a nil Lock without error when the lock is held elsewhere.
+func TryAcquire(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := tryLock(file); err != nil {
+		file.Close()
+		if errors.Is(err, errLocked) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release unlocks and closes the lock file
+func (l *Lock) Release() {
+	_ = unlock(l.file)
+	_ = l.file.Close()
+}
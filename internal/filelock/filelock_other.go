@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package filelock
+
+import "os"
+
+// tryLock is not implemented on this platform: Acquire always succeeds and
+// state files are only protected by their atomic renames
+func tryLock(file *os.File) error {
+	return nil
+}
+
+func unlock(file *os.File) error {
+	return nil
+}
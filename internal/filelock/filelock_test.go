@@ -0,0 +1,88 @@
+package filelock
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireExcludes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.lock")
+
+	var mu sync.Mutex
+	holders, maxHolders := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock, err := Acquire(context.Background(), path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			holders++
+			if holders > maxHolders {
+				maxHolders = holders
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			lock.Release()
+		}()
+	}
+	wg.Wait()
+
+	if maxHolders != 1 {
+		t.Errorf("%d handles held the lock at once, want 1", maxHolders)
+	}
+}
+
+func TestAcquireHonorsContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.lock")
+	held, err := Acquire(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() on a held lock = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The lock file stays in place and can be taken again after release
+	held.Release()
+	lock, err := Acquire(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Acquire() after release: %v", err)
+	}
+	lock.Release()
+}
+
+func TestTryAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.lock")
+	held, err := TryAcquire(path)
+	if err != nil || held == nil {
+		t.Fatalf("TryAcquire() on a free lock = %v, %v", held, err)
+	}
+
+	if lock, err := TryAcquire(path); err != nil || lock != nil {
+		t.Errorf("TryAcquire() on a held lock = %v, %v, want nil, nil", lock, err)
+	}
+
+	held.Release()
+	lock, err := TryAcquire(path)
+	if err != nil || lock == nil {
+		t.Fatalf("TryAcquire() after release = %v, %v", lock, err)
+	}
+	lock.Release()
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package filelock
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	lockfileFailImmediately = 0x00000001
+	lockfileExclusiveLock   = 0x00000002
+
+	errorLockViolation syscall.Errno = 33
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+// tryLock takes an exclusive LockFileEx lock on the first byte without
+// blocking
+func tryLock(file *os.File) error {
+	var overlapped syscall.Overlapped
+	ret, _, err := procLockFileEx.Call(
+		file.Fd(),
+		lockfileExclusiveLock|lockfileFailImmediately,
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)),
+	)
+	if ret != 0 {
+		return nil
+	}
+	if err == errorLockViolation {
+		return errLocked
+	}
+	return err
+}
+
+func unlock(file *os.File) error {
+	var overlapped syscall.Overlapped
+	ret, _, err := procUnlockFileEx.Call(
+		file.Fd(),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)),
+	)
+	if ret == 0 {
+		return err
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 )
 
 // EnsureRuntime checks and installs Node.js runtime if needed
@@ -20,34 +21,116 @@ func EnsureRuntime() (string, error) {
 	nodePath := GetNodeBinaryPath(platform)
 
 	// Check if already installed
+	if nodeWorks(nodePath) {
+		return nodePath, nil
+	}
+
+	// Another process may be installing right now; install under the
+	// runtime lock and check again once it is held
+	err = withRuntimeLock(func() error {
+		return installRuntime(platform, nodePath)
+	})
+	if err != nil {
+		return "", err
+	}
+	return nodePath, nil
+}
+
+// nodeWorks reports whether the node executable at nodePath runs
+func nodeWorks(nodePath string) bool {
+	if _, err := os.Stat(nodePath); err != nil {
+		return false
+	}
+	// Node.js already installed, verify it works silently
+	return exec.Command(nodePath, "--version").Run() == nil
+}
+
+// installRuntime performs the first-time setup of EnsureRuntime; the caller
+// holds the runtime lock
+func installRuntime(platform *PlatformInfo, nodePath string) error {
+	if nodeWorks(nodePath) {
+		// Installed by another process while this one waited for the lock
+		return nil
+	}
 	if _, err := os.Stat(nodePath); err == nil {
-		// Node.js already installed, verify it works silently
-		cmd := exec.Command(nodePath, "--version")
-		if err := cmd.Run(); err == nil {
-			// Verification passed, return immediately
-			return nodePath, nil
-		}
 		// If verification fails, continue with reinstallation
-		fmt.Printf("⚠ Existing Node.js installation appears corrupted, reinstalling...\n")
+		Progress().Warn(StageSetup, "existing Node.js installation appears corrupted, reinstalling...")
+	}
+
+	// Install from an offline bundle when one is configured (no network access)
+	if bundlePath := os.Getenv(RuntimeBundleEnv); bundlePath != "" {
+		Progress().Header(StageBundleInstall, "Setting up webauto runtime from bundle %s...", bundlePath)
+		if _, err := installBundle(bundlePath, os.Getenv(RuntimeBundleSHA256Env)); err != nil {
+			return fmt.Errorf("failed to install runtime bundle: %w", err)
+		}
+		Progress().Complete(StageBundleInstall, "Setup complete!")
+		return nil
 	}
 
 	// Not installed, perform first-time setup
-	fmt.Printf("\n📦 Setting up webauto runtime (one-time setup)...\n")
-	fmt.Printf("   ▸ Detecting platform: %s %s\n", platform.OS, platform.Arch)
+	Progress().Header(StageSetup, "Setting up webauto runtime (one-time setup)...")
+	Progress().Step(StageSetup, "Detecting platform: %s %s", platform.OS, platform.Arch)
+
+	archiveSHA256, err := InstallNode(platform)
+	if err != nil {
+		return err
+	}
+
+	// Install Playwright
+	if err := InstallPlaywright(nodePath); err != nil {
+		return fmt.Errorf("failed to install Playwright: %w", err)
+	}
 
+	// Install Playwright browsers
+	if err := InstallPlaywrightBrowsers(nodePath); err != nil {
+		return fmt.Errorf("failed to install Playwright browsers: %w", err)
+	}
+
+	// Record what was installed and how it was verified
+	if err := UpdateRuntimeManifest(func(manifest *RuntimeManifest) {
+		manifest.Source = SourceDownload
+		manifest.InstalledAt = time.Now().UTC()
+		manifest.BundlePath, manifest.BundleSHA256, manifest.BundleVerified = "", "", false
+		recordNode(manifest, platform, archiveSHA256)
+		recordPlaywright(manifest)
+	}); err != nil {
+		Progress().Warn(StageSetup, "%v", err)
+	}
+
+	Progress().Complete(StageSetup, "Setup complete!")
+	return nil
+}
+
+// InstallNode downloads the pinned Node.js release, verifies it against the
+// release's SHASUMS256.txt and extracts it into the runtime directory.
+// Returns the SHA-256 of the downloaded archive.
+func InstallNode(platform *PlatformInfo) (string, error) {
 	// Create runtime directory
 	runtimeDir := GetRuntimeDir()
 	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create runtime directory: %w", err)
 	}
 
+	if err := CheckDiskSpace(runtimeDir, nodeInstallBytes); err != nil {
+		return "", err
+	}
+
 	// Download Node.js
 	archivePath := filepath.Join(runtimeDir, platform.NodeDirName+platform.ArchiveExt)
-	fmt.Printf("   ▸ Downloading Node.js %s (~30MB)...\n", NodeVersion)
+	Progress().Step(StageDownload, "Downloading Node.js %s (~30MB)...", NodeVersion)
 
 	if err := DownloadFile(platform.DownloadURL, archivePath, "     "); err != nil {
-		return "", fmt.Errorf("failed to download Node.js: %w\n\nSuggestions:\n   1. Check your internet connection\n   2. Retry: oa webauto browser-launch\n   3. Manual setup: export PLAYWRIGHT_NODE_PATH=/path/to/node", err)
+		return "", fmt.Errorf("failed to download Node.js: %w\n\nSuggestions:\n   1. Check your internet connection\n   2. Retry: oa webauto browser-launch\n   3. Manual setup: export PLAYWRIGHT_NODE_PATH=/path/to/node\n   4. Offline hosts: export %s=/path/to/webauto-runtime.tar.gz\n   5. Corporate networks: set HTTPS_PROXY, %s (internal mirror) or %s (private CA)", err, RuntimeBundleEnv, NodeMirrorEnv, CABundleEnv)
+	}
+
+	// Verify the archive against the release's published checksums
+	Progress().Step(StageVerify, "Verifying checksum...")
+	archiveSHA256, err := VerifyNodeArchive(platform, archivePath)
+	if err != nil {
+		os.Remove(archivePath)
+		return "", fmt.Errorf("Node.js archive verification failed: %w", err)
 	}
+	Progress().Done(StageVerify, "SHA-256 matches SHASUMS256.txt")
 
 	// Extract archive
 	if err := extractArchive(archivePath, runtimeDir, platform.ArchiveExt); err != nil {
@@ -56,26 +139,15 @@ func EnsureRuntime() (string, error) {
 
 	// Clean up archive
 	if err := CleanupArchive(archivePath); err != nil {
-		fmt.Printf("   ⚠ Warning: failed to cleanup archive: %v\n", err)
+		Progress().Warn(StageSetup, "failed to cleanup archive: %v", err)
 	}
 
 	// Verify Node.js installation
-	if err := VerifyNodeInstallation(nodePath); err != nil {
+	if err := VerifyNodeInstallation(GetNodeBinaryPath(platform)); err != nil {
 		return "", fmt.Errorf("Node.js installation verification failed: %w", err)
 	}
 
-	// Install Playwright
-	if err := InstallPlaywright(nodePath); err != nil {
-		return "", fmt.Errorf("failed to install Playwright: %w", err)
-	}
-
-	// Install Playwright browsers
-	if err := InstallPlaywrightBrowsers(nodePath); err != nil {
-		return "", fmt.Errorf("failed to install Playwright browsers: %w", err)
-	}
-
-	fmt.Printf("✓ Setup complete!\n\n")
-	return nodePath, nil
+	return archiveSHA256, nil
 }
 
 // extractArchive extracts archive based on file extension
@@ -0,0 +1,146 @@
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SupportedBrowsers lists the Playwright browser types webauto can launch
+var SupportedBrowsers = []string{"chromium", "firefox", "webkit"}
+
+// IsSupportedBrowser reports whether browserType is a known Playwright browser
+func IsSupportedBrowser(browserType string) bool {
+	for _, name := range SupportedBrowsers {
+		if name == browserType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBrowserInstalled checks whether Playwright has finished installing the
+// revision of the given browser the installed Playwright expects. Playwright
+// keeps each browser in a "<type>-<revision>" directory and writes
+// INSTALLATION_COMPLETE last. When Playwright's browsers.json cannot be read,
+// any complete build of the browser counts.
+func IsBrowserInstalled(browserType string) bool {
+	if revision, ok := expectedBrowserRevisions()[browserType]; ok {
+		return buildComplete(browserType + "-" + revision)
+	}
+
+	entries, err := os.ReadDir(GetBrowsersDir())
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), browserType+"-") && buildComplete(entry.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildComplete reports whether a browser build directory finished installing
+func buildComplete(dir string) bool {
+	_, err := os.Stat(filepath.Join(GetBrowsersDir(), dir, "INSTALLATION_COMPLETE"))
+	return err == nil
+}
+
+// InstallBrowser downloads one Playwright browser. With withDeps the
+// installer also installs the browser's system libraries, which needs root
+// (or sudo) on Linux. Installer output is written to out.
+func InstallBrowser(nodeExePath, browserType string, withDeps bool, out io.Writer) error {
+	return withRuntimeLock(func() error {
+		return installBrowser(nodeExePath, browserType, withDeps, out)
+	})
+}
+
+// installBrowser implements InstallBrowser; the caller holds the runtime lock
+func installBrowser(nodeExePath, browserType string, withDeps bool, out io.Writer) error {
+	if !IsSupportedBrowser(browserType) {
+		return fmt.Errorf("unsupported browser type: %s (supported: %s)", browserType, strings.Join(SupportedBrowsers, ", "))
+	}
+
+	browsersDir := GetBrowsersDir()
+	nodeModulesDir := GetNodeModulesDir()
+
+	// Ensure browsers directory exists
+	if err := os.MkdirAll(browsersDir, 0755); err != nil {
+		return fmt.Errorf("failed to create browsers directory: %w", err)
+	}
+	if err := CheckDiskSpace(browsersDir, browserInstallBytes); err != nil {
+		return err
+	}
+
+	npxPath, err := findNodeTool(nodeExePath, "npx")
+	if err != nil {
+		return err
+	}
+
+	// Run npx playwright install <type>
+	args := []string{"playwright", "install", browserType}
+	if withDeps {
+		args = append(args, "--with-deps")
+	}
+	cmd := exec.Command(npxPath, args...)
+	cmd.Dir = filepath.Dir(nodeModulesDir)
+	cmd.Env = toolEnv()
+
+	// Show output in real-time for long-running browser download
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("playwright install %s failed: %w", browserType, err)
+	}
+	return nil
+}
+
+// InstallBrowserWithProgress installs one browser, reporting the step and
+// the installer output through the global progress reporter
+func InstallBrowserWithProgress(nodeExePath, browserType string, withDeps bool) error {
+	return withRuntimeLock(func() error {
+		return installBrowserWithProgress(nodeExePath, browserType, withDeps)
+	})
+}
+
+// installBrowserWithProgress implements InstallBrowserWithProgress; the
+// caller holds the runtime lock
+func installBrowserWithProgress(nodeExePath, browserType string, withDeps bool) error {
+	Progress().Step(StageBrowserInstall, "Installing Playwright %s browser...", browserType)
+
+	out := Progress().Output(StageBrowserInstall)
+	err := installBrowser(nodeExePath, browserType, withDeps, out)
+	out.Close()
+	if err != nil {
+		return err
+	}
+
+	Progress().Done(StageBrowserInstall, "%s browser installed", browserType)
+	return nil
+}
+
+// findNodeTool locates npm/npx next to the node executable, falling back to
+// PATH when node itself was resolved from PATH
+func findNodeTool(nodeExePath, name string) (string, error) {
+	if filepath.Dir(nodeExePath) == "." {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+		return "", fmt.Errorf("%s not found in PATH", name)
+	}
+
+	toolPath := filepath.Join(filepath.Dir(nodeExePath), name)
+	if _, err := os.Stat(toolPath); os.IsNotExist(err) {
+		// On Windows, npm/npx are .cmd scripts
+		toolPath = filepath.Join(filepath.Dir(nodeExePath), name+".cmd")
+		if _, err := os.Stat(toolPath); os.IsNotExist(err) {
+			return "", fmt.Errorf("%s not found in Node.js installation", name)
+		}
+	}
+	return toolPath, nil
+}
@@ -0,0 +1,466 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// RuntimeBundleEnv names a bundle that EnsureRuntime installs from
+	// instead of downloading Node.js, Playwright and browsers
+	RuntimeBundleEnv = "WEBAUTO_RUNTIME_BUNDLE"
+
+	// RuntimeBundleSHA256Env is the expected SHA-256 of the bundle named by
+	// WEBAUTO_RUNTIME_BUNDLE (as printed by "runtime bundle")
+	RuntimeBundleSHA256Env = "WEBAUTO_RUNTIME_BUNDLE_SHA256"
+
+	// bundleManifestName is the first entry of every runtime bundle
+	bundleManifestName = "bundle.json"
+
+	// bundleFormatVersion is bumped when the bundle layout changes
+	bundleFormatVersion = 1
+)
+
+// bundleFiles are the cache entries besides runtime/ and browsers/ that make
+// up an installed runtime
+var bundleFiles = []string{"node_modules", "package.json", "package-lock.json", "runtime.json"}
+
+// BundleManifest describes the contents of a runtime bundle
+type BundleManifest struct {
+	FormatVersion     int       `json:"format_version"`
+	OS                string    `json:"os"`
+	Arch              string    `json:"arch"`
+	NodeVersion       string    `json:"node_version"`
+	PlaywrightVersion string    `json:"playwright_version"`
+	Browsers          []string  `json:"browsers"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// CreateBundle packages the installed runtime (Node.js, node_modules and the
+// selected browsers) into a .tar.gz archive at outputPath. With no browser
+// types given, every installed browser is included.
+func CreateBundle(outputPath string, browserTypes []string) (*BundleManifest, error) {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	if !IsRuntimeInstalled() {
+		return nil, fmt.Errorf("runtime is not installed; run any webauto command once to set it up")
+	}
+
+	if len(browserTypes) == 0 {
+		for _, name := range SupportedBrowsers {
+			if IsBrowserInstalled(name) {
+				browserTypes = append(browserTypes, name)
+			}
+		}
+	}
+	for _, name := range browserTypes {
+		if !IsBrowserInstalled(name) {
+			return nil, fmt.Errorf("browser %s is not installed; run: webauto runtime install-browser %s", name, name)
+		}
+	}
+
+	manifest := &BundleManifest{
+		FormatVersion:     bundleFormatVersion,
+		OS:                platform.OS,
+		Arch:              platform.Arch,
+		NodeVersion:       NodeVersion,
+		PlaywrightVersion: InstalledPlaywrightVersion(),
+		Browsers:          browserTypes,
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	// Write to a temporary file so a failed run leaves no partial bundle
+	tmpPath := outputPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+
+	if err := writeBundle(tw, manifest, platform); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gzw.Close(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeBundle writes the manifest followed by the runtime files
+func writeBundle(tw *tar.Writer, manifest *BundleManifest, platform *PlatformInfo) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+	header := &tar.Header{
+		Name:    bundleManifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+
+	cacheDir := GetCacheDir()
+	roots := []string{filepath.Join("runtime", platform.NodeDirName)}
+	for _, name := range bundleFiles {
+		if _, err := os.Lstat(filepath.Join(cacheDir, name)); err == nil {
+			roots = append(roots, name)
+		}
+	}
+
+	// Browsers not selected are left out, shared helpers (ffmpeg, ...) are kept
+	entries, err := os.ReadDir(GetBrowsersDir())
+	if err != nil {
+		return fmt.Errorf("failed to read browsers directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !excludedBrowserDir(entry.Name(), manifest.Browsers) {
+			roots = append(roots, filepath.Join("browsers", entry.Name()))
+		}
+	}
+
+	for _, root := range roots {
+		if err := addToBundle(tw, cacheDir, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excludedBrowserDir reports whether a browsers/ entry belongs to a browser
+// type that was not selected for the bundle
+func excludedBrowserDir(name string, selected []string) bool {
+	for _, browserType := range SupportedBrowsers {
+		if !strings.HasPrefix(name, browserType+"-") && !strings.HasPrefix(name, browserType+"_") {
+			continue
+		}
+		for _, keep := range selected {
+			if keep == browserType {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// addToBundle adds root (relative to baseDir) and everything below it to the
+// archive. Symlinks are stored as links, not followed.
+func addToBundle(tw *tar.Writer, baseDir, root string) error {
+	return filepath.Walk(filepath.Join(baseDir, root), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return fmt.Errorf("failed to read symlink %s: %w", path, err)
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", rel, err)
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", rel, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", rel, err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(tw, file); err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", rel, err)
+		}
+		return nil
+	})
+}
+
+// InstallBundle installs the runtime from a bundle created by CreateBundle
+// without any network access. When expectedSHA256 is set the bundle must
+// match it. The bundle is extracted into a staging directory first, so a
+// broken archive leaves the current runtime untouched.
+func InstallBundle(bundlePath, expectedSHA256 string) (*BundleManifest, error) {
+	var manifest *BundleManifest
+	err := withRuntimeLock(func() error {
+		var err error
+		manifest, err = installBundle(bundlePath, expectedSHA256)
+		return err
+	})
+	return manifest, err
+}
+
+// installBundle implements InstallBundle; the caller holds the runtime lock
+func installBundle(bundlePath, expectedSHA256 string) (*BundleManifest, error) {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	ext, err := bundleArchiveExt(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	bundleSHA256, err := FileSHA256(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	if expectedSHA256 != "" && !strings.EqualFold(bundleSHA256, expectedSHA256) {
+		return nil, fmt.Errorf("bundle checksum mismatch: expected %s, got %s", expectedSHA256, bundleSHA256)
+	}
+
+	manifest, err := ReadBundleManifest(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion != bundleFormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d (expected %d)", manifest.FormatVersion, bundleFormatVersion)
+	}
+	if manifest.OS != platform.OS || manifest.Arch != platform.Arch {
+		return nil, fmt.Errorf("bundle is for %s-%s, this host is %s-%s", manifest.OS, manifest.Arch, platform.OS, platform.Arch)
+	}
+	if manifest.NodeVersion != NodeVersion {
+		return nil, fmt.Errorf("bundle contains Node.js %s, this webauto build expects %s", manifest.NodeVersion, NodeVersion)
+	}
+	if manifest.PlaywrightVersion != PlaywrightVersion {
+		return nil, fmt.Errorf("bundle contains Playwright %s, this webauto build expects %s; create the bundle with the same webauto release", manifest.PlaywrightVersion, PlaywrightVersion)
+	}
+
+	cacheDir := GetCacheDir()
+	stagingDir := filepath.Join(cacheDir, ".bundle-staging")
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return nil, fmt.Errorf("failed to clear staging directory: %w", err)
+	}
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if err := extractArchive(bundlePath, stagingDir, ext); err != nil {
+		return nil, fmt.Errorf("failed to extract bundle: %w", err)
+	}
+
+	// Check the bundled Node.js before anything installed is touched
+	stagedNode := filepath.Join(stagingDir, "runtime", platform.NodeDirName, platform.BinaryPath)
+	if err := VerifyNodeInstallation(stagedNode); err != nil {
+		return nil, fmt.Errorf("bundled Node.js verification failed: %w", err)
+	}
+
+	// Node.js and node_modules are replaced as a whole; browsers are merged
+	// so browsers installed outside the bundle are kept
+	names := append([]string{filepath.Join("runtime", platform.NodeDirName)}, bundleFiles...)
+	entries, err := os.ReadDir(filepath.Join(stagingDir, "browsers"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read bundled browsers: %w", err)
+	}
+	for _, entry := range entries {
+		names = append(names, filepath.Join("browsers", entry.Name()))
+	}
+	if err := swapStaged(stagingDir, cacheDir, names); err != nil {
+		return nil, err
+	}
+
+	// Keep the Node.js verification done on the machine that built the bundle
+	runtimeManifest := &RuntimeManifest{}
+	if bundled, err := ReadRuntimeManifest(); err == nil {
+		runtimeManifest = bundled
+	}
+	runtimeManifest.Source = SourceBundle
+	runtimeManifest.InstalledAt = time.Now().UTC()
+	runtimeManifest.NodeVersion = manifest.NodeVersion
+	runtimeManifest.PlaywrightVersion = InstalledPlaywrightVersion()
+	runtimeManifest.BundlePath = bundlePath
+	runtimeManifest.BundleSHA256 = bundleSHA256
+	runtimeManifest.BundleVerified = expectedSHA256 != ""
+	if err := writeRuntimeManifest(runtimeManifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// swapStaged renames each bundled stagingDir/name over cacheDir/name. The
+// entries they replace are kept aside until every rename succeeded and are
+// put back when one fails, so a failed install leaves the runtime as it was.
+func swapStaged(stagingDir, cacheDir string, names []string) (err error) {
+	type swapped struct{ target, backup string }
+	var done []swapped
+
+	defer func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			if err != nil {
+				_ = os.RemoveAll(done[i].target)
+				if done[i].backup != "" {
+					_ = os.Rename(done[i].backup, done[i].target)
+				}
+			} else if done[i].backup != "" {
+				_ = os.RemoveAll(done[i].backup)
+			}
+		}
+	}()
+
+	for _, name := range names {
+		source := filepath.Join(stagingDir, name)
+		if _, err := os.Lstat(source); os.IsNotExist(err) {
+			continue
+		}
+
+		target := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", target, err)
+		}
+
+		step := swapped{target: target}
+		if _, err := os.Lstat(target); err == nil {
+			step.backup = target + ".old"
+			if err := os.RemoveAll(step.backup); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", step.backup, err)
+			}
+			if err := os.Rename(target, step.backup); err != nil {
+				return fmt.Errorf("failed to move aside %s: %w", target, err)
+			}
+		}
+		if err := os.Rename(source, target); err != nil {
+			if step.backup != "" {
+				_ = os.Rename(step.backup, target)
+			}
+			return fmt.Errorf("failed to install %s: %w", name, err)
+		}
+		done = append(done, step)
+	}
+	return nil
+}
+
+// ReadBundleManifest reads the manifest stored as the first bundle entry
+func ReadBundleManifest(bundlePath string) (*BundleManifest, error) {
+	ext, err := bundleArchiveExt(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	var reader io.Reader
+	switch ext {
+	case ".tar.gz":
+		gzr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		defer gzr.Close()
+		reader = gzr
+	case ".tar.xz":
+		xzCmd := exec.Command("xz", "-d", "-c")
+		xzCmd.Stdin = file
+		xzOutput, err := xzCmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xz stdout pipe: %w", err)
+		}
+		if err := xzCmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start xz decompression: %w", err)
+		}
+		defer func() {
+			_ = xzCmd.Process.Kill()
+			_ = xzCmd.Wait()
+		}()
+		reader = xzOutput
+	}
+
+	tr := tar.NewReader(reader)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if header.Name != bundleManifestName {
+		return nil, fmt.Errorf("not a webauto runtime bundle: first entry is %s, expected %s", header.Name, bundleManifestName)
+	}
+
+	var manifest BundleManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// bundleArchiveExt maps a bundle file name to the extractArchive format
+func bundleArchiveExt(bundlePath string) (string, error) {
+	switch {
+	case strings.HasSuffix(bundlePath, ".tar.gz"), strings.HasSuffix(bundlePath, ".tgz"):
+		return ".tar.gz", nil
+	case strings.HasSuffix(bundlePath, ".tar.xz"):
+		return ".tar.xz", nil
+	default:
+		return "", fmt.Errorf("unsupported bundle format: %s (expected .tar.gz or .tar.xz)", filepath.Base(bundlePath))
+	}
+}
+
+// InstalledPlaywrightVersion returns the version of the installed Playwright
+// library, or an empty string when it is not installed
+func InstalledPlaywrightVersion() string {
+	data, err := os.ReadFile(filepath.Join(GetNodeModulesDir(), "playwright", "package.json"))
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Version
+}
@@ -0,0 +1,173 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSwapStaged(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		wantErr bool
+		want    map[string]string
+	}{
+		{
+			name:  "replaces and adds entries",
+			names: []string{"node_modules", "package.json", "browsers/chromium-1"},
+			want: map[string]string{
+				"node_modules/mod.js":     "new",
+				"package.json":            "new",
+				"browsers/chromium-1/bin": "new",
+				"browsers/firefox-1/bin":  "old",
+				"package-lock.json":       "old",
+			},
+		},
+		{
+			name:  "skips entries missing from the bundle",
+			names: []string{"package-lock.json", "package.json"},
+			want: map[string]string{
+				"package-lock.json": "old",
+				"package.json":      "new",
+			},
+		},
+		{
+			name:    "rolls back when a rename fails",
+			names:   []string{"node_modules", "package.json", "blocked/entry"},
+			wantErr: true,
+			want: map[string]string{
+				"node_modules/mod.js": "old",
+				"package.json":        "old",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staging := t.TempDir()
+			cache := t.TempDir()
+
+			writeTestFile(t, filepath.Join(staging, "node_modules", "mod.js"), "new")
+			writeTestFile(t, filepath.Join(staging, "package.json"), "new")
+			writeTestFile(t, filepath.Join(staging, "browsers", "chromium-1", "bin"), "new")
+			writeTestFile(t, filepath.Join(staging, "blocked", "entry"), "new")
+
+			writeTestFile(t, filepath.Join(cache, "node_modules", "mod.js"), "old")
+			writeTestFile(t, filepath.Join(cache, "package.json"), "old")
+			writeTestFile(t, filepath.Join(cache, "package-lock.json"), "old")
+			writeTestFile(t, filepath.Join(cache, "browsers", "firefox-1", "bin"), "old")
+			// A file where a directory is needed makes the last entry fail
+			writeTestFile(t, filepath.Join(cache, "blocked"), "old")
+
+			err := swapStaged(staging, cache, tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("swapStaged() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for name, want := range tt.want {
+				if got := readTestFile(t, filepath.Join(cache, name)); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			for _, name := range tt.names {
+				if _, err := os.Lstat(filepath.Join(cache, name+".old")); err == nil {
+					t.Errorf("backup of %s left behind", name)
+				}
+			}
+		})
+	}
+}
+
+// writeManifestBundle writes a bundle holding only a manifest
+func writeManifestBundle(t *testing.T, manifest *BundleManifest) string {
+	t.Helper()
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+	if err := tw.WriteHeader(&tar.Header{Name: bundleManifestName, Mode: 0644, Size: int64(len(data))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInstallBundleChecksVersions(t *testing.T) {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name       string
+		node       string
+		playwright string
+		wantErr    string
+	}{
+		{"other Node.js", "v0.0.1", PlaywrightVersion, "Node.js v0.0.1"},
+		{"other Playwright", NodeVersion, "0.0.1", "Playwright 0.0.1"},
+		// Versions match, so the install goes on and stops at the missing Node.js
+		{"matching versions", NodeVersion, PlaywrightVersion, "bundled Node.js verification failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("LOCALAPPDATA", t.TempDir())
+
+			bundle := writeManifestBundle(t, &BundleManifest{
+				FormatVersion:     bundleFormatVersion,
+				OS:                platform.OS,
+				Arch:              platform.Arch,
+				NodeVersion:       tt.node,
+				PlaywrightVersion: tt.playwright,
+			})
+
+			_, err := InstallBundle(bundle, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("InstallBundle() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package bootstrap
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// freeDiskSpace returns the bytes available to the current user on the
+// volume holding dir
+func freeDiskSpace(dir string) (int64, error) {
+	path, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var freeBytesAvailable uint64
+	ret, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(path)),
+		uintptr(unsafe.Pointer(&freeBytesAvailable)),
+		0,
+		0,
+	)
+	if ret == 0 {
+		return 0, err
+	}
+	return int64(freeBytesAvailable), nil
+}
@@ -7,8 +7,6 @@ import (
 	"os"
 	"path/filepath"
 	"time"
-
-	"github.com/schollz/progressbar/v3"
 )
 
 const (
@@ -17,6 +15,13 @@ const (
 
 	// RetryDelay is the delay between retry attempts
 	RetryDelay = 2 * time.Second
+
+	// nodeInstallBytes is the space needed to download and extract Node.js
+	nodeInstallBytes = 250 << 20
+
+	// browserInstallBytes is the space needed to install one browser type
+	// (Chromium with its headless shell is the largest)
+	browserInstallBytes = 600 << 20
 )
 
 // DownloadFile downloads a file from URL to destPath with progress bar
@@ -25,7 +30,7 @@ func DownloadFile(url, destPath string, description string) error {
 
 	for attempt := 1; attempt <= MaxRetries; attempt++ {
 		if attempt > 1 {
-			fmt.Printf("   ▸ Retry attempt %d/%d...\n", attempt, MaxRetries)
+			Progress().Retry(StageDownload, "Retry attempt %d/%d...", attempt, MaxRetries)
 			time.Sleep(RetryDelay)
 		}
 
@@ -40,8 +45,17 @@ func DownloadFile(url, destPath string, description string) error {
 	return fmt.Errorf("failed after %d attempts: %w", MaxRetries, lastErr)
 }
 
-// downloadFileAttempt performs a single download attempt
+// downloadFileAttempt performs a single download attempt. Data is written to
+// destPath+".part" and renamed into place once complete; a .part file left by
+// an earlier attempt is resumed with an HTTP Range request.
 func downloadFileAttempt(url, destPath, description string) error {
+	partPath := destPath + ".part"
+
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
 	// Create HTTP request
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -50,57 +64,112 @@ func downloadFileAttempt(url, destPath, description string) error {
 
 	// Set User-Agent to avoid potential blocking
 	req.Header.Set("User-Agent", "webauto/1.0")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client, err := downloadClient()
+	if err != nil {
+		return err
+	}
 
 	// Execute request
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to download: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check response status
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && contentRangeStart(resp) == offset:
+		flags |= os.O_APPEND
+		Progress().Step(StageDownload, "Resuming at %d bytes", offset)
+	case resp.StatusCode == http.StatusOK:
+		// Server ignored the Range header: start over
+		offset = 0
+		flags |= os.O_TRUNC
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable || resp.StatusCode == http.StatusPartialContent:
+		// The .part file does not match the file on the server
+		os.Remove(partPath)
+		return fmt.Errorf("cannot resume partial download (%s), restarting", resp.Status)
+	default:
 		return fmt.Errorf("bad status: %s", resp.Status)
 	}
 
-	// Create destination file
-	out, err := os.Create(destPath)
+	if resp.ContentLength > 0 {
+		if err := CheckDiskSpace(filepath.Dir(destPath), resp.ContentLength); err != nil {
+			return err
+		}
+	}
+
+	// Open destination file
+	out, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
 	defer out.Close()
 
-	// Create progress bar
-	bar := progressbar.DefaultBytes(
-		resp.ContentLength,
-		description,
-	)
+	// Report progress
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	bar := Progress().Bytes(StageDownload, description, offset, total)
+	defer bar.Close()
 
-	// Download with progress tracking
+	// Download with progress tracking; a partial .part file is kept so the
+	// next attempt can resume it
 	_, err = io.Copy(io.MultiWriter(out, bar), resp.Body)
 	if err != nil {
-		os.Remove(destPath) // Clean up partial download
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := out.Close(); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	fmt.Println() // New line after progress bar
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
 	return nil
 }
 
-// CheckDiskSpace checks if there is enough disk space available
-func CheckDiskSpace(path string, requiredBytes int64) error {
-	// Check if path exists
-	if _, err := os.Stat(path); err != nil {
-		// If path doesn't exist, check parent directory
-		parent := filepath.Dir(path)
-		if _, err = os.Stat(parent); err != nil {
-			return fmt.Errorf("failed to check disk space: %w", err)
+// contentRangeStart returns the first byte offset of a 206 response, or -1
+func contentRangeStart(resp *http.Response) int64 {
+	var start, end, size int64
+	if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &size); err != nil {
+		// "bytes start-end/*" when the size is unknown
+		if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d", &start, &end); err != nil {
+			return -1
 		}
 	}
+	return start
+}
 
-	// Note: os.FileInfo doesn't provide disk space info in Go's standard library
-	// This is a simplified check - actual implementation would use syscall for accurate disk space check
-	// For now, we'll skip the actual check and just ensure directory is writable
+// CheckDiskSpace checks that the filesystem holding path has at least
+// requiredBytes free. path does not need to exist yet; its closest existing
+// parent is checked. Platforms without a free-space query pass the check.
+func CheckDiskSpace(path string, requiredBytes int64) error {
+	// Walk up to a directory that exists
+	dir := path
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("failed to check disk space: no existing parent of %s", path)
+		}
+		dir = parent
+	}
 
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check disk space: %w", err)
+	}
+	if free >= 0 && free < requiredBytes {
+		return fmt.Errorf("not enough disk space in %s: %d MB free, %d MB required", dir, free>>20, (requiredBytes+(1<<20)-1)>>20)
+	}
 	return nil
 }
@@ -0,0 +1,124 @@
+package bootstrap
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContentRangeStart(t *testing.T) {
+	tests := []struct {
+		header string
+		want   int64
+	}{
+		{"bytes 100-199/200", 100},
+		{"bytes 0-99/100", 0},
+		{"bytes 512-1023/*", 512},
+		{"", -1},
+		{"bytes */200", -1},
+		{"items 1-2/3", -1},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			resp.Header.Set("Content-Range", tt.header)
+		}
+		if got := contentRangeStart(resp); got != tt.want {
+			t.Errorf("contentRangeStart(%q) = %d, want %d", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestNodeMirrorURL(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", NodeBaseURL},
+		{"   ", NodeBaseURL},
+		{"https://mirror.example.com/node/", "https://mirror.example.com/node"},
+		{" https://mirror.example.com/node ", "https://mirror.example.com/node"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(NodeMirrorEnv, tt.env)
+		if got := NodeMirrorURL(); got != tt.want {
+			t.Errorf("NodeMirrorURL() with %q = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadFileAttemptResumes(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "node.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		part      []byte
+		wantRange string
+	}{
+		{"fresh download", nil, ""},
+		{"resume matching part", content[:400], "bytes=400-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges = nil
+			destPath := filepath.Join(t.TempDir(), "node.tar.gz")
+			if tt.part != nil {
+				if err := os.WriteFile(destPath+".part", tt.part, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := downloadFileAttempt(server.URL, destPath, "test"); err != nil {
+				t.Fatalf("downloadFileAttempt() error = %v", err)
+			}
+
+			got, err := os.ReadFile(destPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("downloaded %d bytes, want the %d bytes served", len(got), len(content))
+			}
+			if _, err := os.Stat(destPath + ".part"); !os.IsNotExist(err) {
+				t.Errorf(".part file left behind")
+			}
+			if len(ranges) != 1 || ranges[0] != tt.wantRange {
+				t.Errorf("Range headers = %q, want [%q]", ranges, tt.wantRange)
+			}
+		})
+	}
+}
+
+func TestDownloadFileAttemptDropsOversizedPart(t *testing.T) {
+	content := []byte("small file")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "node.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	destPath := filepath.Join(t.TempDir(), "node.tar.gz")
+	if err := os.WriteFile(destPath+".part", []byte(strings.Repeat("x", 100)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := downloadFileAttempt(server.URL, destPath, "test"); err == nil {
+		t.Fatal("downloadFileAttempt() succeeded with a .part file larger than the download")
+	}
+	if _, err := os.Stat(destPath + ".part"); !os.IsNotExist(err) {
+		t.Errorf("stale .part file kept; the next attempt would fail again")
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 // ExtractTarGz extracts a .tar.gz archive to destDir
 func ExtractTarGz(archivePath, destDir string) error {
-	fmt.Printf("   ▸ Extracting Node.js runtime...\n")
+	Progress().Step(StageExtract, "Extracting %s...", filepath.Base(archivePath))
 
 	// Open archive file
 	file, err := os.Open(archivePath)
@@ -49,6 +49,9 @@ func ExtractTarGz(archivePath, destDir string) error {
 		if !strings.HasPrefix(target, filepath.Clean(destDir)+string(os.PathSeparator)) {
 			return fmt.Errorf("illegal file path: %s", header.Name)
 		}
+		if err := checkExtractPath(destDir, target); err != nil {
+			return fmt.Errorf("illegal file path: %s: %w", header.Name, err)
+		}
 
 		// Handle different file types
 		switch header.Typeflag {
@@ -65,21 +68,26 @@ func ExtractTarGz(archivePath, destDir string) error {
 			}
 
 		case tar.TypeSymlink:
+			// Reject links pointing outside destDir
+			if err := checkSymlinkTarget(destDir, target, header.Linkname); err != nil {
+				return fmt.Errorf("illegal symlink: %s -> %s: %w", header.Name, header.Linkname, err)
+			}
+
 			// Create symbolic link
 			if err := os.Symlink(header.Linkname, target); err != nil {
 				// Ignore symlink errors on Windows
 				if !os.IsExist(err) {
-					fmt.Printf("   ⚠ Warning: failed to create symlink %s: %v\n", target, err)
+					Progress().Warn(StageExtract, "failed to create symlink %s: %v", target, err)
 				}
 			}
 
 		default:
 			// Skip other types (devices, fifos, etc.)
-			fmt.Printf("   ⚠ Warning: skipping unsupported file type: %c in %s\n", header.Typeflag, header.Name)
+			Progress().Warn(StageExtract, "skipping unsupported file type: %c in %s", header.Typeflag, header.Name)
 		}
 	}
 
-	fmt.Printf("     ✓ Complete\n")
+	Progress().Done(StageExtract, "Complete")
 	return nil
 }
 
@@ -105,9 +113,80 @@ func extractFile(tr *tar.Reader, target string, mode os.FileMode) error {
 	return nil
 }
 
+// checkExtractPath rejects a target that would be written outside destDir
+// once symlinks extracted earlier are followed (e.g. "link/file" where link
+// points to a directory above destDir)
+func checkExtractPath(destDir, target string) error {
+	root, err := filepath.EvalSymlinks(destDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", destDir, err)
+	}
+
+	parent, err := resolveExisting(filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	if !isWithinDir(root, parent) {
+		return fmt.Errorf("resolves outside %s", destDir)
+	}
+	return nil
+}
+
+// checkSymlinkTarget rejects a symlink whose target resolves outside destDir.
+// Absolute targets are never allowed.
+func checkSymlinkTarget(destDir, target, linkname string) error {
+	if filepath.IsAbs(linkname) || filepath.VolumeName(linkname) != "" {
+		return fmt.Errorf("absolute link target")
+	}
+
+	root, err := filepath.EvalSymlinks(destDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", destDir, err)
+	}
+
+	parent, err := resolveExisting(filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	if !isWithinDir(root, filepath.Join(parent, linkname)) {
+		return fmt.Errorf("link target escapes %s", destDir)
+	}
+	return nil
+}
+
+// resolveExisting resolves symlinks in the longest existing prefix of p
+func resolveExisting(p string) (string, error) {
+	missing := ""
+	for {
+		if _, err := os.Lstat(p); err == nil {
+			resolved, err := filepath.EvalSymlinks(p)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve %s: %w", p, err)
+			}
+			return filepath.Join(resolved, missing), nil
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return filepath.Join(p, missing), nil
+		}
+		missing = filepath.Join(filepath.Base(p), missing)
+		p = parent
+	}
+}
+
+// isWithinDir reports whether p is root or below it
+func isWithinDir(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
 // ExtractZip extracts a .zip archive to destDir (for Windows)
 func ExtractZip(archivePath, destDir string) error {
-	fmt.Printf("   ▸ Extracting Node.js runtime...\n")
+	Progress().Step(StageExtract, "Extracting %s...", filepath.Base(archivePath))
 
 	// Open zip file
 	r, err := zip.OpenReader(archivePath)
@@ -125,24 +204,72 @@ func ExtractZip(archivePath, destDir string) error {
 		if !strings.HasPrefix(target, filepath.Clean(destDir)+string(os.PathSeparator)) {
 			return fmt.Errorf("illegal file path: %s", f.Name)
 		}
+		if err := checkExtractPath(destDir, target); err != nil {
+			return fmt.Errorf("illegal file path: %s: %w", f.Name, err)
+		}
 
-		if f.FileInfo().IsDir() {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
 			// Create directory
-			if err := os.MkdirAll(target, f.Mode()); err != nil {
+			if err := os.MkdirAll(target, mode.Perm()); err != nil {
 				return fmt.Errorf("failed to create directory %s: %w", target, err)
 			}
-		} else {
+
+		case mode&os.ModeSymlink != 0:
+			// The entry's content is the link target
+			linkname, err := readZipLink(f)
+			if err != nil {
+				return fmt.Errorf("failed to read symlink %s: %w", f.Name, err)
+			}
+
+			// Reject links pointing outside destDir
+			if err := checkSymlinkTarget(destDir, target, linkname); err != nil {
+				return fmt.Errorf("illegal symlink: %s -> %s: %w", f.Name, linkname, err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("failed to create parent directory: %w", err)
+			}
+			if err := os.Symlink(linkname, target); err != nil {
+				// Ignore symlink errors on Windows
+				if !os.IsExist(err) {
+					Progress().Warn(StageExtract, "failed to create symlink %s: %v", target, err)
+				}
+			}
+
+		case mode.IsRegular():
 			// Extract file
 			if err := extractZipFile(f, target); err != nil {
 				return fmt.Errorf("failed to extract file %s: %w", target, err)
 			}
+
+		default:
+			// Skip other types (devices, fifos, etc.)
+			Progress().Warn(StageExtract, "skipping unsupported file type %s in %s", mode.Type(), f.Name)
 		}
 	}
 
-	fmt.Printf("     ✓ Complete\n")
+	Progress().Done(StageExtract, "Complete")
 	return nil
 }
 
+// readZipLink reads the target of a symlink entry
+func readZipLink(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	// Link targets are short; anything larger is not a real link
+	data, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // extractZipFile extracts a single file from zip
 func extractZipFile(f *zip.File, target string) error {
 	// Ensure parent directory exists
@@ -158,7 +285,7 @@ func extractZipFile(f *zip.File, target string) error {
 	defer rc.Close()
 
 	// Create target file
-	outFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode())
+	outFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm())
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
@@ -0,0 +1,257 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsWithinDir(t *testing.T) {
+	root := filepath.FromSlash("/opt/webauto")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/opt/webauto", true},
+		{"/opt/webauto/node/bin", true},
+		{"/opt/webauto/..data", true},
+		{"/opt", false},
+		{"/opt/webauto-other", false},
+		{"/opt/webauto/../etc", false},
+	}
+
+	for _, tt := range tests {
+		if got := isWithinDir(root, filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("isWithinDir(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCheckExtractPath(t *testing.T) {
+	destDir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(destDir, "escape")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(destDir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("lib", filepath.Join(destDir, "inside")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		wantErr bool
+	}{
+		{"plain file", "file.txt", false},
+		{"new subdirectory", "a/b/c.txt", false},
+		{"through internal link", "inside/file.txt", false},
+		{"through escaping link", "escape/file.txt", true},
+		{"below escaping link", "escape/deep/file.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkExtractPath(destDir, filepath.Join(destDir, filepath.FromSlash(tt.target)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkExtractPath(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckSymlinkTarget(t *testing.T) {
+	destDir := t.TempDir()
+	tests := []struct {
+		name     string
+		target   string
+		linkname string
+		wantErr  bool
+	}{
+		{"sibling", "bin/node", "../lib/node", false},
+		{"same directory", "lib/libnode.so", "libnode.so.1", false},
+		{"climbs out", "bin/node", "../../etc/passwd", true},
+		{"absolute", "bin/node", "/usr/bin/node", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(destDir, filepath.FromSlash(tt.target))
+			err := checkSymlinkTarget(destDir, target, filepath.FromSlash(tt.linkname))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkSymlinkTarget(%q -> %q) error = %v, wantErr %v", tt.target, tt.linkname, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeTarGz builds a .tar.gz archive from headers, giving regular files a
+// short body
+func writeTarGz(t *testing.T, headers []*tar.Header) string {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "archive.tar.gz")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+	for _, header := range headers {
+		body := []byte("data")
+		if header.Typeflag == tar.TypeReg {
+			header.Size = int64(len(body))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return archivePath
+}
+
+func TestExtractTarGz(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*tar.Header
+		wantErr bool
+	}{
+		{"regular files", []*tar.Header{
+			{Name: "node/", Typeflag: tar.TypeDir, Mode: 0755},
+			{Name: "node/bin/node", Typeflag: tar.TypeReg, Mode: 0755},
+		}, false},
+		{"internal symlink", []*tar.Header{
+			{Name: "node/lib/real", Typeflag: tar.TypeReg, Mode: 0644},
+			{Name: "node/lib/link", Typeflag: tar.TypeSymlink, Linkname: "real"},
+		}, false},
+		{"dot-dot entry", []*tar.Header{
+			{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644},
+		}, true},
+		{"escaping symlink", []*tar.Header{
+			{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../"},
+		}, true},
+		{"write through symlink", []*tar.Header{
+			{Name: "up", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "up/../../evil", Typeflag: tar.TypeReg, Mode: 0644},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := writeTarGz(t, tt.headers)
+			destDir := t.TempDir()
+			err := ExtractTarGz(archivePath, destDir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExtractTarGz() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// zipEntry is one entry for writeZip; a symlink's body is its target
+type zipEntry struct {
+	name string
+	mode os.FileMode
+	body string
+}
+
+func writeZip(t *testing.T, entries []zipEntry) string {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "archive.zip")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Store}
+		header.SetMode(entry.mode)
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return archivePath
+}
+
+func TestExtractZip(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		wantErr bool
+	}{
+		{"regular files", []zipEntry{
+			{name: "node/", mode: os.ModeDir | 0755},
+			{name: "node/node.exe", mode: 0755, body: "data"},
+		}, false},
+		{"internal symlink", []zipEntry{
+			{name: "node/lib/real", mode: 0644, body: "data"},
+			{name: "node/lib/link", mode: os.ModeSymlink | 0777, body: "real"},
+		}, false},
+		{"dot-dot entry", []zipEntry{
+			{name: "../evil", mode: 0644, body: "data"},
+		}, true},
+		{"escaping symlink", []zipEntry{
+			{name: "link", mode: os.ModeSymlink | 0777, body: "../../"},
+		}, true},
+		{"absolute symlink", []zipEntry{
+			{name: "link", mode: os.ModeSymlink | 0777, body: "/etc"},
+		}, true},
+		{"write through symlink", []zipEntry{
+			{name: "up", mode: os.ModeSymlink | 0777, body: "."},
+			{name: "up/../../evil", mode: 0644, body: "data"},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := writeZip(t, tt.entries)
+			destDir := t.TempDir()
+			err := ExtractZip(archivePath, destDir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExtractZip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtractZipRejectsWriteThroughEscapingLink(t *testing.T) {
+	destDir := t.TempDir()
+	outside := t.TempDir()
+	// A link left by an earlier extraction that points outside destDir
+	if err := os.Symlink(outside, filepath.Join(destDir, "out")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	archivePath := writeZip(t, []zipEntry{{name: "out/evil", mode: 0644, body: "data"}})
+	if err := ExtractZip(archivePath, destDir); err == nil {
+		t.Fatal("ExtractZip() wrote through a link pointing outside destDir")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "evil")); err == nil {
+		t.Error("file was written outside destDir")
+	}
+}
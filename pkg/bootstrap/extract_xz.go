@@ -13,7 +13,7 @@ import (
 // ExtractTarXz extracts a .tar.xz archive to destDir (for Linux)
 // Uses system 'xz' command for decompression
 func ExtractTarXz(archivePath, destDir string) error {
-	fmt.Printf("   ▸ Extracting Node.js runtime...\n")
+	Progress().Step(StageExtract, "Extracting %s...", filepath.Base(archivePath))
 
 	// Check if xz is available
 	if _, err := exec.LookPath("xz"); err != nil {
@@ -51,6 +51,9 @@ func ExtractTarXz(archivePath, destDir string) error {
 		if !strings.HasPrefix(target, filepath.Clean(destDir)+string(os.PathSeparator)) {
 			return fmt.Errorf("illegal file path: %s", header.Name)
 		}
+		if err := checkExtractPath(destDir, target); err != nil {
+			return fmt.Errorf("illegal file path: %s: %w", header.Name, err)
+		}
 
 		// Handle different file types
 		switch header.Typeflag {
@@ -67,16 +70,21 @@ func ExtractTarXz(archivePath, destDir string) error {
 			}
 
 		case tar.TypeSymlink:
+			// Reject links pointing outside destDir
+			if err := checkSymlinkTarget(destDir, target, header.Linkname); err != nil {
+				return fmt.Errorf("illegal symlink: %s -> %s: %w", header.Name, header.Linkname, err)
+			}
+
 			// Create symbolic link
 			if err := os.Symlink(header.Linkname, target); err != nil {
 				if !os.IsExist(err) {
-					fmt.Printf("   ⚠ Warning: failed to create symlink %s: %v\n", target, err)
+					Progress().Warn(StageExtract, "failed to create symlink %s: %v", target, err)
 				}
 			}
 
 		default:
 			// Skip other types
-			fmt.Printf("   ⚠ Warning: skipping unsupported file type: %c in %s\n", header.Typeflag, header.Name)
+			Progress().Warn(StageExtract, "skipping unsupported file type: %c in %s", header.Typeflag, header.Name)
 		}
 	}
 
@@ -85,6 +93,6 @@ func ExtractTarXz(archivePath, destDir string) error {
 		return fmt.Errorf("xz decompression failed: %w", err)
 	}
 
-	fmt.Printf("     ✓ Complete\n")
+	Progress().Done(StageExtract, "Complete")
 	return nil
 }
@@ -0,0 +1,39 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oa-plugins/webauto/internal/filelock"
+)
+
+// runtimeLockPath is the lock serializing everything that installs into or
+// deletes from the runtime, across webauto processes
+func runtimeLockPath() string {
+	return filepath.Join(GetCacheDir(), "runtime.lock")
+}
+
+// withRuntimeLock runs fn while holding the runtime lock, waiting for another
+// process's install, repair or prune to finish first. fn must not take the
+// lock again: every Acquire opens its own handle, so nesting deadlocks.
+func withRuntimeLock(fn func() error) error {
+	if err := os.MkdirAll(GetCacheDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	lock, err := filelock.TryAcquire(runtimeLockPath())
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		Progress().Step(StageSetup, "Waiting for another webauto process to finish changing the runtime...")
+		if lock, err = filelock.Acquire(context.Background(), runtimeLockPath()); err != nil {
+			return err
+		}
+	}
+	defer lock.Release()
+
+	return fn()
+}
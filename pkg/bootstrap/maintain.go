@@ -0,0 +1,328 @@
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Runtime components that can be repaired individually, besides the
+// browser types in SupportedBrowsers
+const (
+	ComponentNode       = "node"
+	ComponentPlaywright = "playwright"
+)
+
+// RemovedItem is a file or directory deleted by prune or uninstall
+type RemovedItem struct {
+	Path      string `json:"path"`
+	Reason    string `json:"reason"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// UpgradeResult reports what an upgrade changed
+type UpgradeResult struct {
+	FromNode       string        `json:"from_node"`
+	ToNode         string        `json:"to_node"`
+	FromPlaywright string        `json:"from_playwright"`
+	ToPlaywright   string        `json:"to_playwright"`
+	Upgraded       []string      `json:"upgraded"`
+	Pruned         []RemovedItem `json:"pruned"`
+}
+
+// RepairNode reinstalls the pinned Node.js release
+func RepairNode() error {
+	return withRuntimeLock(repairNode)
+}
+
+// repairNode implements RepairNode; the caller holds the runtime lock
+func repairNode() error {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		return fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(GetRuntimeDir(), platform.NodeDirName)); err != nil {
+		return fmt.Errorf("failed to remove Node.js: %w", err)
+	}
+
+	archiveSHA256, err := InstallNode(platform)
+	if err != nil {
+		return err
+	}
+
+	return UpdateRuntimeManifest(func(manifest *RuntimeManifest) {
+		recordNode(manifest, platform, archiveSHA256)
+	})
+}
+
+// RepairPlaywright reinstalls the pinned Playwright packages from scratch
+func RepairPlaywright(nodeExePath string) error {
+	return withRuntimeLock(func() error {
+		return repairPlaywright(nodeExePath)
+	})
+}
+
+// repairPlaywright implements RepairPlaywright; the caller holds the runtime
+// lock
+func repairPlaywright(nodeExePath string) error {
+	if err := os.RemoveAll(GetNodeModulesDir()); err != nil {
+		return fmt.Errorf("failed to remove node_modules: %w", err)
+	}
+
+	if err := InstallPlaywright(nodeExePath); err != nil {
+		return err
+	}
+
+	return UpdateRuntimeManifest(recordPlaywright)
+}
+
+// RepairBrowser removes every build of a browser type and installs it again
+func RepairBrowser(nodeExePath, browserType string, out io.Writer) error {
+	return withRuntimeLock(func() error {
+		return repairBrowser(nodeExePath, browserType, out)
+	})
+}
+
+// repairBrowser implements RepairBrowser; the caller holds the runtime lock
+func repairBrowser(nodeExePath, browserType string, out io.Writer) error {
+	builds, err := ListBrowserBuilds()
+	if err != nil {
+		return err
+	}
+
+	for _, build := range builds {
+		if BrowserTypeOf(build.Name) != browserType {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(GetBrowsersDir(), build.Dir)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", build.Dir, err)
+		}
+	}
+
+	return installBrowser(nodeExePath, browserType, false, out)
+}
+
+// UpgradeRuntime brings Node.js and Playwright to the versions pinned in this
+// build, reinstalls the browsers that were installed before for the new
+// Playwright, and prunes what the old versions left behind
+func UpgradeRuntime(out io.Writer) (*UpgradeResult, error) {
+	var result *UpgradeResult
+	err := withRuntimeLock(func() error {
+		var err error
+		result, err = upgradeRuntime(out)
+		return err
+	})
+	return result, err
+}
+
+// upgradeRuntime implements UpgradeRuntime; the caller holds the runtime lock
+func upgradeRuntime(out io.Writer) (*UpgradeResult, error) {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	before, err := InspectRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{
+		FromNode:       before.Node.Version,
+		ToNode:         before.Node.Version,
+		FromPlaywright: before.Playwright.Version,
+		ToPlaywright:   before.Playwright.Version,
+		Upgraded:       []string{},
+		Pruned:         []RemovedItem{},
+	}
+
+	if !before.Node.OK {
+		if err := repairNode(); err != nil {
+			return result, fmt.Errorf("failed to upgrade Node.js: %w", err)
+		}
+		result.ToNode = NodeVersion
+		result.Upgraded = append(result.Upgraded, ComponentNode)
+	}
+
+	nodePath := GetNodeBinaryPath(platform)
+	if !before.Playwright.OK {
+		if err := repairPlaywright(nodePath); err != nil {
+			return result, fmt.Errorf("failed to upgrade Playwright: %w", err)
+		}
+		result.ToPlaywright = PlaywrightVersion
+		result.Upgraded = append(result.Upgraded, ComponentPlaywright)
+
+		// The new Playwright expects new browser revisions
+		for _, browserType := range installedBrowserTypes(before.Browsers) {
+			if err := installBrowser(nodePath, browserType, false, out); err != nil {
+				return result, fmt.Errorf("failed to upgrade %s: %w", browserType, err)
+			}
+			result.Upgraded = append(result.Upgraded, browserType)
+		}
+	}
+
+	pruned, err := pruneRuntime(false)
+	result.Pruned = pruned
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// installedBrowserTypes returns the browser types with at least one build
+func installedBrowserTypes(builds []BrowserBuild) []string {
+	types := []string{}
+	for _, browserType := range SupportedBrowsers {
+		for _, build := range builds {
+			if build.Name == browserType {
+				types = append(types, browserType)
+				break
+			}
+		}
+	}
+	return types
+}
+
+// PruneRuntime removes stale and incomplete browser builds, Node.js releases
+// other than the pinned one, leftover download archives and bundle staging
+// directories. With dryRun nothing is deleted. Pruning waits for running
+// installs, so partial downloads and builds being unpacked are left alone.
+func PruneRuntime(dryRun bool) ([]RemovedItem, error) {
+	if dryRun {
+		return pruneRuntime(true)
+	}
+	var removed []RemovedItem
+	err := withRuntimeLock(func() error {
+		var err error
+		removed, err = pruneRuntime(false)
+		return err
+	})
+	return removed, err
+}
+
+// pruneRuntime implements PruneRuntime; unless dryRun the caller holds the
+// runtime lock
+func pruneRuntime(dryRun bool) ([]RemovedItem, error) {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	candidates := []RemovedItem{}
+
+	builds, err := ListBrowserBuilds()
+	if err != nil {
+		return nil, err
+	}
+	for _, build := range builds {
+		reason := ""
+		switch {
+		case build.Stale:
+			reason = "stale browser build"
+		case !build.Complete:
+			reason = "incomplete browser build"
+		default:
+			continue
+		}
+		candidates = append(candidates, RemovedItem{
+			Path:      filepath.Join(GetBrowsersDir(), build.Dir),
+			Reason:    reason,
+			SizeBytes: build.SizeBytes,
+		})
+	}
+
+	entries, err := os.ReadDir(GetRuntimeDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read runtime directory: %w", err)
+	}
+	for _, entry := range entries {
+		entryPath := filepath.Join(GetRuntimeDir(), entry.Name())
+		switch {
+		case entry.IsDir() && strings.HasPrefix(entry.Name(), "node-") && entry.Name() != platform.NodeDirName:
+			candidates = append(candidates, RemovedItem{Path: entryPath, Reason: "unused Node.js release", SizeBytes: dirSize(entryPath)})
+		case !entry.IsDir() && isArchiveName(entry.Name()):
+			candidates = append(candidates, RemovedItem{Path: entryPath, Reason: "leftover download", SizeBytes: dirSize(entryPath)})
+		}
+	}
+
+	stagingDir := filepath.Join(GetCacheDir(), ".bundle-staging")
+	if _, err := os.Stat(stagingDir); err == nil {
+		candidates = append(candidates, RemovedItem{Path: stagingDir, Reason: "interrupted bundle install", SizeBytes: dirSize(stagingDir)})
+	}
+
+	if dryRun {
+		return candidates, nil
+	}
+
+	removed := make([]RemovedItem, 0, len(candidates))
+	for _, item := range candidates {
+		if err := os.RemoveAll(item.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", item.Path, err)
+		}
+		removed = append(removed, item)
+	}
+	return removed, nil
+}
+
+// isArchiveName reports whether name looks like a (partial) download
+func isArchiveName(name string) bool {
+	for _, ext := range []string{".tar.gz", ".tar.xz", ".zip", ".part"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// UninstallRuntime removes Node.js, the Playwright packages, all browser
+// builds and the runtime manifest. Sessions, selectors and other user data
+// in the cache directory are kept. With dryRun nothing is deleted.
+func UninstallRuntime(dryRun bool) ([]RemovedItem, error) {
+	if dryRun {
+		return uninstallRuntime(true)
+	}
+	var removed []RemovedItem
+	err := withRuntimeLock(func() error {
+		var err error
+		removed, err = uninstallRuntime(false)
+		return err
+	})
+	return removed, err
+}
+
+// uninstallRuntime implements UninstallRuntime; unless dryRun the caller
+// holds the runtime lock
+func uninstallRuntime(dryRun bool) ([]RemovedItem, error) {
+	cacheDir := GetCacheDir()
+	targets := []struct {
+		path   string
+		reason string
+	}{
+		{GetRuntimeDir(), "Node.js runtime"},
+		{GetNodeModulesDir(), "Playwright packages"},
+		{GetBrowsersDir(), "browser builds"},
+		{filepath.Join(cacheDir, "package.json"), "Playwright package pin"},
+		{filepath.Join(cacheDir, "package-lock.json"), "Playwright lockfile"},
+		{GetRuntimeManifestPath(), "runtime manifest"},
+		{filepath.Join(cacheDir, ".bundle-staging"), "interrupted bundle install"},
+	}
+
+	removed := []RemovedItem{}
+	for _, target := range targets {
+		if _, err := os.Lstat(target.path); err != nil {
+			continue
+		}
+
+		item := RemovedItem{Path: target.path, Reason: target.reason, SizeBytes: dirSize(target.path)}
+		if !dryRun {
+			if err := os.RemoveAll(target.path); err != nil {
+				return removed, fmt.Errorf("failed to remove %s: %w", target.path, err)
+			}
+		}
+		removed = append(removed, item)
+	}
+	return removed, nil
+}
@@ -0,0 +1,107 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Runtime install sources recorded in the runtime manifest
+const (
+	SourceDownload = "download"
+	SourceBundle   = "bundle"
+)
+
+// RuntimeManifest records what was installed into the cache directory and
+// how each artifact was verified
+type RuntimeManifest struct {
+	Source      string    `json:"source"`
+	InstalledAt time.Time `json:"installed_at"`
+
+	// Node.js archive, checked against the release's SHASUMS256.txt
+	NodeVersion    string `json:"node_version"`
+	NodeArchive    string `json:"node_archive,omitempty"`
+	NodeSHA256     string `json:"node_sha256,omitempty"`
+	NodeVerified   bool   `json:"node_verified"`
+	ChecksumSource string `json:"checksum_source,omitempty"`
+
+	// Playwright packages, installed with "npm ci" from the pinned lockfile
+	PlaywrightVersion string `json:"playwright_version"`
+	LockfileSHA256    string `json:"lockfile_sha256,omitempty"`
+
+	// Offline bundle the runtime was installed from, if any
+	BundlePath     string `json:"bundle_path,omitempty"`
+	BundleSHA256   string `json:"bundle_sha256,omitempty"`
+	BundleVerified bool   `json:"bundle_verified,omitempty"`
+}
+
+// GetRuntimeManifestPath returns the path of the runtime manifest
+func GetRuntimeManifestPath() string {
+	return filepath.Join(GetCacheDir(), "runtime.json")
+}
+
+// ReadRuntimeManifest loads the runtime manifest written by the last install
+func ReadRuntimeManifest() (*RuntimeManifest, error) {
+	return readRuntimeManifest(GetRuntimeManifestPath())
+}
+
+func readRuntimeManifest(manifestPath string) (*RuntimeManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest RuntimeManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid runtime manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// writeRuntimeManifest stores the runtime manifest atomically
+func writeRuntimeManifest(manifest *RuntimeManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode runtime manifest: %w", err)
+	}
+
+	manifestPath := GetRuntimeManifestPath()
+	tmpPath := manifestPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write runtime manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, manifestPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write runtime manifest: %w", err)
+	}
+	return nil
+}
+
+// UpdateRuntimeManifest applies update to the current runtime manifest (or an
+// empty one) and stores the result
+func UpdateRuntimeManifest(update func(manifest *RuntimeManifest)) error {
+	manifest, err := ReadRuntimeManifest()
+	if err != nil {
+		manifest = &RuntimeManifest{}
+	}
+	update(manifest)
+	return writeRuntimeManifest(manifest)
+}
+
+// recordNode stores a verified Node.js download in the manifest
+func recordNode(manifest *RuntimeManifest, platform *PlatformInfo, archiveSHA256 string) {
+	manifest.NodeVersion = NodeVersion
+	manifest.NodeArchive = path.Base(platform.DownloadURL)
+	manifest.NodeSHA256 = archiveSHA256
+	manifest.NodeVerified = true
+	manifest.ChecksumSource = NodeChecksumSource(manifest.NodeArchive)
+}
+
+// recordPlaywright stores the installed Playwright version and lockfile
+func recordPlaywright(manifest *RuntimeManifest) {
+	manifest.PlaywrightVersion = InstalledPlaywrightVersion()
+	manifest.LockfileSHA256 = LockfileSHA256()
+}
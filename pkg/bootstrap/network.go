@@ -0,0 +1,93 @@
+package bootstrap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// NodeMirrorEnv overrides NodeBaseURL with an internal mirror of
+	// https://nodejs.org/dist (same layout). Archives from a mirror are still
+	// verified against checksums from the official origin.
+	NodeMirrorEnv = "WEBAUTO_NODE_MIRROR"
+
+	// NpmRegistryEnv is the npm registry Playwright is installed from
+	NpmRegistryEnv = "WEBAUTO_NPM_REGISTRY"
+
+	// PlaywrightDownloadHostEnv is the mirror of the Playwright browser CDN
+	// (passed to Playwright as PLAYWRIGHT_DOWNLOAD_HOST)
+	PlaywrightDownloadHostEnv = "WEBAUTO_PLAYWRIGHT_DOWNLOAD_HOST"
+
+	// CABundleEnv names a PEM file of extra CA certificates trusted for all
+	// runtime downloads, for mirrors and proxies behind a private CA
+	CABundleEnv = "WEBAUTO_CA_BUNDLE"
+)
+
+var (
+	httpClientOnce sync.Once
+	httpClient     *http.Client
+	httpClientErr  error
+)
+
+// NodeMirrorURL returns the base URL Node.js releases are downloaded from
+func NodeMirrorURL() string {
+	if mirror := strings.TrimSpace(os.Getenv(NodeMirrorEnv)); mirror != "" {
+		return strings.TrimRight(mirror, "/")
+	}
+	return NodeBaseURL
+}
+
+// downloadClient returns the HTTP client used for runtime downloads. It
+// honors HTTPS_PROXY, HTTP_PROXY and NO_PROXY and trusts the certificates in
+// WEBAUTO_CA_BUNDLE in addition to the system roots.
+func downloadClient() (*http.Client, error) {
+	httpClientOnce.Do(func() {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyFromEnvironment
+
+		if caPath := os.Getenv(CABundleEnv); caPath != "" {
+			pem, err := os.ReadFile(caPath)
+			if err != nil {
+				httpClientErr = fmt.Errorf("failed to read %s: %w", CABundleEnv, err)
+				return
+			}
+
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				httpClientErr = fmt.Errorf("%s (%s) contains no PEM certificates", CABundleEnv, caPath)
+				return
+			}
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+
+		httpClient = &http.Client{Transport: transport}
+	})
+	return httpClient, httpClientErr
+}
+
+// toolEnv returns the environment for npm and the Playwright installer with
+// the configured registry, browser mirror and CA bundle applied. Proxy
+// variables are inherited as-is; npm and Playwright read them natively.
+func toolEnv() []string {
+	env := append(os.Environ(), fmt.Sprintf("PLAYWRIGHT_BROWSERS_PATH=%s", GetBrowsersDir()))
+
+	if registry := strings.TrimSpace(os.Getenv(NpmRegistryEnv)); registry != "" {
+		env = append(env, "npm_config_registry="+registry)
+	}
+	if host := strings.TrimSpace(os.Getenv(PlaywrightDownloadHostEnv)); host != "" {
+		env = append(env, "PLAYWRIGHT_DOWNLOAD_HOST="+strings.TrimRight(host, "/"))
+	}
+	if caPath := os.Getenv(CABundleEnv); caPath != "" {
+		// Additive to Node's bundled roots, unlike npm's cafile
+		env = append(env, "NODE_EXTRA_CA_CERTS="+caPath)
+	}
+	return env
+}
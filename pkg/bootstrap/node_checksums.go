@@ -0,0 +1,8 @@
+package bootstrap
+
+// nodeArchiveSHA256 pins the SHA-256 of each Node.js v22.11.0 archive.
+// scripts/nodechecksums fills it from the official SHASUMS256.txt; this build
+// was cut without network access, so the table is empty and every archive is
+// checked against SHASUMS256.txt on nodejs.org instead. Run
+// "go generate ./pkg/bootstrap" to pin them.
+var nodeArchiveSHA256 = map[string]string{}
@@ -1,91 +1,81 @@
 package bootstrap
 
 import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 )
 
-// InstallPlaywright installs Playwright via npm
+// runtimePackage holds the package.json and lockfile that pin the exact
+// Playwright packages (with integrity hashes) installed into the runtime
+//
+//go:embed runtime/package.json runtime/package-lock.json
+var runtimePackage embed.FS
+
+// InstallPlaywright installs the pinned Playwright packages via "npm ci",
+// which refuses packages that do not match the lockfile's integrity hashes
 func InstallPlaywright(nodeExePath string) error {
 	cacheDir := GetCacheDir()
 	nodeModulesDir := GetNodeModulesDir()
-	browsersDir := GetBrowsersDir()
 
-	fmt.Printf("   ▸ Installing Playwright library...\n")
+	Progress().Step(StagePlaywrightInstall, "Installing Playwright library %s...", PlaywrightVersion)
 
 	// Ensure directories exist
 	if err := os.MkdirAll(nodeModulesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create node_modules directory: %w", err)
 	}
 
-	// Get npm path (should be in same directory as node)
-	npmPath := filepath.Join(filepath.Dir(nodeExePath), "npm")
-	if _, err := os.Stat(npmPath); os.IsNotExist(err) {
-		// On Windows, npm might be npm.cmd
-		npmPath = filepath.Join(filepath.Dir(nodeExePath), "npm.cmd")
-		if _, err := os.Stat(npmPath); os.IsNotExist(err) {
-			return fmt.Errorf("npm not found in Node.js installation")
+	// Write the pinned package.json and lockfile
+	for _, name := range []string{"package.json", "package-lock.json"} {
+		data, err := runtimePackage.ReadFile("runtime/" + name)
+		if err != nil {
+			return fmt.Errorf("failed to read embedded %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(cacheDir, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
 		}
 	}
 
-	// Run npm install playwright
-	cmd := exec.Command(npmPath, "install", "playwright", "@playwright/test")
+	// Get npm path (should be in same directory as node)
+	npmPath, err := findNodeTool(nodeExePath, "npm")
+	if err != nil {
+		return err
+	}
+
+	// Run npm ci against the pinned lockfile
+	cmd := exec.Command(npmPath, "ci", "--no-audit", "--no-fund")
 	cmd.Dir = cacheDir
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("PLAYWRIGHT_BROWSERS_PATH=%s", browsersDir),
-	)
+	cmd.Env = toolEnv()
 
 	// Capture output
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("npm install failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("npm ci failed: %w\nOutput: %s", err, string(output))
 	}
 
-	fmt.Printf("     ✓ Playwright installed\n")
+	Progress().Done(StagePlaywrightInstall, "Playwright installed")
 	return nil
 }
 
-// InstallPlaywrightBrowsers downloads and installs Playwright browsers
-func InstallPlaywrightBrowsers(nodeExePath string) error {
-	browsersDir := GetBrowsersDir()
-	nodeModulesDir := GetNodeModulesDir()
-
-	fmt.Printf("   ▸ Installing Playwright browsers...\n")
-
-	// Ensure browsers directory exists
-	if err := os.MkdirAll(browsersDir, 0755); err != nil {
-		return fmt.Errorf("failed to create browsers directory: %w", err)
-	}
-
-	// Get npx path
-	npxPath := filepath.Join(filepath.Dir(nodeExePath), "npx")
-	if _, err := os.Stat(npxPath); os.IsNotExist(err) {
-		// On Windows, npx might be npx.cmd
-		npxPath = filepath.Join(filepath.Dir(nodeExePath), "npx.cmd")
-		if _, err := os.Stat(npxPath); os.IsNotExist(err) {
-			return fmt.Errorf("npx not found in Node.js installation")
-		}
-	}
-
-	// Run npx playwright install chromium
-	cmd := exec.Command(npxPath, "playwright", "install", "chromium", "--with-deps")
-	cmd.Dir = filepath.Dir(nodeModulesDir)
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("PLAYWRIGHT_BROWSERS_PATH=%s", browsersDir),
-	)
-
-	// Show output in real-time for long-running browser download
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("playwright install failed: %w", err)
+// LockfileSHA256 returns the SHA-256 of the embedded Playwright lockfile
+func LockfileSHA256() string {
+	data, err := runtimePackage.ReadFile("runtime/package-lock.json")
+	if err != nil {
+		return ""
 	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
 
-	fmt.Printf("     ✓ Chromium browser installed\n")
-	return nil
+// InstallPlaywrightBrowsers downloads and installs Playwright browsers. It
+// runs during first-time setup, which holds the runtime lock.
+func InstallPlaywrightBrowsers(nodeExePath string) error {
+	// Firefox and WebKit are installed on demand (see InstallBrowser)
+	return installBrowserWithProgress(nodeExePath, "chromium", true)
 }
 
 // VerifyNodeInstallation checks if Node.js is properly installed
@@ -108,6 +98,6 @@ func VerifyNodeInstallation(nodePath string) error {
 		version = version[:len(version)-1]
 	}
 
-	fmt.Printf("     ✓ Node.js %s verified\n", version)
+	Progress().Done(StageVerify, "Node.js %s verified", version)
 	return nil
 }
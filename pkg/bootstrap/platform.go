@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 )
@@ -11,8 +12,12 @@ const (
 	// Node.js version to download
 	NodeVersion = "v22.11.0"
 
-	// Base URL for Node.js downloads
+	// Default base URL for Node.js downloads (see NodeMirrorURL)
 	NodeBaseURL = "https://nodejs.org/dist"
+
+	// Playwright version installed into the runtime; must match
+	// runtime/package.json and runtime/package-lock.json
+	PlaywrightVersion = "1.56.0"
 )
 
 // PlatformInfo holds platform-specific information
@@ -25,20 +30,50 @@ type PlatformInfo struct {
 	BinaryPath   string // bin/node or node.exe
 }
 
+// supportedPlatforms lists the GOOS/GOARCH pairs GetPlatformInfo supports
+var supportedPlatforms = [][2]string{
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"linux", "amd64"},
+	{"linux", "arm64"},
+	{"windows", "amd64"},
+	{"windows", "arm64"},
+}
+
 // GetPlatformInfo returns platform information for current system
 func GetPlatformInfo() (*PlatformInfo, error) {
+	return platformInfoFor(runtime.GOOS, runtime.GOARCH)
+}
+
+// NodeArchiveNames returns the Node.js archive file names of every supported
+// platform
+func NodeArchiveNames() []string {
+	names := make([]string, 0, len(supportedPlatforms))
+	for _, p := range supportedPlatforms {
+		info, err := platformInfoFor(p[0], p[1])
+		if err != nil {
+			continue
+		}
+		names = append(names, path.Base(info.DownloadURL))
+	}
+	return names
+}
+
+// platformInfoFor returns platform information for the given GOOS/GOARCH
+func platformInfoFor(goos, goarch string) (*PlatformInfo, error) {
 	info := &PlatformInfo{
-		OS: runtime.GOOS,
+		OS: goos,
 	}
+	baseURL := NodeMirrorURL()
 
 	// Determine architecture
-	switch runtime.GOARCH {
+	switch goarch {
 	case "amd64":
 		info.Arch = "x64"
 	case "arm64":
 		info.Arch = "arm64"
 	default:
-		return nil, fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+		return nil, fmt.Errorf("unsupported architecture: %s", goarch)
 	}
 
 	// Platform-specific configuration
@@ -47,17 +82,17 @@ func GetPlatformInfo() (*PlatformInfo, error) {
 		info.NodeDirName = fmt.Sprintf("node-%s-darwin-%s", NodeVersion, info.Arch)
 		info.ArchiveExt = ".tar.gz"
 		info.BinaryPath = "bin/node"
-		info.DownloadURL = fmt.Sprintf("%s/%s/%s.tar.gz", NodeBaseURL, NodeVersion, info.NodeDirName)
+		info.DownloadURL = fmt.Sprintf("%s/%s/%s.tar.gz", baseURL, NodeVersion, info.NodeDirName)
 	case "windows":
 		info.NodeDirName = fmt.Sprintf("node-%s-win-%s", NodeVersion, info.Arch)
 		info.ArchiveExt = ".zip"
 		info.BinaryPath = "node.exe"
-		info.DownloadURL = fmt.Sprintf("%s/%s/%s.zip", NodeBaseURL, NodeVersion, info.NodeDirName)
+		info.DownloadURL = fmt.Sprintf("%s/%s/%s.zip", baseURL, NodeVersion, info.NodeDirName)
 	case "linux":
 		info.NodeDirName = fmt.Sprintf("node-%s-linux-%s", NodeVersion, info.Arch)
 		info.ArchiveExt = ".tar.xz"
 		info.BinaryPath = "bin/node"
-		info.DownloadURL = fmt.Sprintf("%s/%s/%s.tar.xz", NodeBaseURL, NodeVersion, info.NodeDirName)
+		info.DownloadURL = fmt.Sprintf("%s/%s/%s.tar.xz", baseURL, NodeVersion, info.NodeDirName)
 	default:
 		return nil, fmt.Errorf("unsupported operating system: %s", info.OS)
 	}
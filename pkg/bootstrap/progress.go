@@ -0,0 +1,326 @@
+package bootstrap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// Progress output modes. Progress never goes to stdout, which is reserved
+// for the command's JSON response.
+const (
+	// ProgressText writes human-readable progress lines to stderr
+	ProgressText = "text"
+
+	// ProgressJSON writes one JSON progress event per line to stderr
+	ProgressJSON = "json"
+
+	// ProgressQuiet writes no progress at all
+	ProgressQuiet = "quiet"
+
+	// ProgressEnv selects the progress mode when --progress is not given
+	ProgressEnv = "WEBAUTO_PROGRESS"
+)
+
+// Progress stages
+const (
+	StageSetup             = "setup"
+	StageDownload          = "download"
+	StageVerify            = "verify"
+	StageExtract           = "extract"
+	StagePlaywrightInstall = "playwright_install"
+	StageBrowserInstall    = "browser_install"
+	StageBundleInstall     = "bundle_install"
+)
+
+// ProgressEvent is one line of --progress=json output. Byte counts are only
+// set on "progress" events; total and percent only when the size is known.
+type ProgressEvent struct {
+	Event      string   `json:"event"`
+	Stage      string   `json:"stage"`
+	Status     string   `json:"status"`
+	Message    string   `json:"message,omitempty"`
+	Bytes      *int64   `json:"bytes,omitempty"`
+	TotalBytes *int64   `json:"total_bytes,omitempty"`
+	Percent    *float64 `json:"percent,omitempty"`
+	Timestamp  string   `json:"timestamp"`
+}
+
+// Reporter routes bootstrap progress to stderr as text or JSON events, or
+// drops it in quiet mode
+type Reporter struct {
+	mu   sync.Mutex
+	mode string
+	out  io.Writer
+}
+
+var (
+	progressMu       sync.Mutex
+	progressReporter = NewReporter(ProgressText, os.Stderr)
+)
+
+// NewReporter creates a reporter writing in the given mode to out
+func NewReporter(mode string, out io.Writer) *Reporter {
+	return &Reporter{mode: mode, out: out}
+}
+
+// ParseProgressMode validates a --progress value; empty means text
+func ParseProgressMode(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", ProgressText:
+		return ProgressText, nil
+	case ProgressJSON:
+		return ProgressJSON, nil
+	case ProgressQuiet:
+		return ProgressQuiet, nil
+	default:
+		return "", fmt.Errorf("invalid progress mode %q (use %s, %s or %s)", mode, ProgressText, ProgressJSON, ProgressQuiet)
+	}
+}
+
+// SetProgressMode switches the global reporter used by all bootstrap output
+func SetProgressMode(mode string) error {
+	parsed, err := ParseProgressMode(mode)
+	if err != nil {
+		return err
+	}
+
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progressReporter = NewReporter(parsed, os.Stderr)
+	return nil
+}
+
+// Progress returns the global progress reporter
+func Progress() *Reporter {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	return progressReporter
+}
+
+// Mode returns the reporter's output mode
+func (r *Reporter) Mode() string {
+	return r.mode
+}
+
+// Header announces a group of steps ("Setting up webauto runtime...")
+func (r *Reporter) Header(stage, format string, args ...interface{}) {
+	r.report(stage, "start", "\n📦 ", format, args...)
+}
+
+// Step announces a step within a stage
+func (r *Reporter) Step(stage, format string, args ...interface{}) {
+	r.report(stage, "start", "   ▸ ", format, args...)
+}
+
+// Done reports that a step finished
+func (r *Reporter) Done(stage, format string, args ...interface{}) {
+	r.report(stage, "done", "     ✓ ", format, args...)
+}
+
+// Complete reports that a group of steps finished
+func (r *Reporter) Complete(stage, format string, args ...interface{}) {
+	r.report(stage, "complete", "✓ ", format, args...)
+	if r.mode == ProgressText {
+		r.write("\n")
+	}
+}
+
+// Warn reports a problem that does not stop the stage
+func (r *Reporter) Warn(stage, format string, args ...interface{}) {
+	r.report(stage, "warning", "   ⚠ Warning: ", format, args...)
+}
+
+// Retry reports that a step is retried
+func (r *Reporter) Retry(stage, format string, args ...interface{}) {
+	r.report(stage, "retry", "   ▸ ", format, args...)
+}
+
+func (r *Reporter) report(stage, status, prefix, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	switch r.mode {
+	case ProgressText:
+		r.write(prefix + message + "\n")
+	case ProgressJSON:
+		r.emit(ProgressEvent{Stage: stage, Status: status, Message: message})
+	}
+}
+
+// emit writes one JSON event line
+func (r *Reporter) emit(event ProgressEvent) {
+	event.Event = "progress"
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	r.write(string(data) + "\n")
+}
+
+func (r *Reporter) write(text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	io.WriteString(r.out, text)
+}
+
+// Bytes returns a writer that tracks a transfer of total bytes (-1 when
+// unknown) starting at offset: a progress bar in text mode, throttled byte
+// events in JSON mode. Close it when the transfer ends.
+func (r *Reporter) Bytes(stage, description string, offset, total int64) io.WriteCloser {
+	switch r.mode {
+	case ProgressText:
+		bar := progressbar.NewOptions64(
+			total,
+			progressbar.OptionSetDescription(description),
+			progressbar.OptionSetWriter(r.out),
+			progressbar.OptionShowBytes(true),
+			progressbar.OptionShowTotalBytes(true),
+			progressbar.OptionSetWidth(10),
+			progressbar.OptionThrottle(65*time.Millisecond),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSpinnerType(14),
+			progressbar.OptionFullWidth(),
+			progressbar.OptionSetRenderBlankState(true),
+		)
+		bar.Set64(offset)
+		return &barWriter{bar: bar, out: r.out}
+	case ProgressJSON:
+		counter := &byteEvents{reporter: r, stage: stage, bytes: offset, total: total}
+		counter.flush()
+		return counter
+	default:
+		return nopWriteCloser{io.Discard}
+	}
+}
+
+// Output returns a writer for a subprocess's output (npm, the Playwright
+// installer): passed through in text mode, wrapped line by line in "output"
+// events in JSON mode. Close it when the subprocess exits.
+func (r *Reporter) Output(stage string) io.WriteCloser {
+	switch r.mode {
+	case ProgressText:
+		return nopWriteCloser{&lockedWriter{r}}
+	case ProgressJSON:
+		reader, writer := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			scanner := bufio.NewScanner(reader)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				if line := strings.TrimSpace(scanner.Text()); line != "" {
+					r.emit(ProgressEvent{Stage: stage, Status: "output", Message: line})
+				}
+			}
+			io.Copy(io.Discard, reader)
+		}()
+		return &pipeOutput{writer: writer, done: done}
+	default:
+		return nopWriteCloser{io.Discard}
+	}
+}
+
+// barWriter feeds a progress bar and ends its line on close
+type barWriter struct {
+	bar *progressbar.ProgressBar
+	out io.Writer
+}
+
+func (w *barWriter) Write(p []byte) (int, error) {
+	return w.bar.Write(p)
+}
+
+func (w *barWriter) Close() error {
+	w.bar.Finish()
+	fmt.Fprintln(w.out)
+	return nil
+}
+
+// byteEvents emits a JSON event per percent (or every 500ms when the total
+// is unknown)
+type byteEvents struct {
+	reporter    *Reporter
+	stage       string
+	bytes       int64
+	total       int64
+	lastPercent float64
+	lastEmit    time.Time
+}
+
+func (b *byteEvents) Write(p []byte) (int, error) {
+	b.bytes += int64(len(p))
+	if b.total > 0 {
+		if b.percent()-b.lastPercent >= 1 {
+			b.flush()
+		}
+	} else if time.Since(b.lastEmit) >= 500*time.Millisecond {
+		b.flush()
+	}
+	return len(p), nil
+}
+
+func (b *byteEvents) Close() error {
+	b.flush()
+	return nil
+}
+
+func (b *byteEvents) percent() float64 {
+	if b.total <= 0 {
+		return 0
+	}
+	return float64(int64(float64(b.bytes)*1000/float64(b.total))) / 10
+}
+
+func (b *byteEvents) flush() {
+	b.lastPercent = b.percent()
+	b.lastEmit = time.Now()
+
+	bytes := b.bytes
+	event := ProgressEvent{Stage: b.stage, Status: "progress", Bytes: &bytes}
+	if b.total > 0 {
+		total, percent := b.total, b.lastPercent
+		event.TotalBytes = &total
+		event.Percent = &percent
+	}
+	b.reporter.emit(event)
+}
+
+// pipeOutput closes the pipe and waits until every line was emitted
+type pipeOutput struct {
+	writer *io.PipeWriter
+	done   chan struct{}
+}
+
+func (p *pipeOutput) Write(data []byte) (int, error) {
+	return p.writer.Write(data)
+}
+
+func (p *pipeOutput) Close() error {
+	p.writer.Close()
+	<-p.done
+	return nil
+}
+
+// lockedWriter serializes subprocess output with the reporter's own lines
+type lockedWriter struct {
+	reporter *Reporter
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.reporter.mu.Lock()
+	defer w.reporter.mu.Unlock()
+	return w.reporter.out.Write(p)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,225 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ComponentStatus describes one installed runtime component
+type ComponentStatus struct {
+	Installed       bool   `json:"installed"`
+	Version         string `json:"version,omitempty"`
+	ExpectedVersion string `json:"expected_version"`
+	Path            string `json:"path"`
+	SizeBytes       int64  `json:"size_bytes"`
+	OK              bool   `json:"ok"`
+	Error           string `json:"error,omitempty"`
+}
+
+// BrowserBuild is one "<name>-<revision>" directory in the browsers directory
+type BrowserBuild struct {
+	Dir       string `json:"dir"`
+	Name      string `json:"name"`
+	Revision  string `json:"revision"`
+	Complete  bool   `json:"complete"`
+	Stale     bool   `json:"stale"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// RuntimeStatus is a snapshot of everything installed in the cache directory
+type RuntimeStatus struct {
+	CacheDir          string           `json:"cache_dir"`
+	Node              ComponentStatus  `json:"node"`
+	Playwright        ComponentStatus  `json:"playwright"`
+	Browsers          []BrowserBuild   `json:"browsers"`
+	BrowsersSizeBytes int64            `json:"browsers_size_bytes"`
+	TotalSizeBytes    int64            `json:"total_size_bytes"`
+	Manifest          *RuntimeManifest `json:"manifest,omitempty"`
+	Healthy           bool             `json:"healthy"`
+	Issues            []string         `json:"issues"`
+}
+
+// InspectRuntime reports the installed Node.js, Playwright and browser
+// builds, their versions and disk usage, and anything that needs repair
+func InspectRuntime() (*RuntimeStatus, error) {
+	platform, err := GetPlatformInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	status := &RuntimeStatus{
+		CacheDir: GetCacheDir(),
+		Browsers: []BrowserBuild{},
+		Issues:   []string{},
+	}
+
+	// Node.js
+	nodePath := GetNodeBinaryPath(platform)
+	status.Node = ComponentStatus{
+		ExpectedVersion: NodeVersion,
+		Path:            nodePath,
+		SizeBytes:       dirSize(GetRuntimeDir()),
+	}
+	if _, err := os.Stat(nodePath); err == nil {
+		status.Node.Installed = true
+		output, err := exec.Command(nodePath, "--version").Output()
+		if err != nil {
+			status.Node.Error = fmt.Sprintf("node --version failed: %v", err)
+			status.Issues = append(status.Issues, "Node.js is installed but does not run (repair: runtime repair node)")
+		} else {
+			status.Node.Version = strings.TrimSpace(string(output))
+		}
+	} else {
+		status.Issues = append(status.Issues, "Node.js is not installed (repair: runtime repair node)")
+	}
+	status.Node.OK = status.Node.Version == NodeVersion
+	if status.Node.Version != "" && !status.Node.OK {
+		status.Issues = append(status.Issues, fmt.Sprintf("Node.js %s is installed, this build expects %s (run: runtime upgrade)", status.Node.Version, NodeVersion))
+	}
+
+	// Playwright library
+	status.Playwright = ComponentStatus{
+		ExpectedVersion: PlaywrightVersion,
+		Path:            GetNodeModulesDir(),
+		SizeBytes:       dirSize(GetNodeModulesDir()),
+		Version:         InstalledPlaywrightVersion(),
+	}
+	status.Playwright.Installed = status.Playwright.Version != ""
+	status.Playwright.OK = status.Playwright.Version == PlaywrightVersion
+	switch {
+	case !status.Playwright.Installed:
+		status.Issues = append(status.Issues, "Playwright is not installed (repair: runtime repair playwright)")
+	case !status.Playwright.OK:
+		status.Issues = append(status.Issues, fmt.Sprintf("Playwright %s is installed, this build expects %s (run: runtime upgrade)", status.Playwright.Version, PlaywrightVersion))
+	}
+
+	// Browser builds
+	builds, err := ListBrowserBuilds()
+	if err != nil {
+		return nil, err
+	}
+	status.Browsers = builds
+	stale := 0
+	for _, build := range builds {
+		status.BrowsersSizeBytes += build.SizeBytes
+		if !build.Complete {
+			status.Issues = append(status.Issues, fmt.Sprintf("Browser build %s is incomplete (repair: runtime repair %s)", build.Dir, BrowserTypeOf(build.Name)))
+		}
+		if build.Stale {
+			stale++
+		}
+	}
+	if stale > 0 {
+		status.Issues = append(status.Issues, fmt.Sprintf("%d stale browser build(s) not used by Playwright %s (run: runtime prune)", stale, status.Playwright.Version))
+	}
+	if !IsBrowserInstalled("chromium") {
+		status.Issues = append(status.Issues, "Chromium is not installed (repair: runtime repair chromium)")
+	}
+
+	status.TotalSizeBytes = status.Node.SizeBytes + status.Playwright.SizeBytes + status.BrowsersSizeBytes
+
+	if manifest, err := ReadRuntimeManifest(); err == nil {
+		status.Manifest = manifest
+	}
+
+	status.Healthy = status.Node.OK && status.Playwright.OK && IsBrowserInstalled("chromium")
+	return status, nil
+}
+
+// ListBrowserBuilds lists the browser builds in the browsers directory. A
+// build is stale when the installed Playwright expects another revision of
+// that browser.
+func ListBrowserBuilds() ([]BrowserBuild, error) {
+	entries, err := os.ReadDir(GetBrowsersDir())
+	if os.IsNotExist(err) {
+		return []BrowserBuild{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read browsers directory: %w", err)
+	}
+
+	expected := expectedBrowserRevisions()
+	builds := make([]BrowserBuild, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		i := strings.LastIndex(entry.Name(), "-")
+		if i <= 0 {
+			continue
+		}
+
+		dir := filepath.Join(GetBrowsersDir(), entry.Name())
+		build := BrowserBuild{
+			Dir:       entry.Name(),
+			Name:      entry.Name()[:i],
+			Revision:  entry.Name()[i+1:],
+			SizeBytes: dirSize(dir),
+		}
+		if _, err := os.Stat(filepath.Join(dir, "INSTALLATION_COMPLETE")); err == nil {
+			build.Complete = true
+		}
+		if revision, ok := expected[build.Name]; ok && revision != build.Revision {
+			build.Stale = true
+		}
+		builds = append(builds, build)
+	}
+	return builds, nil
+}
+
+// expectedBrowserRevisions reads the browser revisions the installed
+// Playwright downloads, keyed by browsers directory name
+// (e.g. "chromium_headless_shell")
+func expectedBrowserRevisions() map[string]string {
+	revisions := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(GetNodeModulesDir(), "playwright-core", "browsers.json"))
+	if err != nil {
+		return revisions
+	}
+
+	var manifest struct {
+		Browsers []struct {
+			Name     string `json:"name"`
+			Revision string `json:"revision"`
+		} `json:"browsers"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return revisions
+	}
+
+	for _, browser := range manifest.Browsers {
+		revisions[strings.ReplaceAll(browser.Name, "-", "_")] = browser.Revision
+	}
+	return revisions
+}
+
+// BrowserTypeOf maps a browser build name to the browser type that installs
+// it (chromium_headless_shell belongs to chromium)
+func BrowserTypeOf(name string) string {
+	for _, browserType := range SupportedBrowsers {
+		if name == browserType || strings.HasPrefix(name, browserType+"_") {
+			return browserType
+		}
+	}
+	return name
+}
+
+// dirSize returns the total size of the files below path
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
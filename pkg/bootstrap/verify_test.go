@@ -0,0 +1,118 @@
+package bootstrap
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindChecksum(t *testing.T) {
+	const sums = `aaaa1111  node-v20.11.0-darwin-arm64.tar.gz
+bbbb2222  node-v20.11.0-linux-x64.tar.xz
+cccc3333 *node-v20.11.0-win-x64.zip
+dddd4444  node-v20.11.0-linux-x64.tar.xz.sig extra
+`
+	tests := []struct {
+		name     string
+		fileName string
+		want     string
+		wantErr  bool
+	}{
+		{"first entry", "node-v20.11.0-darwin-arm64.tar.gz", "aaaa1111", false},
+		{"middle entry", "node-v20.11.0-linux-x64.tar.xz", "bbbb2222", false},
+		{"binary mode marker", "node-v20.11.0-win-x64.zip", "cccc3333", false},
+		{"prefix is not a match", "node-v20.11.0-linux-x64.tar", "", true},
+		{"missing file", "node-v18.0.0-linux-x64.tar.xz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findChecksum(strings.NewReader(sums), tt.fileName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findChecksum(%q) error = %v, wantErr %v", tt.fileName, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("findChecksum(%q) = %q, want %q", tt.fileName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeChecksumsURLIgnoresMirror(t *testing.T) {
+	t.Setenv(NodeMirrorEnv, "https://mirror.example.com/node")
+	if got := NodeChecksumsURL(); !strings.HasPrefix(got, NodeBaseURL+"/") {
+		t.Errorf("NodeChecksumsURL() = %q, want the official origin %s", got, NodeBaseURL)
+	}
+}
+
+func TestVerifyNodeArchivePinned(t *testing.T) {
+	platform := &PlatformInfo{DownloadURL: "https://mirror.example.com/node/v1/node-v1-linux-x64.tar.xz"}
+	archivePath := filepath.Join(t.TempDir(), "node.tar.xz")
+	if err := os.WriteFile(archivePath, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := FileSHA256(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pinned  string
+		wantErr bool
+	}{
+		{"matching checksum", sum, false},
+		{"matching checksum in upper case", strings.ToUpper(sum), false},
+		{"mismatching checksum", strings.Repeat("0", 64), true},
+	}
+
+	saved := nodeArchiveSHA256
+	defer func() { nodeArchiveSHA256 = saved }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeArchiveSHA256 = map[string]string{path.Base(platform.DownloadURL): tt.pinned}
+
+			got, err := VerifyNodeArchive(platform, archivePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyNodeArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != sum {
+				t.Errorf("VerifyNodeArchive() = %q, want %q", got, sum)
+			}
+			if source := NodeChecksumSource(path.Base(platform.DownloadURL)); source != ChecksumSourcePinned {
+				t.Errorf("NodeChecksumSource() = %q, want %q", source, ChecksumSourcePinned)
+			}
+		})
+	}
+}
+
+func TestNodeArchiveChecksumsPinned(t *testing.T) {
+	if len(nodeArchiveSHA256) == 0 {
+		t.Skip("no Node.js checksums pinned; run go generate ./pkg/bootstrap")
+	}
+	for _, name := range NodeArchiveNames() {
+		sum, ok := nodeArchiveSHA256[name]
+		if !ok {
+			t.Errorf("no SHA-256 pinned for %s", name)
+			continue
+		}
+		if len(sum) != 64 || strings.Trim(sum, "0123456789abcdef") != "" {
+			t.Errorf("SHA-256 pinned for %s is not a hex digest: %q", name, sum)
+		}
+	}
+}
+
+func TestNodeArchiveNames(t *testing.T) {
+	names := NodeArchiveNames()
+	if len(names) != len(supportedPlatforms) {
+		t.Fatalf("NodeArchiveNames() returned %d names, want %d", len(names), len(supportedPlatforms))
+	}
+	for _, name := range names {
+		if !strings.HasPrefix(name, "node-"+NodeVersion+"-") {
+			t.Errorf("archive name %q does not carry NodeVersion %s", name, NodeVersion)
+		}
+	}
+}
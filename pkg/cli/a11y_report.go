@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"encoding/json"
+	"html/template"
+	"os"
+	"time"
+)
+
+// a11yAuditResult mirrors the runner's a11y-audit response data
+type a11yAuditResult struct {
+	URL            string          `json:"url"`
+	Title          string          `json:"title"`
+	RulesChecked   []string        `json:"rules_checked"`
+	Passes         []string        `json:"passes"`
+	Violations     []a11yViolation `json:"violations"`
+	ViolationCount int             `json:"violation_count"`
+	Summary        map[string]int  `json:"summary"`
+}
+
+type a11yViolation struct {
+	Rule        string     `json:"rule"`
+	Impact      string     `json:"impact"`
+	Description string     `json:"description"`
+	Help        string     `json:"help"`
+	NodeCount   int        `json:"node_count"`
+	Nodes       []a11yNode `json:"nodes"`
+}
+
+type a11yNode struct {
+	Selector string `json:"selector"`
+	HTML     string `json:"html"`
+	Message  string `json:"message"`
+
+	// Set by the color-contrast rule
+	ContrastRatio *float64 `json:"contrast_ratio,omitempty"`
+	RequiredRatio *float64 `json:"required_ratio,omitempty"`
+}
+
+// decodeA11yAuditResult converts the generic runner data into a typed result
+func decodeA11yAuditResult(data map[string]interface{}) (*a11yAuditResult, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result a11yAuditResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+var a11yReportTemplate = template.Must(template.New("a11y-report").Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="utf-8">
+<title>Accessibility audit – {{.Result.Title}}</title>
+<style>
+  body { font-family: -apple-system, "Apple SD Gothic Neo", "Malgun Gothic", sans-serif; margin: 2rem; color: #1f2328; }
+  h1 { font-size: 1.5rem; }
+  table { border-collapse: collapse; margin: 1rem 0; }
+  th, td { border: 1px solid #d0d7de; padding: .4rem .8rem; text-align: left; vertical-align: top; }
+  .critical { color: #a40e26; } .serious { color: #bc4c00; } .moderate { color: #7d4e00; } .minor { color: #57606a; }
+  code { background: #f6f8fa; padding: .1rem .3rem; word-break: break-all; }
+  section { border-top: 1px solid #d0d7de; margin-top: 1.5rem; }
+</style>
+</head>
+<body>
+<h1>Accessibility audit</h1>
+<p><strong>{{.Result.Title}}</strong><br><code>{{.Result.URL}}</code><br>Generated {{.GeneratedAt}}</p>
+
+<table>
+  <tr><th>Impact</th><th>Issues</th></tr>
+  <tr><td class="critical">critical</td><td>{{index .Result.Summary "critical"}}</td></tr>
+  <tr><td class="serious">serious</td><td>{{index .Result.Summary "serious"}}</td></tr>
+  <tr><td class="moderate">moderate</td><td>{{index .Result.Summary "moderate"}}</td></tr>
+  <tr><td class="minor">minor</td><td>{{index .Result.Summary "minor"}}</td></tr>
+</table>
+<p>Passed rules: {{range $i, $rule := .Result.Passes}}{{if $i}}, {{end}}<code>{{$rule}}</code>{{else}}none{{end}}</p>
+
+{{range .Result.Violations}}
+<section>
+  <h2 class="{{.Impact}}">{{.Rule}} ({{.Impact}}, {{.NodeCount}})</h2>
+  <p>{{.Description}}. {{.Help}}.</p>
+  <table>
+    <tr><th>Selector</th><th>Issue</th><th>HTML</th></tr>
+    {{range .Nodes}}<tr><td><code>{{.Selector}}</code></td><td>{{.Message}}</td><td><code>{{.HTML}}</code></td></tr>
+    {{end}}
+  </table>
+</section>
+{{else}}
+<p>No violations found.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// writeA11yReport renders the audit result as a standalone HTML report
+func writeA11yReport(path string, result *a11yAuditResult) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a11yReportTemplate.Execute(file, map[string]interface{}{
+		"Result":      result,
+		"GeneratedAt": time.Now().Format(time.RFC3339),
+	})
+}
@@ -2,21 +2,29 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/proxy"
 	"github.com/oa-plugins/webauto/pkg/response"
 	"github.com/spf13/cobra"
 )
 
 var (
-	browserType        string
-	headless           bool
-	noHeadless         bool
-	viewportWidth      int
-	viewportHeight     int
-	userAgent          string
-	launchSessionID    string
+	browserType     string
+	headless        bool
+	noHeadless      bool
+	viewportWidth   int
+	viewportHeight  int
+	userAgent       string
+	launchSessionID string
+	sharedBrowser   bool
+	launchProxy     string
+	proxyBypass     []string
+	proxyPoolPath   string
 )
 
 var browserLaunchCmd = &cobra.Command{
@@ -34,6 +42,10 @@ func init() {
 	browserLaunchCmd.Flags().IntVar(&viewportHeight, "viewport-height", 1080, "Viewport height")
 	browserLaunchCmd.Flags().StringVar(&userAgent, "user-agent", "", "User-Agent override")
 	browserLaunchCmd.Flags().StringVar(&launchSessionID, "session-id", "", "Session ID (optional, auto-generated if not provided)")
+	browserLaunchCmd.Flags().BoolVar(&sharedBrowser, "shared-browser", false, "Open the session as a new browser context in a shared browser process")
+	browserLaunchCmd.Flags().StringVar(&launchProxy, "proxy", "", "Proxy server URL (http://, https:// or socks5://, optionally with user:password@)")
+	browserLaunchCmd.Flags().StringSliceVar(&proxyBypass, "proxy-bypass", nil, "Hosts that bypass the proxy (e.g. localhost,.internal.example.com)")
+	browserLaunchCmd.Flags().StringVar(&proxyPoolPath, "proxy-pool", "", "Proxy pool file to take the session's proxy from (default: PROXY_POOL_PATH)")
 }
 
 func runBrowserLaunch(cmd *cobra.Command, args []string) {
@@ -45,28 +57,56 @@ func runBrowserLaunch(cmd *cobra.Command, args []string) {
 		headless = false
 	}
 
+	sessionProxy, err := launchProxySettings()
+	if err != nil {
+		resp := response.Error(
+			"INVALID_PROXY",
+			err.Error(),
+			"Use --proxy scheme://[user:password@]host:port with scheme http, https or socks5; --proxy-bypass requires --proxy",
+			map[string]interface{}{
+				"proxy": proxy.Redact(launchProxy),
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
 	// Get global session manager (singleton pattern)
 	sessionMgr := playwright.GetGlobalSessionManager()
 
 	// Create browser session with optional session ID
-	session, err := sessionMgr.Create(ctx, browserType, headless, launchSessionID)
+	session, err := sessionMgr.CreateWithOptions(ctx, playwright.LaunchOptions{
+		BrowserType:   browserType,
+		Headless:      headless,
+		SessionID:     launchSessionID,
+		SharedBrowser: sharedBrowser,
+		Proxy:         sessionProxy,
+		ProxyPoolPath: proxyPoolPath,
+	})
 	if err != nil {
+		code, recovery := response.ErrBrowserLaunchFailed, "Check Playwright installation and browser binaries"
+		if errors.Is(err, playwright.ErrBrowserNotInstalled) {
+			code = response.ErrPlaywrightNotInstalled
+			recovery = "Install the browser with: webauto runtime install-browser " + browserType + " (or set BROWSER_AUTO_INSTALL=true to install on first launch)"
+		}
 		resp := response.Error(
-			response.ErrBrowserLaunchFailed,
+			code,
 			"Failed to launch browser: "+err.Error(),
-			"Check Playwright installation and browser binaries",
+			recovery,
 			map[string]interface{}{
-				"browser_type": browserType,
-				"headless":     headless,
+				"browser_type":   browserType,
+				"headless":       headless,
+				"shared_browser": sharedBrowser,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Success response
-	resp := response.Success(map[string]interface{}{
+	data := map[string]interface{}{
 		"session_id":   session.ID,
 		"browser_type": session.BrowserType,
 		"headless":     session.Headless,
@@ -74,8 +114,36 @@ func runBrowserLaunch(cmd *cobra.Command, args []string) {
 			"width":  viewportWidth,
 			"height": viewportHeight,
 		},
-		"user_agent":  userAgent,
-		"created_at":  session.CreatedAt.Format(time.RFC3339),
-	}, startTime)
-	resp.Print()
+		"user_agent": userAgent,
+		"created_at": session.CreatedAt.Format(time.RFC3339),
+		"warm_start": session.Pooled,
+	}
+	if session.SharedBrowser {
+		data["shared_browser"] = true
+		data["runner_id"] = session.RunnerID
+		data["context_id"] = session.ContextID
+	}
+	if session.Proxy != nil {
+		data["proxy"] = session.Proxy
+	}
+
+	resp := response.Success(data, startTime)
+	printResponse(resp)
+}
+
+// launchProxySettings builds the session proxy from --proxy and --proxy-bypass
+func launchProxySettings() (*proxy.Proxy, error) {
+	if launchProxy == "" {
+		if len(proxyBypass) > 0 {
+			return nil, fmt.Errorf("--proxy-bypass requires --proxy")
+		}
+		return nil, nil
+	}
+
+	sessionProxy, err := proxy.Parse(launchProxy)
+	if err != nil {
+		return nil, err
+	}
+	sessionProxy.Bypass = strings.Join(proxyBypass, ",")
+	return sessionProxy, nil
 }
@@ -44,7 +44,7 @@ func runBrowserClose(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -60,7 +60,7 @@ func runBrowserClose(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -69,7 +69,7 @@ func runBrowserClose(cmd *cobra.Command, args []string) {
 		"session_id": sessionID,
 		"closed_at":  time.Now().Format(time.RFC3339),
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 
 	_ = ctx // Avoid unused variable warning
 }
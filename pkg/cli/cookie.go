@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/cookies"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// Cookie command error codes
+const (
+	errCookieOperationFailed = "COOKIE_OPERATION_FAILED"
+	errInvalidCookie         = "INVALID_COOKIE"
+)
+
+// cookieRecovery is the recovery hint of a cookie command the runner rejected
+const cookieRecovery = "Check the cookie fields; a cookie needs a url or a domain and path"
+
+var (
+	cookieDomain   string
+	cookiePath     string
+	cookieName     string
+	cookieValue    string
+	cookieURL      string
+	cookieExpires  int64
+	cookieHTTPOnly bool
+	cookieSecure   bool
+	cookieSameSite string
+)
+
+var cookieListCmd = &cobra.Command{
+	Use:   "cookie-list",
+	Short: "List the cookies of a session",
+	Long: `List the cookies of a session's browser context, optionally filtered.
+
+--domain also matches subdomains (example.com matches .www.example.com);
+--path and --name match exactly.`,
+	Run: runCookieList,
+}
+
+var cookieSetCmd = &cobra.Command{
+	Use:   "cookie-set",
+	Short: "Set a cookie in a session",
+	Long: `Add a cookie to a session's browser context, replacing a cookie with the
+same name, domain and path.
+
+The cookie is scoped either by --url or by --domain (with --path, default /).
+A domain with a leading dot (.example.com) also covers subdomains.`,
+	Run: runCookieSet,
+}
+
+var cookieDeleteCmd = &cobra.Command{
+	Use:   "cookie-delete",
+	Short: "Delete the cookies matching a filter",
+	Long: `Delete the cookies of a session that match --domain, --path and --name.
+At least one filter is required; use cookie-clear to delete every cookie.`,
+	Run: runCookieDelete,
+}
+
+var cookieClearCmd = &cobra.Command{
+	Use:   "cookie-clear",
+	Short: "Delete all cookies of a session",
+	Run:   runCookieClear,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{cookieListCmd, cookieSetCmd, cookieDeleteCmd, cookieClearCmd} {
+		cmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+		cmd.MarkFlagRequired("session-id")
+	}
+
+	addCookieFilterFlags(cookieListCmd)
+	addCookieFilterFlags(cookieDeleteCmd)
+
+	cookieSetCmd.Flags().StringVar(&cookieName, "name", "", "Cookie name (required)")
+	cookieSetCmd.Flags().StringVar(&cookieValue, "value", "", "Cookie value")
+	cookieSetCmd.Flags().StringVar(&cookieURL, "url", "", "URL the cookie belongs to (instead of --domain/--path)")
+	cookieSetCmd.Flags().StringVar(&cookieDomain, "domain", "", "Cookie domain (leading dot to include subdomains)")
+	cookieSetCmd.Flags().StringVar(&cookiePath, "path", "", "Cookie path (default / with --domain)")
+	cookieSetCmd.Flags().Int64Var(&cookieExpires, "expires", -1, "Expiry as a Unix timestamp in seconds (-1 for a session cookie)")
+	cookieSetCmd.Flags().BoolVar(&cookieHTTPOnly, "http-only", false, "Hide the cookie from JavaScript")
+	cookieSetCmd.Flags().BoolVar(&cookieSecure, "secure", false, "Only send the cookie over HTTPS")
+	cookieSetCmd.Flags().StringVar(&cookieSameSite, "same-site", "", "SameSite policy (Strict|Lax|None)")
+	cookieSetCmd.MarkFlagRequired("name")
+}
+
+// addCookieFilterFlags registers --domain, --path and --name as filters
+func addCookieFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cookieDomain, "domain", "", "Only cookies of this domain and its subdomains")
+	cmd.Flags().StringVar(&cookiePath, "path", "", "Only cookies with exactly this path")
+	cmd.Flags().StringVar(&cookieName, "name", "", "Only cookies with exactly this name")
+}
+
+// cookieFilter returns the filter given by --domain, --path and --name
+func cookieFilter() cookies.Filter {
+	return cookies.Filter{Domain: cookieDomain, Path: cookiePath, Name: cookieName}
+}
+
+// filterDetails describes a filter in error details
+func filterDetails(filter cookies.Filter) map[string]interface{} {
+	details := map[string]interface{}{"session_id": sessionID}
+	if filter.Domain != "" {
+		details["domain"] = filter.Domain
+	}
+	if filter.Path != "" {
+		details["path"] = filter.Path
+	}
+	if filter.Name != "" {
+		details["name"] = filter.Name
+	}
+	return details
+}
+
+// fetchCookies returns the session's cookies that pass the filter
+func fetchCookies(ctx context.Context, sessionMgr *playwright.SessionManager, filter cookies.Filter) ([]cookies.Cookie, bool, error) {
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, map[string]interface{}{"command": "cookies-get"})
+	if err != nil {
+		return nil, sessionErr, err
+	}
+
+	all, err := cookies.FromData(result.Data["cookies"])
+	if err != nil {
+		return nil, false, err
+	}
+	return filter.Apply(all), false, nil
+}
+
+func runCookieList(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	filter := cookieFilter()
+	matched, sessionErr, err := fetchCookies(ctx, sessionMgr, filter)
+	if err != nil {
+		printResponse(commandFailure(err, sessionErr, "list cookies", errCookieOperationFailed, cookieRecovery, filterDetails(filter), startTime))
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id":   sessionID,
+		"cookies":      matched,
+		"cookie_count": len(matched),
+	}, startTime)
+	printResponse(resp)
+}
+
+func runCookieSet(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	cookie, err := cookieFromFlags()
+	if err != nil {
+		resp := response.Error(
+			errInvalidCookie,
+			"Invalid cookie: "+err.Error(),
+			"Give --url, or --domain with an optional --path; --same-site takes Strict, Lax or None",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"name":       cookieName,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	setCmd := map[string]interface{}{
+		"command": "cookies-add",
+		"cookies": []cookies.Cookie{*cookie},
+	}
+	if _, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, setCmd); err != nil {
+		details := map[string]interface{}{
+			"session_id": sessionID,
+			"name":       cookieName,
+		}
+		printResponse(commandFailure(err, sessionErr, "set cookie", errCookieOperationFailed, cookieRecovery, details, startTime))
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id": sessionID,
+		"cookie":     cookie,
+		"set":        true,
+	}, startTime)
+	printResponse(resp)
+}
+
+// cookieFromFlags builds the cookie given to cookie-set
+func cookieFromFlags() (*cookies.Cookie, error) {
+	cookie := &cookies.Cookie{
+		Name:     cookieName,
+		Value:    cookieValue,
+		Expires:  float64(cookieExpires),
+		HTTPOnly: cookieHTTPOnly,
+		Secure:   cookieSecure,
+	}
+
+	switch {
+	case cookieURL != "" && (cookieDomain != "" || cookiePath != ""):
+		return nil, fmt.Errorf("--url cannot be combined with --domain or --path")
+	case cookieURL != "":
+		cookie.URL = cookieURL
+	case cookieDomain != "":
+		cookie.Domain = cookieDomain
+		cookie.Path = cookiePath
+		if cookie.Path == "" {
+			cookie.Path = "/"
+		}
+	default:
+		return nil, fmt.Errorf("--url or --domain is required")
+	}
+
+	if cookieExpires == 0 || cookieExpires < -1 {
+		return nil, fmt.Errorf("--expires must be a Unix timestamp or -1")
+	}
+
+	if cookieSameSite != "" {
+		switch strings.ToLower(cookieSameSite) {
+		case "strict":
+			cookie.SameSite = "Strict"
+		case "lax":
+			cookie.SameSite = "Lax"
+		case "none":
+			cookie.SameSite = "None"
+		default:
+			return nil, fmt.Errorf("unknown SameSite policy %q", cookieSameSite)
+		}
+	}
+	return cookie, nil
+}
+
+func runCookieDelete(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	filter := cookieFilter()
+	if filter.IsEmpty() {
+		resp := response.Error(
+			errInvalidCookie,
+			"No cookie filter given",
+			"Pass --domain, --path or --name, or use cookie-clear to delete every cookie",
+			map[string]interface{}{
+				"session_id": sessionID,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	matched, sessionErr, err := fetchCookies(ctx, sessionMgr, filter)
+	if err == nil && len(matched) > 0 {
+		deleteCmd := map[string]interface{}{
+			"command": "cookies-delete",
+			"cookies": matched,
+		}
+		_, sessionErr, err = sendCheckedCommand(ctx, sessionMgr, deleteCmd)
+	}
+	if err != nil {
+		printResponse(commandFailure(err, sessionErr, "delete cookies", errCookieOperationFailed, cookieRecovery, filterDetails(filter), startTime))
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id":    sessionID,
+		"deleted":       matched,
+		"deleted_count": len(matched),
+	}, startTime)
+	printResponse(resp)
+}
+
+func runCookieClear(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, map[string]interface{}{"command": "cookies-clear"})
+	if err != nil {
+		details := map[string]interface{}{
+			"session_id": sessionID,
+		}
+		printResponse(commandFailure(err, sessionErr, "clear cookies", errCookieOperationFailed, cookieRecovery, details, startTime))
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id":    sessionID,
+		"deleted_count": result.Data["deleted"],
+	}, startTime)
+	printResponse(resp)
+}
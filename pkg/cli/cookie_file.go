@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/cookies"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cookieFilePath    string
+	cookieFileFormat  string
+	cookieImportClear bool
+)
+
+var cookieExportCmd = &cobra.Command{
+	Use:   "cookie-export",
+	Short: "Export session cookies to a JSON or cookies.txt file",
+	Long: `Write the cookies of a session to a file, optionally filtered by --domain,
+--path and --name.
+
+Formats:
+  json      JSON array of cookies in Playwright's format (default)
+  netscape  Netscape cookies.txt, usable with curl -b / wget --load-cookies
+
+Without --format, a .txt output path selects netscape. The file is created
+readable by the owner only.`,
+	Run: runCookieExport,
+}
+
+var cookieImportCmd = &cobra.Command{
+	Use:   "cookie-import",
+	Short: "Import cookies from a JSON or cookies.txt file",
+	Long: `Add the cookies of a file to a session.
+
+Accepts a JSON cookie array, a Playwright storage state file or a Netscape
+cookies.txt file; without --format the format is detected from the content.
+Expired cookies are skipped.`,
+	Run: runCookieImport,
+}
+
+func init() {
+	cookieExportCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	cookieExportCmd.Flags().StringVar(&cookieFilePath, "output-path", "", "Output file path (required)")
+	cookieExportCmd.Flags().StringVar(&cookieFileFormat, "format", "", "File format (json|netscape, default from the file extension)")
+	addCookieFilterFlags(cookieExportCmd)
+
+	cookieExportCmd.MarkFlagRequired("session-id")
+	cookieExportCmd.MarkFlagRequired("output-path")
+
+	cookieImportCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	cookieImportCmd.Flags().StringVar(&cookieFilePath, "input-path", "", "Cookie file path (required)")
+	cookieImportCmd.Flags().StringVar(&cookieFileFormat, "format", "", "File format (json|netscape, default detected from the content)")
+	cookieImportCmd.Flags().BoolVar(&cookieImportClear, "clear", false, "Delete the session's cookies before importing")
+
+	cookieImportCmd.MarkFlagRequired("session-id")
+	cookieImportCmd.MarkFlagRequired("input-path")
+}
+
+func runCookieExport(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	format, err := cookies.ParseFormat(cookieFileFormat, cookieFilePath)
+	if err != nil {
+		resp := response.Error(
+			errInvalidCookie,
+			err.Error(),
+			"Use --format json or --format netscape",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"format":     cookieFileFormat,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	filter := cookieFilter()
+	matched, sessionErr, err := fetchCookies(ctx, sessionMgr, filter)
+	if err != nil {
+		printResponse(commandFailure(err, sessionErr, "export cookies", errCookieOperationFailed, cookieRecovery, filterDetails(filter), startTime))
+		return
+	}
+
+	if err := cookies.WriteFile(cookieFilePath, format, matched); err != nil {
+		resp := response.Error(
+			errCookieOperationFailed,
+			err.Error(),
+			"Check file path and permissions",
+			map[string]interface{}{
+				"session_id":  sessionID,
+				"output_path": cookieFilePath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id":   sessionID,
+		"output_path":  cookieFilePath,
+		"format":       format,
+		"cookie_count": len(matched),
+	}, startTime)
+	printResponse(resp)
+}
+
+func runCookieImport(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	format := ""
+	if cookieFileFormat != "" {
+		var err error
+		if format, err = cookies.ParseFormat(cookieFileFormat, cookieFilePath); err != nil {
+			resp := response.Error(
+				errInvalidCookie,
+				err.Error(),
+				"Use --format json or --format netscape, or omit it to detect the format",
+				map[string]interface{}{
+					"session_id": sessionID,
+					"format":     cookieFileFormat,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+	}
+
+	parsed, format, err := cookies.ReadFile(cookieFilePath, format)
+	if err != nil {
+		resp := response.Error(
+			errInvalidCookie,
+			err.Error(),
+			"Provide a JSON cookie array, a Playwright storage state file or a Netscape cookies.txt file",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"input_path": cookieFilePath,
+				"format":     format,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Adding an expired cookie would only delete the browser's copy
+	now := float64(time.Now().Unix())
+	valid := make([]cookies.Cookie, 0, len(parsed))
+	for _, c := range parsed {
+		if c.Expires == -1 || c.Expires > now {
+			valid = append(valid, c)
+		}
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	details := map[string]interface{}{
+		"session_id": sessionID,
+		"input_path": cookieFilePath,
+	}
+
+	if cookieImportClear {
+		if _, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, map[string]interface{}{"command": "cookies-clear"}); err != nil {
+			printResponse(commandFailure(err, sessionErr, "clear cookies", errCookieOperationFailed, cookieRecovery, details, startTime))
+			return
+		}
+	}
+
+	if len(valid) > 0 {
+		importCmd := map[string]interface{}{
+			"command": "cookies-add",
+			"cookies": valid,
+		}
+		if _, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, importCmd); err != nil {
+			printResponse(commandFailure(err, sessionErr, "import cookies", errCookieOperationFailed, cookieRecovery, details, startTime))
+			return
+		}
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id":      sessionID,
+		"input_path":      cookieFilePath,
+		"format":          format,
+		"imported_count":  len(valid),
+		"skipped_expired": len(parsed) - len(valid),
+		"cleared":         cookieImportClear,
+	}, startTime)
+	printResponse(resp)
+}
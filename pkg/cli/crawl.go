@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/crawl"
+	"github.com/oa-plugins/webauto/pkg/extract"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	crawlSeeds          []string
+	crawlSeedsFile      string
+	crawlConcurrency    int
+	crawlMaxDepth       int
+	crawlMaxPages       int
+	crawlSchemaPath     string
+	crawlSameDomain     bool
+	crawlRespectRobots  bool
+	crawlUserAgent      string
+	crawlBrowserType    string
+	crawlHeadless       bool
+	crawlWaitUntil      string
+	crawlTimeout        int
+	crawlOutputPath     string
+	crawlCheckpointPath string
+	crawlResume         bool
+)
+
+var crawlCmd = &cobra.Command{
+	Use:   "crawl",
+	Short: "Crawl sites concurrently with a pool of browser sessions",
+	Long: `Crawl seed URLs with a pool of browser sessions working a shared queue.
+
+Each page is loaded, optionally run through a page-extract schema, and, while
+below --max-depth, its links are queued (same domain as the seeds by default).
+robots.txt rules are honoured unless --respect-robots=false; while a site's
+robots.txt is unreachable (network error or HTTP 5xx) its URLs are skipped as
+ROBOTS_UNAVAILABLE. The pool size is --concurrency, capped by the free
+SESSION_MAX_COUNT slots; per-domain rate limits and --retries apply to every
+page.
+
+Every visit is appended to --output-path as one NDJSON line with "type"
+page, failure or skipped. The crawl state is checkpointed every 25 pages or
+10 seconds and when the crawl stops; rerun with --resume to continue an
+interrupted crawl (Ctrl+C is safe). After a hard kill, pages finished since the
+last checkpoint are visited again.
+
+Examples:
+  webauto crawl --seed https://example.com --max-depth 2 --output-path crawl.ndjson
+  webauto crawl --seeds-file urls.txt --concurrency 4 --schema article.json \
+    --output-path articles.ndjson --resume`,
+	Run: runCrawl,
+}
+
+func init() {
+	crawlCmd.Flags().StringArrayVar(&crawlSeeds, "seed", nil, "Seed URL (repeatable)")
+	crawlCmd.Flags().StringVar(&crawlSeedsFile, "seeds-file", "", "File with one seed URL per line (# comments allowed)")
+	crawlCmd.Flags().IntVar(&crawlConcurrency, "concurrency", 2, "Number of browser sessions working in parallel")
+	crawlCmd.Flags().IntVar(&crawlMaxDepth, "max-depth", 0, "Link depth to follow from the seeds (0 = seeds only)")
+	crawlCmd.Flags().IntVar(&crawlMaxPages, "max-pages", 100, "Maximum number of pages to visit (0 = unlimited)")
+	crawlCmd.Flags().StringVar(&crawlSchemaPath, "schema", "", "Extraction schema file path or inline JSON run on every page (optional)")
+	crawlCmd.Flags().BoolVar(&crawlSameDomain, "same-domain", true, "Only follow links to the seed domains")
+	crawlCmd.Flags().BoolVar(&crawlRespectRobots, "respect-robots", true, "Skip URLs disallowed by robots.txt")
+	crawlCmd.Flags().StringVar(&crawlUserAgent, "robots-user-agent", "webauto", "User agent token matched against robots.txt groups")
+	crawlCmd.Flags().StringVar(&crawlBrowserType, "browser-type", "chromium", "Browser type (chromium|firefox|webkit)")
+	crawlCmd.Flags().BoolVar(&crawlHeadless, "headless", true, "Headless mode")
+	crawlCmd.Flags().StringVar(&crawlWaitUntil, "wait-until", "load", "Navigation wait condition (load|domcontentloaded|networkidle)")
+	crawlCmd.Flags().IntVar(&crawlTimeout, "timeout-ms", 30000, "Timeout for each page command in milliseconds")
+	crawlCmd.Flags().StringVar(&crawlOutputPath, "output-path", "", "NDJSON results file (required)")
+	crawlCmd.Flags().StringVar(&crawlCheckpointPath, "checkpoint-path", "", "Checkpoint file (default: <output-path>.checkpoint.json)")
+	crawlCmd.Flags().BoolVar(&crawlResume, "resume", false, "Resume from the checkpoint instead of starting over")
+
+	crawlCmd.MarkFlagRequired("output-path")
+}
+
+func runCrawl(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	// Stop gracefully on Ctrl+C so the checkpoint reflects unfinished pages
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	seeds, err := loadSeeds(crawlSeeds, crawlSeedsFile)
+	if err != nil {
+		resp := response.Error(
+			"INVALID_SEEDS",
+			"Failed to read seeds: "+err.Error(),
+			"Check the --seeds-file path",
+			map[string]interface{}{
+				"seeds_file": crawlSeedsFile,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+	if len(seeds) == 0 && !crawlResume {
+		resp := response.Error(
+			"INVALID_SEEDS",
+			"No seed URLs provided",
+			"Provide --seed or --seeds-file, or --resume an earlier crawl",
+			nil,
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	var schema *extract.Schema
+	if crawlSchemaPath != "" {
+		schema, err = extract.LoadSchema(crawlSchemaPath)
+		if err != nil {
+			resp := response.Error(
+				"INVALID_SCHEMA",
+				"Invalid extraction schema: "+err.Error(),
+				"Check the schema file against the format shown in 'webauto page-extract --help'",
+				map[string]interface{}{
+					"schema": crawlSchemaPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	crawler, err := crawl.New(sessionMgr, crawl.Options{
+		Seeds:          seeds,
+		Concurrency:    crawlConcurrency,
+		MaxDepth:       crawlMaxDepth,
+		MaxPages:       crawlMaxPages,
+		SameDomain:     crawlSameDomain,
+		Robots:         crawlRespectRobots,
+		UserAgent:      crawlUserAgent,
+		Schema:         schema,
+		BrowserType:    crawlBrowserType,
+		Headless:       crawlHeadless,
+		Timeout:        time.Duration(crawlTimeout) * time.Millisecond,
+		WaitUntil:      crawlWaitUntil,
+		Retry:          retryPolicy(),
+		OutputPath:     crawlOutputPath,
+		CheckpointPath: crawlCheckpointPath,
+		Resume:         crawlResume,
+	})
+	var summary *crawl.Summary
+	if err == nil {
+		summary, err = crawler.Run(ctx)
+	}
+	if err != nil {
+		resp := response.Error(
+			"CRAWL_FAILED",
+			"Crawl failed: "+err.Error(),
+			"Check the seeds, SESSION_MAX_COUNT and the browser installation",
+			map[string]interface{}{
+				"output_path": crawlOutputPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	checkpointPath := crawlCheckpointPath
+	if checkpointPath == "" {
+		checkpointPath = crawlOutputPath + ".checkpoint.json"
+	}
+
+	// Success response
+	resp := response.Success(map[string]interface{}{
+		"sessions":        summary.Sessions,
+		"pages":           summary.Pages,
+		"failures":        summary.Failures,
+		"skipped":         summary.Skipped,
+		"discovered":      summary.Discovered,
+		"pending":         summary.Pending,
+		"resumed":         summary.Resumed,
+		"stop_reason":     summary.StopReason,
+		"output_path":     crawlOutputPath,
+		"checkpoint_path": checkpointPath,
+	}, startTime)
+	printResponse(resp)
+}
+
+// loadSeeds merges --seed values with the lines of --seeds-file
+func loadSeeds(seeds []string, path string) ([]string, error) {
+	result := append([]string{}, seeds...)
+	if path == "" {
+		return result, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result = append(result, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// GetCrawlCommand returns the crawl command for registration
+func GetCrawlCommand() *cobra.Command {
+	return crawlCmd
+}
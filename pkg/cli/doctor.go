@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+	"github.com/oa-plugins/webauto/pkg/doctor"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorBrowserTypes []string
+	doctorSkipLaunch   bool
+	doctorTimeoutMs    int
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment webauto runs in",
+	Long: `Run environment checks and report each with a status and a remediation hint:
+the cache directory, the Node.js binary, the Playwright import, a headless
+launch of each browser type, shared libraries, the browser sandbox, /dev/shm
+size, CJK font coverage and stale sessions.
+
+Fails with ENVIRONMENT_CHECK_FAILED when any check fails; warnings alone do
+not fail the command.`,
+	Run: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringSliceVar(&doctorBrowserTypes, "browser-type", nil, "Browser types to smoke-test (default: all)")
+	doctorCmd.Flags().BoolVar(&doctorSkipLaunch, "skip-launch", false, "Skip the browser launch smoke tests")
+	doctorCmd.Flags().IntVar(&doctorTimeoutMs, "timeout-ms", 60000, "Timeout for each browser launch smoke test in milliseconds")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	for _, browserType := range doctorBrowserTypes {
+		if !bootstrap.IsSupportedBrowser(browserType) {
+			resp := response.Error(
+				"INVALID_BROWSER_TYPE",
+				fmt.Sprintf("Unsupported browser type: %s", browserType),
+				"Use one of: "+strings.Join(bootstrap.SupportedBrowsers, ", "),
+				map[string]interface{}{
+					"browser_type": browserType,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+	}
+
+	report := doctor.Run(context.Background(), doctor.Options{
+		NodePath:      runtimeNodePath(),
+		BrowserTypes:  doctorBrowserTypes,
+		SkipLaunch:    doctorSkipLaunch,
+		LaunchTimeout: time.Duration(doctorTimeoutMs) * time.Millisecond,
+		Sessions:      playwright.GetGlobalSessionManager(),
+	})
+
+	failures := report.Failures()
+	if len(failures) > 0 {
+		names := make([]string, 0, len(failures))
+		remediations := make([]string, 0, len(failures))
+		for _, check := range failures {
+			names = append(names, check.Name)
+			if check.Remediation != "" {
+				remediations = append(remediations, fmt.Sprintf("%s: %s", check.Name, check.Remediation))
+			}
+		}
+
+		resp := response.Error(
+			"ENVIRONMENT_CHECK_FAILED",
+			fmt.Sprintf("%d environment check(s) failed: %s", len(failures), strings.Join(names, ", ")),
+			strings.Join(remediations, "; "),
+			report,
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	printResponse(response.Success(report, startTime))
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkSelector string
+	checkTimeout  int
+)
+
+var (
+	checkLocator   locatorFlags
+	uncheckLocator locatorFlags
+)
+
+var elementCheckCmd = &cobra.Command{
+	Use:   "element-check",
+	Short: "Check a checkbox or select a radio button",
+	Long: `Check a checkbox or radio button (or an element with role checkbox/radio).
+Nothing happens when it is already checked. The response reports the state
+read back afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runElementCheck(cmd, &checkLocator, true)
+	},
+}
+
+var elementUncheckCmd = &cobra.Command{
+	Use:   "element-uncheck",
+	Short: "Uncheck a checkbox",
+	Long: `Uncheck a checkbox. Radio buttons cannot be unchecked; check another
+radio button of the group instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runElementCheck(cmd, &uncheckLocator, false)
+	},
+}
+
+func init() {
+	for _, c := range []struct {
+		cmd     *cobra.Command
+		locator *locatorFlags
+	}{{elementCheckCmd, &checkLocator}, {elementUncheckCmd, &uncheckLocator}} {
+		c.cmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+		c.cmd.Flags().StringVar(&checkSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+		addLocatorFlags(c.cmd, c.locator)
+		c.cmd.Flags().IntVar(&checkTimeout, "timeout", 30000, "Timeout in milliseconds")
+
+		c.cmd.MarkFlagRequired("session-id")
+	}
+}
+
+func runElementCheck(cmd *cobra.Command, flags *locatorFlags, checked bool) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	action := "check"
+	if !checked {
+		action = "uncheck"
+	}
+
+	locator, err := flags.spec(cmd, checkSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	checkCmd := withLocator(map[string]interface{}{
+		"command": "check",
+		"checked": checked,
+		"timeout": checkTimeout,
+	}, locator)
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, checkCmd)
+	if err != nil {
+		resp := response.Error(
+			commandErrorCode(err, response.ErrElementNotFound),
+			"Failed to "+action+" element: "+err.Error(),
+			"Verify session ID and element selector",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": locator.String(),
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	if !result.Success {
+		resp := response.Error(
+			response.ErrElementNotClickable,
+			"Failed to "+action+" element: "+result.Error,
+			"Check that the element is a visible, enabled checkbox or radio button",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": locator.String(),
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	state, _ := result.Data["checked"].(bool)
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
+		"session_id":       sessionID,
+		"element_selector": locator.String(),
+		"checked":          state,
+		"verified":         state == checked,
+	}, result), startTime)
+	printResponse(resp)
+}
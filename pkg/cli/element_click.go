@@ -14,20 +14,33 @@ var (
 	clickTimeout    int
 )
 
+var (
+	clickLocator locatorFlags
+	clickMouse   mouseOptions
+)
+
 var elementClickCmd = &cobra.Command{
 	Use:   "element-click",
 	Short: "Click an element on the page",
-	Long:  `Click an element identified by a CSS selector.`,
-	Run:   runElementClick,
+	Long: `Click an element identified by a CSS selector or locator option.
+
+--button right opens context menus, --click-count 2 double-clicks (e.g. to edit
+grid cells), --modifiers holds keys such as Shift or Control during the click,
+and --position clicks a point relative to the element's top-left corner.`,
+	Run: runElementClick,
 }
 
 func init() {
 	elementClickCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	elementClickCmd.Flags().StringVar(&elementSelector, "element-selector", "", "CSS selector for the element (required)")
+	elementClickCmd.Flags().StringVar(&elementSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementClickCmd, &clickLocator)
+	elementClickCmd.Flags().StringVar(&clickMouse.button, "button", "left", "Mouse button (left|right|middle)")
+	elementClickCmd.Flags().IntVar(&clickMouse.clickCount, "click-count", 1, "Number of clicks (2 for a double-click)")
+	elementClickCmd.Flags().StringSliceVar(&clickMouse.modifiers, "modifiers", nil, "Modifier keys held during the click (Alt, Control, ControlOrMeta, Meta, Shift)")
+	elementClickCmd.Flags().StringVar(&clickMouse.position, "position", "", "Point to click as x,y relative to the element's top-left corner")
 	elementClickCmd.Flags().IntVar(&clickTimeout, "timeout", 30000, "Click timeout in milliseconds")
 
 	elementClickCmd.MarkFlagRequired("session-id")
-	elementClickCmd.MarkFlagRequired("element-selector")
 }
 
 func runElementClick(cmd *cobra.Command, args []string) {
@@ -37,26 +50,37 @@ func runElementClick(cmd *cobra.Command, args []string) {
 	// Get global session manager (singleton pattern)
 	sessionMgr := playwright.GetGlobalSessionManager()
 
+	locator, err := clickLocator.spec(cmd, elementSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
 	// Send click command to session
 	clickCmd := map[string]interface{}{
-		"command":  "click",
-		"selector": elementSelector,
-		"timeout":  clickTimeout,
+		"command": "click",
+		"timeout": clickTimeout,
 	}
+	if err := clickMouse.payload(clickCmd); err != nil {
+		printResponse(invalidMouseResponse(err, startTime))
+		return
+	}
+
+	withLocator(clickCmd, locator)
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, clickCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, clickCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrElementNotFound,
+			commandErrorCode(err, response.ErrElementNotFound),
 			"Failed to click element: "+err.Error(),
 			"Verify session ID and element selector",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": elementSelector,
+				"element_selector": locator.String(),
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -67,20 +91,22 @@ func runElementClick(cmd *cobra.Command, args []string) {
 			"Check if element is visible and clickable",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": elementSelector,
+				"element_selector": locator.String(),
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Success response
-	resp := response.Success(map[string]interface{}{
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
 		"session_id":       sessionID,
-		"element_selector": elementSelector,
+		"element_selector": locator.String(),
 		"clicked":          true,
+		"button":           clickMouse.button,
+		"click_count":      clickMouse.clickCount,
 		"timeout_ms":       clickTimeout,
-	}, startTime)
-	resp.Print()
+	}, result), startTime)
+	printResponse(resp)
 }
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/export"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractTableSelector     string
+	extractTableHeaderRows   int
+	extractTableNextSelector string
+	extractTableMaxPages     int
+	extractTableFormat       string
+	extractTableOutputPath   string
+	extractTableTimeout      int
+)
+
+var elementExtractTableCmd = &cobra.Command{
+	Use:   "element-extract-table",
+	Short: "Extract an HTML table as records",
+	Long: `Extract an HTML table into a rectangular grid and return it as records keyed by header.
+
+Header rows are detected from <thead> or leading rows made only of <th> cells
+(override with --header-rows). Multi-row headers are joined with " / ".
+colspan/rowspan cells are repeated into every position they cover.
+
+With --next-selector the command clicks the pagination control after each
+page and appends the rows of the following pages, up to --max-pages. It stops
+early when the control is missing or disabled, or the table does not change.
+
+Output formats: json (records), csv, xlsx. csv/xlsx require --output-path;
+the format defaults to the --output-path extension.
+
+Examples:
+  webauto element-extract-table --session-id ses_abc --element-selector "table#prices"
+  webauto element-extract-table --session-id ses_abc --element-selector "#result table" \
+    --next-selector "a.next" --max-pages 5 --output-path prices.xlsx`,
+	Run: runElementExtractTable,
+}
+
+func init() {
+	elementExtractTableCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	elementExtractTableCmd.Flags().StringVar(&extractTableSelector, "element-selector", "table", "CSS selector or XPath of the table (first match is used)")
+	elementExtractTableCmd.Flags().IntVar(&extractTableHeaderRows, "header-rows", -1, "Number of header rows (-1 to detect, 0 for none)")
+	elementExtractTableCmd.Flags().StringVar(&extractTableNextSelector, "next-selector", "", "Selector of the pagination control to follow (optional)")
+	elementExtractTableCmd.Flags().IntVar(&extractTableMaxPages, "max-pages", 10, "Maximum number of pages to extract when following --next-selector")
+	elementExtractTableCmd.Flags().StringVar(&extractTableFormat, "format", "", "Output format: json, csv, xlsx (default from --output-path extension, else json)")
+	elementExtractTableCmd.Flags().StringVar(&extractTableOutputPath, "output-path", "", "Output file path (optional, omit to return records in JSON)")
+	elementExtractTableCmd.Flags().IntVar(&extractTableTimeout, "timeout-ms", 30000, "Timeout in milliseconds")
+
+	elementExtractTableCmd.MarkFlagRequired("session-id")
+}
+
+func runElementExtractTable(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	format := strings.ToLower(extractTableFormat)
+	if format == "" {
+		format = export.FormatFromPath(extractTableOutputPath)
+	}
+	if format != export.FormatJSON && format != export.FormatCSV && format != export.FormatXLSX {
+		resp := response.Error(
+			"INVALID_OUTPUT_FORMAT",
+			fmt.Sprintf("Unsupported output format: %s", extractTableFormat),
+			"Use --format json, csv or xlsx",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"format":     extractTableFormat,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+	if format != export.FormatJSON && extractTableOutputPath == "" {
+		resp := response.Error(
+			"INVALID_OUTPUT_FORMAT",
+			fmt.Sprintf("Format %s requires --output-path", format),
+			"Provide --output-path or use --format json",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"format":     format,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Send extract-table command to session
+	extractCmd := map[string]interface{}{
+		"command":  "extract-table",
+		"selector": extractTableSelector,
+		"timeout":  extractTableTimeout,
+	}
+	if extractTableHeaderRows >= 0 {
+		extractCmd["headerRows"] = extractTableHeaderRows
+	}
+	if extractTableNextSelector != "" {
+		extractCmd["nextSelector"] = extractTableNextSelector
+		extractCmd["maxPages"] = extractTableMaxPages
+	}
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, extractCmd)
+	if err != nil {
+		resp := response.Error(
+			commandErrorCode(err, response.ErrElementNotFound),
+			"Failed to extract table: "+err.Error(),
+			"Verify session ID and table selector",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": extractTableSelector,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	if !result.Success {
+		resp := response.Error(
+			response.ErrElementNotFound,
+			"Extract table failed: "+result.Error,
+			"Check that the selector matches a <table> element",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": extractTableSelector,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	headers := recordKeys(stringSlice(result.Data["headers"]))
+	rows := stringRows(result.Data["rows"])
+
+	responseData := map[string]interface{}{
+		"session_id":       sessionID,
+		"element_selector": extractTableSelector,
+		"headers":          headers,
+		"row_count":        len(rows),
+		"column_count":     len(headers),
+		"pages":            result.Data["pages"],
+		"stop_reason":      result.Data["stop_reason"],
+	}
+	if caption, ok := result.Data["caption"].(string); ok && caption != "" {
+		responseData["caption"] = caption
+	}
+
+	records := tableRecords(headers, rows)
+
+	if extractTableOutputPath == "" {
+		responseData["records"] = records
+		printResponse(response.Success(responseData, startTime))
+		return
+	}
+
+	var writeErr error
+	switch format {
+	case export.FormatCSV:
+		writeErr = export.WriteCSV(extractTableOutputPath, headers, rows)
+	case export.FormatXLSX:
+		writeErr = export.WriteXLSX(extractTableOutputPath, headers, rows)
+	default:
+		var content []byte
+		content, writeErr = json.MarshalIndent(records, "", "  ")
+		if writeErr == nil {
+			writeErr = os.WriteFile(extractTableOutputPath, content, 0644)
+		}
+	}
+	if writeErr != nil {
+		resp := response.Error(
+			response.ErrPageLoadFailed,
+			"Failed to write table file: "+writeErr.Error(),
+			"Check file path and permissions",
+			map[string]interface{}{
+				"session_id":  sessionID,
+				"output_path": extractTableOutputPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	responseData["output_path"] = extractTableOutputPath
+	responseData["format"] = format
+
+	// Success response
+	resp := response.Success(responseData, startTime)
+	printResponse(resp)
+}
+
+// recordKeys turns header labels into unique, non-empty record keys
+func recordKeys(headers []string) []string {
+	keys := make([]string, len(headers))
+	seen := make(map[string]int, len(headers))
+	for i, header := range headers {
+		key := header
+		if key == "" {
+			key = fmt.Sprintf("column_%d", i+1)
+		}
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s_%d", key, seen[key])
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// tableRecords pairs every row with the header keys
+func tableRecords(headers []string, rows [][]string) []map[string]string {
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]string, len(headers))
+		for i, key := range headers {
+			if i < len(row) {
+				record[key] = row[i]
+			} else {
+				record[key] = ""
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func stringSlice(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		text, _ := item.(string)
+		result = append(result, text)
+	}
+	return result
+}
+
+func stringRows(value interface{}) [][]string {
+	items, _ := value.([]interface{})
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, stringSlice(item))
+	}
+	return rows
+}
+
+// GetElementExtractTableCommand returns the element-extract-table command for registration
+func GetElementExtractTableCommand() *cobra.Command {
+	return elementExtractTableCmd
+}
@@ -15,6 +15,8 @@ var (
 	getAttributeTimeout  int
 )
 
+var getAttributeLocator locatorFlags
+
 var elementGetAttributeCmd = &cobra.Command{
 	Use:   "element-get-attribute",
 	Short: "Get attribute value from an element",
@@ -24,12 +26,12 @@ var elementGetAttributeCmd = &cobra.Command{
 
 func init() {
 	elementGetAttributeCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	elementGetAttributeCmd.Flags().StringVar(&getAttributeSelector, "element-selector", "", "CSS selector or XPath (required)")
+	elementGetAttributeCmd.Flags().StringVar(&getAttributeSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementGetAttributeCmd, &getAttributeLocator)
 	elementGetAttributeCmd.Flags().StringVar(&getAttributeName, "attribute-name", "", "Attribute name to extract (required)")
 	elementGetAttributeCmd.Flags().IntVar(&getAttributeTimeout, "timeout-ms", 30000, "Timeout in milliseconds")
 
 	elementGetAttributeCmd.MarkFlagRequired("session-id")
-	elementGetAttributeCmd.MarkFlagRequired("element-selector")
 	elementGetAttributeCmd.MarkFlagRequired("attribute-name")
 }
 
@@ -40,28 +42,35 @@ func runElementGetAttribute(cmd *cobra.Command, args []string) {
 	// Get global session manager (singleton pattern)
 	sessionMgr := playwright.GetGlobalSessionManager()
 
+	locator, err := getAttributeLocator.spec(cmd, getAttributeSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
 	// Send get-attribute command to session
 	getAttributeCmd := map[string]interface{}{
 		"command":       "get-attribute",
-		"selector":      getAttributeSelector,
 		"attributeName": getAttributeName,
 		"timeout":       getAttributeTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, getAttributeCmd)
+	withLocator(getAttributeCmd, locator)
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, getAttributeCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrElementNotFound,
+			commandErrorCode(err, response.ErrElementNotFound),
 			"Failed to get attribute: "+err.Error(),
 			"Verify session ID, element selector, and attribute name",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": getAttributeSelector,
+				"element_selector": locator.String(),
 				"attribute_name":   getAttributeName,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -72,24 +81,24 @@ func runElementGetAttribute(cmd *cobra.Command, args []string) {
 			"Check if element exists and has the specified attribute",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": getAttributeSelector,
+				"element_selector": locator.String(),
 				"attribute_name":   getAttributeName,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Success response
-	resp := response.Success(map[string]interface{}{
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
 		"session_id":        sessionID,
-		"element_selector":  getAttributeSelector,
+		"element_selector":  locator.String(),
 		"attribute_name":    getAttributeName,
 		"attribute_value":   result.Data["attribute_value"],
 		"element_count":     result.Data["element_count"],
-	}, startTime)
-	resp.Print()
+	}, result), startTime)
+	printResponse(resp)
 }
 
 // GetElementGetAttributeCommand returns the element-get-attribute command for registration
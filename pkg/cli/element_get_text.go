@@ -14,6 +14,8 @@ var (
 	getTextTimeout  int
 )
 
+var getTextLocator locatorFlags
+
 var elementGetTextCmd = &cobra.Command{
 	Use:   "element-get-text",
 	Short: "Get text content from an element",
@@ -23,11 +25,11 @@ var elementGetTextCmd = &cobra.Command{
 
 func init() {
 	elementGetTextCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	elementGetTextCmd.Flags().StringVar(&getTextSelector, "element-selector", "", "CSS selector or XPath (required)")
+	elementGetTextCmd.Flags().StringVar(&getTextSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementGetTextCmd, &getTextLocator)
 	elementGetTextCmd.Flags().IntVar(&getTextTimeout, "timeout-ms", 30000, "Timeout in milliseconds")
 
 	elementGetTextCmd.MarkFlagRequired("session-id")
-	elementGetTextCmd.MarkFlagRequired("element-selector")
 }
 
 func runElementGetText(cmd *cobra.Command, args []string) {
@@ -37,26 +39,33 @@ func runElementGetText(cmd *cobra.Command, args []string) {
 	// Get global session manager (singleton pattern)
 	sessionMgr := playwright.GetGlobalSessionManager()
 
+	locator, err := getTextLocator.spec(cmd, getTextSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
 	// Send get-text command to session
 	getTextCmd := map[string]interface{}{
-		"command":  "get-text",
-		"selector": getTextSelector,
-		"timeout":  getTextTimeout,
+		"command": "get-text",
+		"timeout": getTextTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, getTextCmd)
+	withLocator(getTextCmd, locator)
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, getTextCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrElementNotFound,
+			commandErrorCode(err, response.ErrElementNotFound),
 			"Failed to get text: "+err.Error(),
 			"Verify session ID and element selector",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": getTextSelector,
+				"element_selector": locator.String(),
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -67,22 +76,22 @@ func runElementGetText(cmd *cobra.Command, args []string) {
 			"Check if element exists and is accessible",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": getTextSelector,
+				"element_selector": locator.String(),
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Success response
-	resp := response.Success(map[string]interface{}{
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
 		"session_id":       sessionID,
-		"element_selector": getTextSelector,
+		"element_selector": locator.String(),
 		"text":             result.Data["text"],
 		"element_count":    result.Data["element_count"],
-	}, startTime)
-	resp.Print()
+	}, result), startTime)
+	printResponse(resp)
 }
 
 // GetElementGetTextCommand returns the element-get-text command for registration
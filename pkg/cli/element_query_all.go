@@ -2,7 +2,9 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/oa-plugins/webauto/pkg/playwright"
@@ -13,17 +15,29 @@ import (
 var (
 	queryAllSelector   string
 	queryAllGetText    bool
-	queryAllAttribute  string
+	queryAllAttributes []string
 	queryAllLimit      int
 	queryAllTimeout    int
 	queryAllTrim       bool
+	queryAllChunkSize  int
+	queryAllStream     bool
 )
 
+var queryAllLocator locatorFlags
+
 var elementQueryAllCmd = &cobra.Command{
 	Use:   "element-query-all",
 	Short: "Query multiple elements and extract data in batch",
 	Long: `Extract text or attributes from multiple elements matching a selector.
 Efficient for lists, tables, search results, and bulk data collection.
+All matches are read in a single pass inside the page.
+
+For very large result sets, --stream reads and prints the matches in chunks of
+--chunk-size elements as NDJSON: one {"success":true,"chunk":N,"data":{...}}
+line per chunk as soon as the browser produced it, then the usual response on
+one line with the totals, or an error on one line if the query fails part way.
+Elements carry their index. Once a chunk is printed the command is no longer
+retried, so no element is printed twice.
 
 Examples:
   # Get text from all blog titles (limit 10)
@@ -33,29 +47,43 @@ Examples:
   webauto element-query-all --session-id ses_abc --element-selector "a.link" --get-attribute href
 
   # Get both text and href from search results
-  webauto element-query-all --session-id ses_abc --element-selector ".result-item" --get-text --get-attribute href --limit 5`,
+  webauto element-query-all --session-id ses_abc --element-selector ".result-item" --get-text --get-attribute href --limit 5
+
+  # Get several attributes at once
+  webauto element-query-all --session-id ses_abc --element-selector "img" --get-attribute src,alt,width
+
+  # Stream 100,000 rows without holding them in memory
+  webauto element-query-all --session-id ses_abc --element-selector "tr" --get-text --stream`,
 	Run: runElementQueryAll,
 }
 
 func init() {
 	elementQueryAllCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	elementQueryAllCmd.Flags().StringVar(&queryAllSelector, "element-selector", "", "CSS selector or XPath (required)")
+	elementQueryAllCmd.Flags().StringVar(&queryAllSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementQueryAllCmd, &queryAllLocator)
 	elementQueryAllCmd.Flags().BoolVar(&queryAllGetText, "get-text", false, "Extract text content from each element")
-	elementQueryAllCmd.Flags().StringVar(&queryAllAttribute, "get-attribute", "", "Attribute name to extract (href, src, class, etc.)")
+	elementQueryAllCmd.Flags().StringSliceVar(&queryAllAttributes, "get-attribute", nil, "Attribute names to extract, comma-separated or repeated (href, src, class, etc.)")
 	elementQueryAllCmd.Flags().IntVar(&queryAllLimit, "limit", 0, "Maximum number of elements to process (0 = all elements)")
 	elementQueryAllCmd.Flags().IntVar(&queryAllTimeout, "timeout-ms", 30000, "Timeout in milliseconds")
 	elementQueryAllCmd.Flags().BoolVar(&queryAllTrim, "trim", true, "Trim whitespace from extracted text (default: true)")
+	elementQueryAllCmd.Flags().BoolVar(&queryAllStream, "stream", false, "Print matches in chunks as NDJSON while they are read")
+	elementQueryAllCmd.Flags().IntVar(&queryAllChunkSize, "chunk-size", 500, "Number of elements per chunk with --stream")
 
 	elementQueryAllCmd.MarkFlagRequired("session-id")
-	elementQueryAllCmd.MarkFlagRequired("element-selector")
 }
 
 func runElementQueryAll(cmd *cobra.Command, args []string) {
 	startTime := time.Now()
 	ctx := context.Background()
 
+	// Streamed output is NDJSON, so every response goes out on one line
+	respond := printResponse
+	if queryAllStream {
+		respond = printResponseLine
+	}
+
 	// Validate: at least one extraction flag must be set
-	if !queryAllGetText && queryAllAttribute == "" {
+	if !queryAllGetText && len(queryAllAttributes) == 0 {
 		resp := response.Error(
 			"INVALID_FLAG_COMBINATION",
 			"At least one of --get-text or --get-attribute must be specified",
@@ -64,11 +92,17 @@ func runElementQueryAll(cmd *cobra.Command, args []string) {
 				"session_id":       sessionID,
 				"element_selector": queryAllSelector,
 				"get_text":         queryAllGetText,
-				"get_attribute":    queryAllAttribute,
+				"get_attribute":    queryAllAttributes,
 			},
 			startTime,
 		)
-		resp.Print()
+		respond(resp)
+		return
+	}
+
+	locator, err := queryAllLocator.spec(cmd, queryAllSelector)
+	if err != nil {
+		respond(invalidLocatorResponse(err, startTime))
 		return
 	}
 
@@ -77,42 +111,58 @@ func runElementQueryAll(cmd *cobra.Command, args []string) {
 
 	// Send query-all command to session
 	queryAllCmd := map[string]interface{}{
-		"command":  "query-all",
-		"selector": queryAllSelector,
-		"getText":  queryAllGetText,
-		"trim":     queryAllTrim,
-		"limit":    queryAllLimit,
-		"timeout":  queryAllTimeout,
+		"command": "query-all",
+		"getText": queryAllGetText,
+		"trim":    queryAllTrim,
+		"limit":   queryAllLimit,
+		"timeout": queryAllTimeout,
+	}
+
+	withLocator(queryAllCmd, locator)
+
+	// Print each chunk as it arrives instead of collecting the result
+	chunks := 0
+	if queryAllStream {
+		queryAllCmd["stream"] = true
+		queryAllCmd["chunkSize"] = queryAllChunkSize
+		ctx = playwright.WithChunkHandler(ctx, func(data map[string]interface{}) {
+			chunks++
+			printChunk(chunks, map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": locator.String(),
+				"elements":         data["elements"],
+			})
+		})
 	}
 
-	// Add attribute name if specified
-	if queryAllAttribute != "" {
-		queryAllCmd["attributeName"] = queryAllAttribute
+	// Add attribute names if specified
+	if len(queryAllAttributes) > 0 {
+		queryAllCmd["attributeNames"] = queryAllAttributes
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, queryAllCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, queryAllCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrElementNotFound,
+			commandErrorCode(err, response.ErrElementNotFound),
 			"Failed to query elements: "+err.Error(),
 			"Verify session ID and element selector",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": queryAllSelector,
+				"element_selector": locator.String(),
 				"get_text":         queryAllGetText,
-				"get_attribute":    queryAllAttribute,
+				"get_attribute":    queryAllAttributes,
 				"limit":            queryAllLimit,
 			},
 			startTime,
 		)
-		resp.Print()
+		respond(resp)
 		return
 	}
 
 	if !result.Success {
 		// Check if it's a "no elements found" error
 		errorCode := response.ErrElementNotFound
-		if result.Error == "No elements found: "+queryAllSelector {
+		if result.Error == "No elements found: "+locator.String() {
 			errorCode = "NO_ELEMENTS_FOUND"
 		}
 
@@ -122,23 +172,25 @@ func runElementQueryAll(cmd *cobra.Command, args []string) {
 			"Check if elements exist and are accessible",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": queryAllSelector,
+				"element_selector": locator.String(),
 				"get_text":         queryAllGetText,
-				"get_attribute":    queryAllAttribute,
+				"get_attribute":    queryAllAttributes,
 				"limit":            queryAllLimit,
 			},
 			startTime,
 		)
-		resp.Print()
+		respond(resp)
 		return
 	}
 
 	// Build success response
-	data := map[string]interface{}{
+	data := withSelectorMatch(map[string]interface{}{
 		"session_id":       sessionID,
-		"element_selector": queryAllSelector,
+		"element_selector": locator.String(),
 		"element_count":    result.Data["element_count"],
-		"elements":         result.Data["elements"],
+	}, result)
+	if !queryAllStream {
+		data["elements"] = result.Data["elements"]
 	}
 
 	// Add limit info if it was applied
@@ -152,8 +204,25 @@ func runElementQueryAll(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	resp := response.Success(data, startTime)
-	resp.Print()
+	if queryAllStream {
+		// The elements were printed chunk by chunk; close with the totals
+		data["chunks"] = chunks
+	}
+	respond(response.Success(data, startTime))
+}
+
+// printChunk prints one streamed chunk as a single NDJSON line
+func printChunk(chunk int, data map[string]interface{}) {
+	line, err := json.Marshal(struct {
+		Success bool                   `json:"success"`
+		Chunk   int                    `json:"chunk"`
+		Data    map[string]interface{} `json:"data"`
+	}{true, chunk, data})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to encode chunk %d: %v\n", chunk, err)
+		return
+	}
+	fmt.Println(string(line))
 }
 
 // GetElementQueryAllCommand returns the element-query-all command for registration
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	selectSelector string
+	selectValues   []string
+	selectLabels   []string
+	selectIndexes  []int
+	selectTimeout  int
+)
+
+var selectLocator locatorFlags
+
+var elementSelectCmd = &cobra.Command{
+	Use:   "element-select",
+	Short: "Select options of a <select> dropdown",
+	Long: `Select options of a <select> element by value (--option-value), visible
+label (--option-label) or 0-based position (--option-index). Repeat the flags
+to select several options of a multi-select; options not named are
+deselected. The response lists the options selected afterwards.
+
+  webauto element-select --session-id s --element-selector "#sido" --option-label 서울`,
+	Run: runElementSelect,
+}
+
+func init() {
+	elementSelectCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	elementSelectCmd.Flags().StringVar(&selectSelector, "element-selector", "", "CSS selector or XPath of the <select> (or use a locator option below)")
+	addLocatorFlags(elementSelectCmd, &selectLocator)
+	elementSelectCmd.Flags().StringArrayVar(&selectValues, "option-value", nil, "Option value attribute to select (repeatable)")
+	elementSelectCmd.Flags().StringArrayVar(&selectLabels, "option-label", nil, "Option label to select (repeatable)")
+	elementSelectCmd.Flags().IntSliceVar(&selectIndexes, "option-index", nil, "0-based option index to select (repeatable)")
+	elementSelectCmd.Flags().IntVar(&selectTimeout, "timeout", 30000, "Select timeout in milliseconds")
+
+	elementSelectCmd.MarkFlagRequired("session-id")
+}
+
+// selectOption picks an option of a <select> by value, label or index, in
+// the runner's (and Playwright's) format
+type selectOption struct {
+	Value *string `json:"value,omitempty"`
+	Label *string `json:"label,omitempty"`
+	Index *int    `json:"index,omitempty"`
+}
+
+// selectedOption is an option the runner read back as selected
+type selectedOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Index int    `json:"index"`
+}
+
+// buildSelectOptions turns values, labels and indexes into runner options
+func buildSelectOptions(values, labels []string, indexes []int) ([]selectOption, error) {
+	options := make([]selectOption, 0, len(values)+len(labels)+len(indexes))
+	for i := range values {
+		options = append(options, selectOption{Value: &values[i]})
+	}
+	for i := range labels {
+		options = append(options, selectOption{Label: &labels[i]})
+	}
+	for i := range indexes {
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("option index must not be negative")
+		}
+		options = append(options, selectOption{Index: &indexes[i]})
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("an option value, label or index is required")
+	}
+	return options, nil
+}
+
+// matches reports whether a selected option is the requested one
+func (o selectOption) matches(selected selectedOption) bool {
+	switch {
+	case o.Value != nil:
+		return selected.Value == *o.Value
+	case o.Label != nil:
+		return selected.Label == *o.Label
+	case o.Index != nil:
+		return selected.Index == *o.Index
+	}
+	return false
+}
+
+// decodeSelectedOptions reads the selected options of a select response
+func decodeSelectedOptions(data map[string]interface{}) []selectedOption {
+	var selected []selectedOption
+	if raw, err := json.Marshal(data["selected"]); err == nil {
+		json.Unmarshal(raw, &selected)
+	}
+	return selected
+}
+
+// selectionVerified reports whether every requested option ended up selected
+func selectionVerified(requested []selectOption, selected []selectedOption) bool {
+	for _, option := range requested {
+		found := false
+		for _, s := range selected {
+			if option.matches(s) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// selectedValues lists the values of the selected options
+func selectedValues(selected []selectedOption) []string {
+	values := make([]string, 0, len(selected))
+	for _, s := range selected {
+		values = append(values, s.Value)
+	}
+	return values
+}
+
+func runElementSelect(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	locator, err := selectLocator.spec(cmd, selectSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
+	options, err := buildSelectOptions(selectValues, selectLabels, selectIndexes)
+	if err != nil {
+		resp := response.Error(
+			response.ErrFormValidationFailed,
+			"Invalid options: "+err.Error(),
+			"Pass --option-value, --option-label or --option-index",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": locator.String(),
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	selectCmd := withLocator(map[string]interface{}{
+		"command": "select",
+		"options": options,
+		"timeout": selectTimeout,
+	}, locator)
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, selectCmd)
+	if err != nil {
+		resp := response.Error(
+			commandErrorCode(err, response.ErrElementNotFound),
+			"Failed to select option: "+err.Error(),
+			"Verify session ID and element selector",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": locator.String(),
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	if !result.Success {
+		resp := response.Error(
+			response.ErrFormValidationFailed,
+			"Select failed: "+result.Error,
+			"Check that the element is a <select> and has the requested options",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": locator.String(),
+				"options":          options,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	selected := decodeSelectedOptions(result.Data)
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
+		"session_id":       sessionID,
+		"element_selector": locator.String(),
+		"selected":         selected,
+		"value":            selectedValues(selected),
+		"multiple":         result.Data["multiple"],
+		"verified":         selectionVerified(options, selected),
+	}, result), startTime)
+	printResponse(resp)
+}
@@ -10,10 +10,12 @@ import (
 )
 
 var (
-	elementText    string
-	typeTimeout    int
+	elementText string
+	typeTimeout int
 )
 
+var typeLocator locatorFlags
+
 var elementTypeCmd = &cobra.Command{
 	Use:   "element-type",
 	Short: "Type text into an element on the page",
@@ -23,12 +25,12 @@ var elementTypeCmd = &cobra.Command{
 
 func init() {
 	elementTypeCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	elementTypeCmd.Flags().StringVar(&elementSelector, "element-selector", "", "CSS selector for the element (required)")
+	elementTypeCmd.Flags().StringVar(&elementSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementTypeCmd, &typeLocator)
 	elementTypeCmd.Flags().StringVar(&elementText, "element-text", "", "Text to type (required)")
 	elementTypeCmd.Flags().IntVar(&typeTimeout, "timeout", 30000, "Type timeout in milliseconds")
 
 	elementTypeCmd.MarkFlagRequired("session-id")
-	elementTypeCmd.MarkFlagRequired("element-selector")
 	elementTypeCmd.MarkFlagRequired("element-text")
 }
 
@@ -36,31 +38,37 @@ func runElementType(cmd *cobra.Command, args []string) {
 	startTime := time.Now()
 	ctx := context.Background()
 
-
 	// Get global session manager (singleton pattern)
 	sessionMgr := playwright.GetGlobalSessionManager()
 
+	locator, err := typeLocator.spec(cmd, elementSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
 	// Send type command to session
 	typeCmd := map[string]interface{}{
-		"command":  "type",
-		"selector": elementSelector,
-		"text":     elementText,
-		"timeout":  typeTimeout,
+		"command": "type",
+		"text":    elementText,
+		"timeout": typeTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, typeCmd)
+	withLocator(typeCmd, locator)
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, typeCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrElementNotFound,
+			commandErrorCode(err, response.ErrElementNotFound),
 			"Failed to type into element: "+err.Error(),
 			"Verify session ID and element selector",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": elementSelector,
+				"element_selector": locator.String(),
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -71,21 +79,21 @@ func runElementType(cmd *cobra.Command, args []string) {
 			"Check if element is visible and editable",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": elementSelector,
+				"element_selector": locator.String(),
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Success response
-	resp := response.Success(map[string]interface{}{
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
 		"session_id":       sessionID,
-		"element_selector": elementSelector,
+		"element_selector": locator.String(),
 		"element_text":     elementText,
 		"typed":            true,
 		"timeout_ms":       typeTimeout,
-	}, startTime)
-	resp.Print()
+	}, result), startTime)
+	printResponse(resp)
 }
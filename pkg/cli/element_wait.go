@@ -10,11 +10,13 @@ import (
 )
 
 var (
-	waitSelector   string
-	waitCondition  string
-	waitTimeoutMs  int
+	waitSelector  string
+	waitCondition string
+	waitTimeoutMs int
 )
 
+var waitLocator locatorFlags
+
 var elementWaitCmd = &cobra.Command{
 	Use:   "element-wait",
 	Short: "Wait for an element to meet a specific condition",
@@ -24,12 +26,12 @@ var elementWaitCmd = &cobra.Command{
 
 func init() {
 	elementWaitCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	elementWaitCmd.Flags().StringVar(&waitSelector, "element-selector", "", "CSS selector or XPath (required)")
+	elementWaitCmd.Flags().StringVar(&waitSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementWaitCmd, &waitLocator)
 	elementWaitCmd.Flags().StringVar(&waitCondition, "wait-for", "visible", "Wait condition: visible, hidden, attached, detached (default: visible)")
 	elementWaitCmd.Flags().IntVar(&waitTimeoutMs, "timeout-ms", 30000, "Timeout in milliseconds")
 
 	elementWaitCmd.MarkFlagRequired("session-id")
-	elementWaitCmd.MarkFlagRequired("element-selector")
 }
 
 func runElementWait(cmd *cobra.Command, args []string) {
@@ -56,36 +58,43 @@ func runElementWait(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Get global session manager (singleton pattern)
 	sessionMgr := playwright.GetGlobalSessionManager()
 
+	locator, err := waitLocator.spec(cmd, waitSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
 	// Send wait command to session
 	waitCmd := map[string]interface{}{
 		"command":       "wait",
-		"selector":      waitSelector,
 		"waitCondition": waitCondition,
 		"timeout":       waitTimeoutMs,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, waitCmd)
+	withLocator(waitCmd, locator)
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, waitCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrTimeoutExceeded,
+			commandErrorCode(err, response.ErrTimeoutExceeded),
 			"Failed to wait for element: "+err.Error(),
 			"Verify session ID, element selector, and timeout value",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": waitSelector,
+				"element_selector": locator.String(),
 				"wait_condition":   waitCondition,
 				"timeout_ms":       waitTimeoutMs,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -96,25 +105,25 @@ func runElementWait(cmd *cobra.Command, args []string) {
 			"Element did not meet wait condition within timeout",
 			map[string]interface{}{
 				"session_id":       sessionID,
-				"element_selector": waitSelector,
+				"element_selector": locator.String(),
 				"wait_condition":   waitCondition,
 				"timeout_ms":       waitTimeoutMs,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Success response
-	resp := response.Success(map[string]interface{}{
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
 		"session_id":       sessionID,
-		"element_selector": waitSelector,
+		"element_selector": locator.String(),
 		"wait_condition":   result.Data["wait_condition"],
 		"waited_ms":        result.Data["waited_ms"],
 		"element_found":    result.Data["element_found"],
-	}, startTime)
-	resp.Print()
+	}, result), startTime)
+	printResponse(resp)
 }
 
 // GetElementWaitCommand returns the element-wait command for registration
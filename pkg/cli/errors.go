@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/oa-plugins/webauto/pkg/ratelimit"
+	"github.com/oa-plugins/webauto/pkg/response"
+)
+
+// commandErrorCode maps an error returned by SessionManager.SendCommand to a
+// response error code. Errors with a well-known cause get their own code;
+// everything else falls back to the command-specific code.
+func commandErrorCode(err error, fallback string) string {
+	if errors.Is(err, ratelimit.ErrLimitExceeded) {
+		return response.ErrRateLimitExceeded
+	}
+	return fallback
+}
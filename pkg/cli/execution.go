@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/ipc"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+)
+
+// Global retry flags (registered on the root command)
+var (
+	retryCount   int
+	retryBackoff time.Duration
+	retryOnCodes []string
+)
+
+// executionLog accumulates attempts of every browser command sent during
+// this invocation so they can be reported in the response metadata
+var executionLog struct {
+	attempts int
+	errors   []response.AttemptError
+}
+
+// retryPolicy builds the retry policy from the global flags
+func retryPolicy() playwright.RetryPolicy {
+	retryOn := make(map[string]bool, len(retryOnCodes))
+	for _, code := range retryOnCodes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			retryOn[code] = true
+		}
+	}
+
+	return playwright.RetryPolicy{
+		MaxRetries: retryCount,
+		Backoff:    retryBackoff,
+		RetryOn:    retryOn,
+	}
+}
+
+// sendCommand is the shared execution layer for browser commands. It sends
+// the command through the session manager, applies the retry policy,
+// records every attempt for the response metadata and updates the
+// self-healing selector store.
+func sendCommand(ctx context.Context, sessionMgr *playwright.SessionManager, sessionID string, command map[string]interface{}) (*ipc.NodeResponse, error) {
+	resp, report, err := sessionMgr.SendCommandWithRetry(ctx, sessionID, command, retryPolicy())
+	if err == nil {
+		recordSelectorMatch(command, resp)
+	}
+	if report != nil {
+		executionLog.attempts += report.Attempts
+		executionLog.errors = append(executionLog.errors, report.Errors...)
+	}
+	return resp, err
+}
+
+// sendCheckedCommand sends a command like sendCommand and folds a runner
+// failure into the returned error. sessionErr tells whether the session
+// itself failed rather than the command.
+func sendCheckedCommand(ctx context.Context, sessionMgr *playwright.SessionManager, command map[string]interface{}) (*ipc.NodeResponse, bool, error) {
+	result, err := sendCommand(ctx, sessionMgr, sessionID, command)
+	if err != nil {
+		return nil, true, err
+	}
+	if !result.Success {
+		return nil, false, fmt.Errorf("%s", result.Error)
+	}
+	return result, false, nil
+}
+
+// commandFailure builds the error response of a command sent with
+// sendCheckedCommand. Session failures keep their own code; runner failures
+// are reported with code and recovery.
+func commandFailure(err error, sessionErr bool, action, code, recovery string, details map[string]interface{}, startTime time.Time) *response.StandardResponse {
+	message := "Failed to " + action + ": " + err.Error()
+	if sessionErr {
+		return response.Error(
+			commandErrorCode(err, response.ErrSessionNotFound),
+			message,
+			"Verify session ID is valid and session is still active",
+			details,
+			startTime,
+		)
+	}
+	return response.Error(code, message, recovery, details, startTime)
+}
+
+// printResponse attaches execution metadata and prints the response
+func printResponse(resp *response.StandardResponse) {
+	attachExecutionLog(resp)
+	resp.Print()
+}
+
+// printResponseLine is printResponse for the last line of streamed output
+func printResponseLine(resp *response.StandardResponse) {
+	attachExecutionLog(resp)
+	resp.PrintLine()
+}
+
+func attachExecutionLog(resp *response.StandardResponse) {
+	if executionLog.attempts > 0 {
+		resp.Metadata.Attempts = executionLog.attempts
+		resp.Metadata.AttemptErrors = executionLog.errors
+	}
+}
@@ -3,6 +3,9 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/oa-plugins/webauto/pkg/playwright"
@@ -20,13 +23,36 @@ var (
 var formFillCmd = &cobra.Command{
 	Use:   "form-fill",
 	Short: "Fill multiple form fields at once",
-	Long:  `Fill multiple form fields with values provided in JSON format. Optionally submit the form after filling.`,
+	Long: `Fill multiple form fields with values provided in JSON format. Optionally submit the form after filling.
+
+--form-data accepts either an object of selector:value pairs or an array of
+entries that can use the same locator options as element commands:
+
+  [{"selector": "#user", "value": "hong"},
+   {"locator": {"role": "textbox", "name": "비밀번호"}, "value": "secret"},
+   {"locator": {"label": "사업자등록번호", "exact": true}, "value": "1234567890"},
+   {"locator": {"selector": "#txtCorpNm", "fallbacks": ["input[name=corpNm]"], "key": "hometax.corp_name"}, "value": "오에이"}]
+
+Array entries may set "type" for controls that cannot be typed into:
+
+  text      (default) fill "value"
+  select    select options by one of "value", "label" (visible text) or
+            "index"; each takes one item or an array for multi-selects
+  checkbox  set "checked" (default true)
+  radio     select the radio button
+
+  [{"selector": "#sido", "type": "select", "label": "서울"},
+   {"selector": "#agree", "type": "checkbox", "checked": true},
+   {"locator": {"role": "radio", "name": "개인"}, "type": "radio"}]
+
+Every field is read back after filling: "value" is what the page holds
+afterwards, "requested" what was asked for and "verified" whether they agree.`,
 	Run:   runFormFill,
 }
 
 func init() {
 	formFillCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
-	formFillCmd.Flags().StringVar(&formData, "form-data", "", "JSON object with selector:value pairs (required)")
+	formFillCmd.Flags().StringVar(&formData, "form-data", "", "JSON object of selector:value pairs or array of field entries (required)")
 	formFillCmd.Flags().BoolVar(&submitForm, "submit", false, "Submit the form after filling")
 	formFillCmd.Flags().StringVar(&submitSelector, "submit-selector", "", "CSS selector for submit button (required if --submit is true)")
 	formFillCmd.Flags().IntVar(&formTimeout, "timeout", 30000, "Timeout for each field in milliseconds")
@@ -44,19 +70,19 @@ func runFormFill(cmd *cobra.Command, args []string) {
 	sessionMgr := playwright.GetGlobalSessionManager()
 
 	// Parse form data JSON
-	var fields map[string]string
-	if err := json.Unmarshal([]byte(formData), &fields); err != nil {
+	fields, err := parseFormFields(formData)
+	if err != nil {
 		resp := response.Error(
 			response.ErrPageLoadFailed,
 			"Failed to parse form-data: "+err.Error(),
-			"Provide valid JSON object with selector:value pairs",
+			"Provide a JSON object with selector:value pairs or an array of {selector|locator, type, value|label|index|checked} entries",
 			map[string]interface{}{
 				"session_id": sessionID,
 				"form_data":  formData,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -70,34 +96,31 @@ func runFormFill(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	// Fill each field
 	filledFields := make([]map[string]interface{}, 0, len(fields))
-	for selector, value := range fields {
-		typeCmd := map[string]interface{}{
-			"command":  "type",
-			"selector": selector,
-			"text":     value,
-			"timeout":  formTimeout,
-		}
+	allVerified := true
+	for _, field := range fields {
+		selector := field.locator.String()
 
-		result, err := sessionMgr.SendCommand(ctx, sessionID, typeCmd)
+		result, err := sendCommand(ctx, sessionMgr, sessionID, field.command())
 		if err != nil {
 			resp := response.Error(
-				response.ErrElementNotFound,
+				commandErrorCode(err, response.ErrElementNotFound),
 				"Failed to fill field: "+err.Error(),
 				"Verify selector and session ID",
 				map[string]interface{}{
 					"session_id": sessionID,
 					"selector":   selector,
-					"value":      value,
+					"type":       field.Type,
+					"value":      field.requested(),
 				},
 				startTime,
 			)
-			resp.Print()
+			printResponse(resp)
 			return
 		}
 
@@ -105,22 +128,25 @@ func runFormFill(cmd *cobra.Command, args []string) {
 			resp := response.Error(
 				response.ErrElementNotClickable,
 				"Field fill failed: "+result.Error,
-				"Check if element is visible and editable",
+				field.recovery(),
 				map[string]interface{}{
 					"session_id": sessionID,
 					"selector":   selector,
+					"type":       field.Type,
 				},
 				startTime,
 			)
-			resp.Print()
+			printResponse(resp)
 			return
 		}
 
-		filledFields = append(filledFields, map[string]interface{}{
-			"selector": selector,
-			"value":    value,
-			"filled":   true,
-		})
+		report := field.report(result.Data)
+		if verified, _ := report["verified"].(bool); !verified {
+			allVerified = false
+		}
+		report["selector"] = selector
+		report["filled"] = true
+		filledFields = append(filledFields, withSelectorMatch(report, result))
 	}
 
 	// Submit form if requested
@@ -137,7 +163,7 @@ func runFormFill(cmd *cobra.Command, args []string) {
 				},
 				startTime,
 			)
-			resp.Print()
+			printResponse(resp)
 			return
 		}
 
@@ -147,10 +173,10 @@ func runFormFill(cmd *cobra.Command, args []string) {
 			"timeout":  formTimeout,
 		}
 
-		result, err := sessionMgr.SendCommand(ctx, sessionID, clickCmd)
+		result, err := sendCommand(ctx, sessionMgr, sessionID, clickCmd)
 		if err != nil {
 			resp := response.Error(
-				response.ErrElementNotFound,
+				commandErrorCode(err, response.ErrElementNotFound),
 				"Failed to click submit button: "+err.Error(),
 				"Verify submit-selector",
 				map[string]interface{}{
@@ -159,7 +185,7 @@ func runFormFill(cmd *cobra.Command, args []string) {
 				},
 				startTime,
 			)
-			resp.Print()
+			printResponse(resp)
 			return
 		}
 
@@ -174,7 +200,7 @@ func runFormFill(cmd *cobra.Command, args []string) {
 				},
 				startTime,
 			)
-			resp.Print()
+			printResponse(resp)
 			return
 		}
 
@@ -186,8 +212,222 @@ func runFormFill(cmd *cobra.Command, args []string) {
 		"session_id":    sessionID,
 		"fields_filled": len(filledFields),
 		"fields":        filledFields,
+		"all_verified":  allVerified,
 		"submitted":     submitted,
 		"timeout_ms":    formTimeout,
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
+}
+
+// Form field types
+const (
+	fieldText     = "text"
+	fieldSelect   = "select"
+	fieldCheckbox = "checkbox"
+	fieldRadio    = "radio"
+)
+
+// formField is a single field of --form-data. Value holds the text of a
+// text field or the option values of a select; Label and Index the option
+// labels and indexes of a select; Checked the wanted checkbox state.
+type formField struct {
+	Selector string       `json:"selector"`
+	Locator  *locatorSpec `json:"locator"`
+	Type     string       `json:"type"`
+	Value    stringList   `json:"value"`
+	Label    stringList   `json:"label"`
+	Index    intList      `json:"index"`
+	Checked  *bool        `json:"checked"`
+
+	locator *locatorSpec
+	options []selectOption
+}
+
+// stringList accepts a JSON string or an array of strings
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("must be a string or an array of strings, got %s", data)
+	}
+	*l = list
+	return nil
+}
+
+// intList accepts a JSON number or an array of numbers
+type intList []int
+
+func (l *intList) UnmarshalJSON(data []byte) error {
+	var single int
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = intList{single}
+		return nil
+	}
+	var list []int
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("must be a number or an array of numbers, got %s", data)
+	}
+	*l = list
+	return nil
+}
+
+// normalize validates the entry's fields against its type
+func (f *formField) normalize() error {
+	switch strings.ToLower(f.Type) {
+	case "", fieldText, "fill":
+		f.Type = fieldText
+		if f.Value == nil && len(f.Label) == 0 && len(f.Index) == 0 {
+			// A text entry without a value clears the field
+			f.Value = stringList{""}
+		}
+		if len(f.Value) != 1 || len(f.Label) > 0 || len(f.Index) > 0 || f.Checked != nil {
+			return fmt.Errorf("a text field takes a single string value")
+		}
+	case fieldSelect:
+		f.Type = fieldSelect
+		if f.Checked != nil {
+			return fmt.Errorf("a select field takes value, label or index, not checked")
+		}
+		kinds := 0
+		for _, n := range []int{len(f.Value), len(f.Label), len(f.Index)} {
+			if n > 0 {
+				kinds++
+			}
+		}
+		if kinds > 1 {
+			return fmt.Errorf("a select field takes one of value, label or index")
+		}
+		options, err := buildSelectOptions(f.Value, f.Label, f.Index)
+		if err != nil {
+			return err
+		}
+		f.options = options
+	case fieldCheckbox, fieldRadio:
+		f.Type = strings.ToLower(f.Type)
+		if len(f.Value) > 0 || len(f.Label) > 0 || len(f.Index) > 0 {
+			return fmt.Errorf("a %s field takes checked, not value, label or index", f.Type)
+		}
+		if f.Checked == nil {
+			checked := true
+			f.Checked = &checked
+		}
+		if f.Type == fieldRadio && !*f.Checked {
+			return fmt.Errorf("a radio button cannot be unchecked; check another radio of the group")
+		}
+	default:
+		return fmt.Errorf("unknown field type %q (use text, select, checkbox or radio)", f.Type)
+	}
+	return nil
+}
+
+// command builds the runner command that fills the field
+func (f *formField) command() map[string]interface{} {
+	command := map[string]interface{}{"timeout": formTimeout}
+	switch f.Type {
+	case fieldSelect:
+		command["command"] = "select"
+		command["options"] = f.options
+	case fieldCheckbox, fieldRadio:
+		command["command"] = "check"
+		command["checked"] = *f.Checked
+	default:
+		command["command"] = "type"
+		command["text"] = f.Value[0]
+	}
+	return withLocator(command, f.locator)
+}
+
+// requested is the value the field was asked to take
+func (f *formField) requested() interface{} {
+	switch f.Type {
+	case fieldSelect:
+		return f.options
+	case fieldCheckbox, fieldRadio:
+		return *f.Checked
+	default:
+		return f.Value[0]
+	}
+}
+
+// recovery is the hint given when filling the field fails
+func (f *formField) recovery() string {
+	switch f.Type {
+	case fieldSelect:
+		return "Check that the element is a <select> and has the requested options"
+	case fieldCheckbox, fieldRadio:
+		return "Check that the element is a visible, enabled " + f.Type
+	default:
+		return "Check if element is visible and editable"
+	}
+}
+
+// report describes the filled field with the value read back from the page
+func (f *formField) report(data map[string]interface{}) map[string]interface{} {
+	report := map[string]interface{}{
+		"type":      f.Type,
+		"requested": f.requested(),
+	}
+	switch f.Type {
+	case fieldSelect:
+		selected := decodeSelectedOptions(data)
+		report["value"] = selectedValues(selected)
+		report["selected"] = selected
+		report["verified"] = selectionVerified(f.options, selected)
+	case fieldCheckbox, fieldRadio:
+		checked, _ := data["checked"].(bool)
+		report["value"] = checked
+		report["verified"] = checked == *f.Checked
+	default:
+		// null when the element has no readable value (e.g. contenteditable)
+		value, ok := data["value"].(string)
+		report["value"] = data["value"]
+		report["verified"] = ok && value == f.Value[0]
+	}
+	return report
+}
+
+// parseFormFields accepts either a {"selector": "value"} object (filled in
+// selector order) or an ordered array of formField entries
+func parseFormFields(data string) ([]formField, error) {
+	var fields []formField
+
+	if strings.HasPrefix(strings.TrimSpace(data), "[") {
+		if err := json.Unmarshal([]byte(data), &fields); err != nil {
+			return nil, err
+		}
+	} else {
+		var pairs map[string]string
+		if err := json.Unmarshal([]byte(data), &pairs); err != nil {
+			return nil, err
+		}
+		for selector, value := range pairs {
+			fields = append(fields, formField{Selector: selector, Value: stringList{value}})
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Selector < fields[j].Selector })
+	}
+
+	for i := range fields {
+		spec := &locatorSpec{}
+		if fields[i].Locator != nil {
+			*spec = *fields[i].Locator
+		}
+		if spec.Selector == "" {
+			spec.Selector = fields[i].Selector
+		}
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("field %d: %w", i, err)
+		}
+		if err := fields[i].normalize(); err != nil {
+			return nil, fmt.Errorf("field %d (%s): %w", i, spec.String(), err)
+		}
+		fields[i].locator = spec
+	}
+
+	return fields, nil
 }
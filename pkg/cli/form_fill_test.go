@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringListUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    stringList
+		wantErr bool
+	}{
+		{"single string", `"서울"`, stringList{"서울"}, false},
+		{"empty string", `""`, stringList{""}, false},
+		{"array", `["a", "b"]`, stringList{"a", "b"}, false},
+		{"empty array", `[]`, stringList{}, false},
+		{"number", `1`, nil, true},
+		{"mixed array", `["a", 1]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got stringList
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%s) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntListUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    intList
+		wantErr bool
+	}{
+		{"single number", `2`, intList{2}, false},
+		{"array", `[0, 3]`, intList{0, 3}, false},
+		{"string", `"2"`, nil, true},
+		{"fraction", `1.5`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got intList
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%s) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string // command of each field, in order
+		wantErr string
+	}{
+		{"object form sorted by selector", `{"#user": "hong", "#pass": "secret"}`, []string{"type #pass", "type #user"}, ""},
+		{"text without a value clears the field", `[{"selector": "#memo"}]`, []string{"type #memo"}, ""},
+		{"fill is an alias of text", `[{"selector": "#memo", "type": "fill", "value": "x"}]`, []string{"type #memo"}, ""},
+		{"select by label list", `[{"selector": "#sido", "type": "select", "label": ["서울", "부산"]}]`, []string{"select #sido"}, ""},
+		{"select by index", `[{"selector": "#sido", "type": "SELECT", "index": 0}]`, []string{"select #sido"}, ""},
+		{"checkbox defaults to checked", `[{"selector": "#agree", "type": "checkbox"}]`, []string{"check #agree"}, ""},
+		{"radio by locator", `[{"locator": {"role": "radio", "name": "개인"}, "type": "radio"}]`, []string{`check role=radio[name="개인"]`}, ""},
+		{"missing selector", `[{"value": "x"}]`, nil, "field 0: an element selector or locator option"},
+		{"invalid locator", `[{"locator": {"name": "x"}}]`, nil, "field 0: an element selector or locator option"},
+		{"text with checked", `[{"selector": "#memo", "checked": true}]`, nil, "a text field takes a single string value"},
+		{"text with several values", `[{"selector": "#memo", "value": ["a", "b"]}]`, nil, "a text field takes a single string value"},
+		{"text with a label", `[{"selector": "#memo", "label": "a"}]`, nil, "a text field takes a single string value"},
+		{"select with checked", `[{"selector": "#sido", "type": "select", "value": "11", "checked": true}]`, nil, "not checked"},
+		{"select with label and index", `[{"selector": "#sido", "type": "select", "label": "서울", "index": 1}]`, nil, "a select field takes one of value, label or index"},
+		{"select with value and label", `[{"selector": "#sido", "type": "select", "value": "11", "label": "서울"}]`, nil, "a select field takes one of value, label or index"},
+		{"select without options", `[{"selector": "#sido", "type": "select"}]`, nil, "an option value, label or index is required"},
+		{"select with a negative index", `[{"selector": "#sido", "type": "select", "index": -1}]`, nil, "option index must not be negative"},
+		{"checkbox with a value", `[{"selector": "#agree", "type": "checkbox", "value": "on"}]`, nil, "a checkbox field takes checked"},
+		{"unchecked radio", `[{"selector": "#personal", "type": "radio", "checked": false}]`, nil, "cannot be unchecked"},
+		{"unknown type", `[{"selector": "#file", "type": "upload"}]`, nil, `unknown field type "upload"`},
+		{"error names the field", `[{"selector": "#a"}, {"selector": "#b", "checked": true}]`, nil, "field 1 (#b):"},
+		{"value of the wrong type", `[{"selector": "#memo", "value": 1}]`, nil, "must be a string or an array of strings"},
+		{"object value of the wrong type", `{"#memo": 1}`, nil, "cannot unmarshal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := parseFormFields(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseFormFields() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFormFields() = %v", err)
+			}
+
+			var got []string
+			for i := range fields {
+				command := fields[i].command()
+				got = append(got, command["command"].(string)+" "+fields[i].locator.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFormFields() commands = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormFieldsNormalizes(t *testing.T) {
+	fields, err := parseFormFields(`[
+		{"selector": "#memo"},
+		{"selector": "#sido", "type": "select", "value": ["11", "26"]},
+		{"selector": "#agree", "type": "Checkbox", "checked": false}
+	]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fields[0].Type != fieldText || !reflect.DeepEqual(fields[0].Value, stringList{""}) {
+		t.Errorf("text field = %+v, want an empty text value", fields[0])
+	}
+	if len(fields[1].options) != 2 || *fields[1].options[0].Value != "11" || *fields[1].options[1].Value != "26" {
+		t.Errorf("select options = %+v, want values 11 and 26", fields[1].options)
+	}
+	if fields[2].Type != fieldCheckbox || fields[2].Checked == nil || *fields[2].Checked {
+		t.Errorf("checkbox field = %+v, want an unchecked checkbox", fields[2])
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/config"
+	"github.com/oa-plugins/webauto/pkg/ipc"
+	"github.com/oa-plugins/webauto/pkg/selectors"
+)
+
+var (
+	selectorStoreOnce sync.Once
+	selectorStore     *selectors.Store
+)
+
+// getSelectorStore returns the store of last known-good selectors
+func getSelectorStore() *selectors.Store {
+	selectorStoreOnce.Do(func() {
+		selectorStore = selectors.NewStore(config.Load().SelectorStorePath)
+	})
+	return selectorStore
+}
+
+// addHealingCandidates turns a locator with fallbacks or a selector key into
+// an ordered candidate list: the locator itself, the fallback selectors, then
+// the locator that matched last time. A stored fingerprint is attached so the
+// runner can score similar elements when every candidate fails.
+func addHealingCandidates(command map[string]interface{}, spec *locatorSpec) {
+	if len(spec.Fallbacks) == 0 && spec.Key == "" {
+		return
+	}
+
+	var candidates []map[string]interface{}
+	seen := map[string]bool{}
+
+	addCandidate := func(locator map[string]interface{}, description string) {
+		id, _ := json.Marshal(locator)
+		if len(locator) == 0 || seen[string(id)] {
+			return
+		}
+		seen[string(id)] = true
+
+		candidate := make(map[string]interface{}, len(locator)+1)
+		for field, value := range locator {
+			candidate[field] = value
+		}
+		candidate["description"] = description
+		candidates = append(candidates, candidate)
+	}
+
+	addCandidate(spec.payload(), spec.String())
+	for _, fallback := range spec.Fallbacks {
+		if fallback != "" {
+			addCandidate(map[string]interface{}{"selector": fallback}, fallback)
+		}
+	}
+
+	if spec.Key != "" {
+		command["selectorKey"] = spec.Key
+		command["captureFingerprint"] = true
+
+		entry, err := getSelectorStore().Get(spec.Key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if entry != nil {
+			switch {
+			case entry.Locator != nil:
+				addCandidate(entry.Locator, entry.Selector)
+			case entry.Strategy != "primary" && entry.Selector != "":
+				// Entries written before locators were stored: only fallback
+				// and fingerprint matches recorded a usable selector
+				addCandidate(map[string]interface{}{"selector": entry.Selector}, entry.Selector)
+			}
+			if entry.Fingerprint != nil {
+				command["fingerprint"] = entry.Fingerprint
+			}
+		}
+	}
+
+	command["candidates"] = candidates
+}
+
+// recordSelectorMatch stores the selector and fingerprint that matched for a
+// keyed locator, and drops the bulky fingerprint from the response
+func recordSelectorMatch(command map[string]interface{}, resp *ipc.NodeResponse) {
+	if resp == nil || !resp.Success {
+		return
+	}
+
+	match, ok := resp.Data["selector_match"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	fingerprint, _ := match["fingerprint"].(map[string]interface{})
+	locator, _ := match["locator"].(map[string]interface{})
+	delete(match, "fingerprint")
+	delete(match, "locator")
+	match["healed"] = match["strategy"] != "primary"
+
+	key, _ := command["selectorKey"].(string)
+	if key == "" {
+		return
+	}
+
+	selector, _ := match["selector"].(string)
+	strategy, _ := match["strategy"].(string)
+	entry := &selectors.Entry{
+		Selector:    selector,
+		Locator:     locator,
+		Strategy:    strategy,
+		Fingerprint: fingerprint,
+		UpdatedAt:   time.Now(),
+	}
+	if err := getSelectorStore().Put(key, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update selector store: %v\n", err)
+		return
+	}
+	match["selector_key"] = key
+	match["store_updated"] = true
+}
+
+// withSelectorMatch copies the runner's selector match report into response data
+func withSelectorMatch(data map[string]interface{}, result *ipc.NodeResponse) map[string]interface{} {
+	if match, ok := result.Data["selector_match"].(map[string]interface{}); ok {
+		data["selector_match"] = match
+	}
+	return data
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// errInvalidKey reports a key Playwright does not know
+const errInvalidKey = "INVALID_KEY"
+
+var (
+	keyboardKeys     []string
+	keyboardAction   string
+	keyboardRepeat   int
+	keyboardDelay    int
+	keyboardText     string
+	keyboardSelector string
+	keyboardTimeout  int
+)
+
+var (
+	keyPressLocator locatorFlags
+	keyTypeLocator  locatorFlags
+)
+
+var keyboardPressCmd = &cobra.Command{
+	Use:   "keyboard-press",
+	Short: "Press keys and key chords",
+	Long: `Press keys using Playwright key syntax: named keys (Enter, Tab, Escape,
+Backspace, ArrowDown, F5, ...), characters (a, 1, /) and chords joined with +
+(Control+A, Shift+Tab, ControlOrMeta+V).
+
+--key is repeatable; the keys are pressed in order, --repeat times. With
+--action down a key or chord is held until a later --action up releases it,
+e.g. to shift-click or drag with a modifier.
+
+Keys go to the element with focus. Give --element-selector or a locator option
+to focus an element first, e.g. to submit a form bound to Enter:
+
+  webauto keyboard-press --session-id s --label 비밀번호 --key Enter`,
+	Run: runKeyboardPress,
+}
+
+var keyboardTypeCmd = &cobra.Command{
+	Use:   "keyboard-type",
+	Short: "Type text into the focused element",
+	Long: `Type text key by key into the element with focus, firing keydown, keypress,
+input and keyup events for every character (element-type fills the value
+without them). Give --element-selector or a locator option to focus an
+element first; --delay-ms slows typing down for sites that debounce input.`,
+	Run: runKeyboardType,
+}
+
+func init() {
+	keyboardPressCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	keyboardPressCmd.Flags().StringArrayVar(&keyboardKeys, "key", nil, "Key or chord to press, e.g. Enter or Control+A (required, repeatable)")
+	keyboardPressCmd.Flags().StringVar(&keyboardAction, "action", "press", "press, down (hold) or up (release)")
+	keyboardPressCmd.Flags().IntVar(&keyboardRepeat, "repeat", 1, "Number of times to press the keys")
+	keyboardPressCmd.Flags().IntVar(&keyboardDelay, "delay-ms", 0, "Time to hold each key down in milliseconds")
+	keyboardPressCmd.Flags().StringVar(&keyboardSelector, "element-selector", "", "Focus this element first (CSS selector or XPath, or use a locator option below)")
+	addLocatorFlags(keyboardPressCmd, &keyPressLocator)
+	keyboardPressCmd.Flags().IntVar(&keyboardTimeout, "timeout", 30000, "Timeout for finding the element in milliseconds")
+
+	keyboardPressCmd.MarkFlagRequired("session-id")
+	keyboardPressCmd.MarkFlagRequired("key")
+
+	keyboardTypeCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	keyboardTypeCmd.Flags().StringVar(&keyboardText, "keyboard-text", "", "Text to type (required)")
+	keyboardTypeCmd.Flags().IntVar(&keyboardDelay, "delay-ms", 0, "Delay between keystrokes in milliseconds")
+	keyboardTypeCmd.Flags().StringVar(&keyboardSelector, "element-selector", "", "Focus this element first (CSS selector or XPath, or use a locator option below)")
+	addLocatorFlags(keyboardTypeCmd, &keyTypeLocator)
+	keyboardTypeCmd.Flags().IntVar(&keyboardTimeout, "timeout", 30000, "Timeout for finding the element in milliseconds")
+
+	keyboardTypeCmd.MarkFlagRequired("session-id")
+	keyboardTypeCmd.MarkFlagRequired("keyboard-text")
+}
+
+// validateKeyboardPress checks the keyboard-press flags
+func validateKeyboardPress() error {
+	switch keyboardAction {
+	case "press", "down", "up":
+	default:
+		return fmt.Errorf("unknown action %q (use press, down or up)", keyboardAction)
+	}
+	if keyboardRepeat < 1 {
+		return fmt.Errorf("--repeat must be at least 1")
+	}
+	if keyboardDelay < 0 {
+		return fmt.Errorf("--delay-ms must not be negative")
+	}
+	for _, key := range keyboardKeys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("--key must not be empty")
+		}
+	}
+	return nil
+}
+
+// keyboardDetails describes a keyboard command in error details
+func keyboardDetails(locator *locatorSpec) map[string]interface{} {
+	details := map[string]interface{}{"session_id": sessionID}
+	if locator != nil {
+		details["element_selector"] = locator.String()
+	}
+	return details
+}
+
+// keyboardErrorCode picks the error code and recovery hint of a keyboard
+// command the runner rejected
+func keyboardErrorCode(err error) (string, string) {
+	if strings.Contains(err.Error(), "Unknown key") {
+		return errInvalidKey, "Use Playwright key names such as Enter, Tab, Escape, ArrowDown, Control+A"
+	}
+	return response.ErrElementNotFound, "Check that the element exists and can take focus"
+}
+
+func runKeyboardPress(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	if err := validateKeyboardPress(); err != nil {
+		resp := response.Error(
+			errInvalidKey,
+			"Invalid keyboard-press options: "+err.Error(),
+			"Pass --key with a Playwright key such as Enter or Control+A",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"keys":       keyboardKeys,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	locator, err := keyPressLocator.optionalSpec(cmd, keyboardSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	pressCmd := map[string]interface{}{
+		"command": "keyboard-press",
+		"keys":    keyboardKeys,
+		"action":  keyboardAction,
+		"repeat":  keyboardRepeat,
+		"delay":   keyboardDelay,
+		"timeout": keyboardTimeout,
+	}
+	if locator != nil {
+		withLocator(pressCmd, locator)
+	}
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, pressCmd)
+	if err != nil {
+		code, recovery := keyboardErrorCode(err)
+		printResponse(commandFailure(err, sessionErr, "press keys", code, recovery, keyboardDetails(locator), startTime))
+		return
+	}
+
+	data := map[string]interface{}{
+		"session_id":      sessionID,
+		"keys":            keyboardKeys,
+		"action":          keyboardAction,
+		"repeat":          keyboardRepeat,
+		"focused_element": result.Data["focused_element"],
+	}
+	if locator != nil {
+		data["element_selector"] = locator.String()
+		data = withSelectorMatch(data, result)
+	}
+	printResponse(response.Success(data, startTime))
+}
+
+func runKeyboardType(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	locator, err := keyTypeLocator.optionalSpec(cmd, keyboardSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	typeCmd := map[string]interface{}{
+		"command": "keyboard-type",
+		"text":    keyboardText,
+		"delay":   keyboardDelay,
+		"timeout": keyboardTimeout,
+	}
+	if locator != nil {
+		withLocator(typeCmd, locator)
+	}
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, typeCmd)
+	if err != nil {
+		code, recovery := keyboardErrorCode(err)
+		printResponse(commandFailure(err, sessionErr, "type text", code, recovery, keyboardDetails(locator), startTime))
+		return
+	}
+
+	data := map[string]interface{}{
+		"session_id":      sessionID,
+		"keyboard_text":   keyboardText,
+		"typed":           true,
+		"focused_element": result.Data["focused_element"],
+	}
+	if locator != nil {
+		data["element_selector"] = locator.String()
+		data = withSelectorMatch(data, result)
+	}
+	printResponse(response.Success(data, startTime))
+}
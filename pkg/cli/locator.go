@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// locatorSpec describes how to find an element. A CSS/XPath selector can be
+// combined with one Playwright getBy* engine (the selector then scopes the
+// search), plus optional filters and an index. Fallbacks and Key enable
+// self-healing (see healing.go).
+type locatorSpec struct {
+	Selector    string `json:"selector,omitempty"`
+	Role        string `json:"role,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Label       string `json:"label,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Exact       bool   `json:"exact,omitempty"`
+	Placeholder string `json:"placeholder,omitempty"`
+	TestID      string `json:"test_id,omitempty"`
+	HasText     string `json:"has_text,omitempty"`
+	Has         string `json:"has,omitempty"`
+	Nth         *int   `json:"nth,omitempty"`
+
+	Fallbacks []string `json:"fallbacks,omitempty"`
+	Key       string   `json:"key,omitempty"`
+}
+
+// validate checks that the spec identifies an element unambiguously
+func (s *locatorSpec) validate() error {
+	engines := 0
+	for _, value := range []string{s.Role, s.Label, s.Text, s.Placeholder, s.TestID} {
+		if value != "" {
+			engines++
+		}
+	}
+
+	if engines > 1 {
+		return fmt.Errorf("only one of --role, --label, --text, --placeholder, --test-id may be used")
+	}
+	if engines == 0 && s.Selector == "" {
+		return fmt.Errorf("an element selector or locator option (--role, --label, --text, --placeholder, --test-id) is required")
+	}
+	if s.Name != "" && s.Role == "" {
+		return fmt.Errorf("--name requires --role")
+	}
+
+	return nil
+}
+
+// payload converts the spec into the runner's locator format
+func (s *locatorSpec) payload() map[string]interface{} {
+	payload := map[string]interface{}{}
+	fields := map[string]string{
+		"selector":    s.Selector,
+		"role":        s.Role,
+		"name":        s.Name,
+		"label":       s.Label,
+		"text":        s.Text,
+		"placeholder": s.Placeholder,
+		"testId":      s.TestID,
+		"hasText":     s.HasText,
+		"has":         s.Has,
+	}
+	for key, value := range fields {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	if s.Exact {
+		payload["exact"] = true
+	}
+	if s.Nth != nil {
+		payload["nth"] = *s.Nth
+	}
+	return payload
+}
+
+// String renders the spec in Playwright's selector-like notation,
+// e.g. `form#login >> role=button[name="로그인"] >> nth=0`. A plain selector is
+// returned unchanged.
+func (s *locatorSpec) String() string {
+	parts := make([]string, 0, 4)
+	if s.Selector != "" {
+		parts = append(parts, s.Selector)
+	}
+
+	switch {
+	case s.Role != "":
+		if s.Name != "" {
+			parts = append(parts, fmt.Sprintf("role=%s[name=%q]", s.Role, s.Name))
+		} else {
+			parts = append(parts, "role="+s.Role)
+		}
+	case s.Label != "":
+		parts = append(parts, fmt.Sprintf("label=%q", s.Label))
+	case s.Text != "":
+		parts = append(parts, fmt.Sprintf("text=%q", s.Text))
+	case s.Placeholder != "":
+		parts = append(parts, fmt.Sprintf("placeholder=%q", s.Placeholder))
+	case s.TestID != "":
+		parts = append(parts, fmt.Sprintf("test-id=%q", s.TestID))
+	}
+
+	if s.HasText != "" {
+		parts = append(parts, fmt.Sprintf("has-text=%q", s.HasText))
+	}
+	if s.Has != "" {
+		parts = append(parts, fmt.Sprintf("has=%q", s.Has))
+	}
+	if s.Nth != nil {
+		parts = append(parts, fmt.Sprintf("nth=%d", *s.Nth))
+	}
+
+	return strings.Join(parts, " >> ")
+}
+
+// locatorFlags binds the locator options of one element command
+type locatorFlags struct {
+	role        string
+	name        string
+	label       string
+	text        string
+	exact       bool
+	placeholder string
+	testID      string
+	hasText     string
+	has         string
+	nth         int
+	fallbacks   []string
+	key         string
+}
+
+// addLocatorFlags registers the getBy* locator options on an element command
+func addLocatorFlags(cmd *cobra.Command, f *locatorFlags) {
+	cmd.Flags().StringVar(&f.role, "role", "", "Locate by ARIA role (button, textbox, link, ...)")
+	cmd.Flags().StringVar(&f.name, "name", "", "Accessible name used with --role")
+	cmd.Flags().StringVar(&f.label, "label", "", "Locate a form control by its label text")
+	cmd.Flags().StringVar(&f.text, "text", "", "Locate by text content (use /pattern/flags for a regex)")
+	cmd.Flags().BoolVar(&f.exact, "exact", false, "Match --name/--label/--text/--placeholder exactly (case-sensitive, whole string)")
+	cmd.Flags().StringVar(&f.placeholder, "placeholder", "", "Locate an input by its placeholder text")
+	cmd.Flags().StringVar(&f.testID, "test-id", "", "Locate by data-testid attribute")
+	cmd.Flags().StringVar(&f.hasText, "has-text", "", "Keep only matches containing this text (use /pattern/flags for a regex)")
+	cmd.Flags().StringVar(&f.has, "has", "", "Keep only matches containing a descendant matching this selector")
+	cmd.Flags().IntVar(&f.nth, "nth", 0, "Pick the n-th match (0-based, negative counts from the end)")
+	cmd.Flags().StringArrayVar(&f.fallbacks, "fallback-selector", nil, "Fallback selector tried in order when the locator matches nothing (repeatable)")
+	cmd.Flags().StringVar(&f.key, "selector-key", "", "Name under which the matched selector and element fingerprint are stored for self-healing")
+}
+
+// spec builds the locator for this invocation from the flags and the
+// command's --element-selector value
+func (f *locatorFlags) spec(cmd *cobra.Command, selector string) (*locatorSpec, error) {
+	spec := &locatorSpec{
+		Selector:    selector,
+		Role:        f.role,
+		Name:        f.name,
+		Label:       f.label,
+		Text:        f.text,
+		Exact:       f.exact,
+		Placeholder: f.placeholder,
+		TestID:      f.testID,
+		HasText:     f.hasText,
+		Has:         f.has,
+		Fallbacks:   f.fallbacks,
+		Key:         f.key,
+	}
+	if cmd.Flags().Changed("nth") {
+		nth := f.nth
+		spec.Nth = &nth
+	}
+
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// optionalSpec is spec for commands where the element is optional: it
+// returns nil when neither a selector nor any locator option was given
+func (f *locatorFlags) optionalSpec(cmd *cobra.Command, selector string) (*locatorSpec, error) {
+	given := selector != "" || len(f.fallbacks) > 0 || cmd.Flags().Changed("nth")
+	for _, value := range []string{f.role, f.name, f.label, f.text, f.placeholder, f.testID, f.hasText, f.has, f.key} {
+		given = given || value != ""
+	}
+	if !given {
+		return nil, nil
+	}
+	return f.spec(cmd, selector)
+}
+
+// withLocator adds the locator, and any self-healing candidates, to a runner
+// command payload
+func withLocator(command map[string]interface{}, spec *locatorSpec) map[string]interface{} {
+	command["selector"] = spec.String()
+	command["locator"] = spec.payload()
+	addHealingCandidates(command, spec)
+	return command
+}
+
+// invalidLocatorResponse reports a locator flag combination that cannot be used
+func invalidLocatorResponse(err error, startTime time.Time) *response.StandardResponse {
+	return response.Error(
+		"INVALID_LOCATOR",
+		"Invalid element locator: "+err.Error(),
+		"Provide --element-selector or exactly one of --role, --label, --text, --placeholder, --test-id",
+		map[string]interface{}{
+			"session_id": sessionID,
+		},
+		startTime,
+	)
+}
@@ -0,0 +1,426 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// errInvalidMouseOptions reports unusable button, modifier or position flags
+const errInvalidMouseOptions = "INVALID_MOUSE_OPTIONS"
+
+// Modifier keys accepted by --modifiers, by lower-case name
+var mouseModifiers = map[string]string{
+	"alt":           "Alt",
+	"control":       "Control",
+	"ctrl":          "Control",
+	"controlormeta": "ControlOrMeta",
+	"meta":          "Meta",
+	"shift":         "Shift",
+}
+
+// mouseOptions are the pointer flags shared by click, hover and mouse
+// commands
+type mouseOptions struct {
+	button     string
+	clickCount int
+	modifiers  []string
+	position   string
+	delay      int
+}
+
+// payload validates the options and adds them to a runner command in
+// Playwright's option names
+func (o *mouseOptions) payload(command map[string]interface{}) error {
+	if o.button != "" {
+		switch o.button {
+		case "left", "right", "middle":
+			command["button"] = o.button
+		default:
+			return fmt.Errorf("unknown button %q (use left, right or middle)", o.button)
+		}
+	}
+	if o.clickCount != 0 {
+		if o.clickCount < 1 {
+			return fmt.Errorf("--click-count must be at least 1")
+		}
+		command["clickCount"] = o.clickCount
+	}
+	if o.delay < 0 {
+		return fmt.Errorf("--delay-ms must not be negative")
+	}
+	if o.delay > 0 {
+		command["delay"] = o.delay
+	}
+
+	if len(o.modifiers) > 0 {
+		modifiers := make([]string, 0, len(o.modifiers))
+		for _, name := range o.modifiers {
+			modifier, ok := mouseModifiers[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return fmt.Errorf("unknown modifier %q (use Alt, Control, ControlOrMeta, Meta or Shift)", name)
+			}
+			modifiers = append(modifiers, modifier)
+		}
+		command["modifiers"] = modifiers
+	}
+
+	if o.position != "" {
+		position, err := parsePoint(o.position)
+		if err != nil {
+			return fmt.Errorf("invalid --position: %w", err)
+		}
+		command["position"] = position
+	}
+	return nil
+}
+
+// parsePoint reads "x,y" into Playwright's {x, y} format
+func parsePoint(value string) (map[string]interface{}, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected x,y, got %q", value)
+	}
+	x, errX := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errX != nil || errY != nil {
+		return nil, fmt.Errorf("expected numeric x,y, got %q", value)
+	}
+	return map[string]interface{}{"x": x, "y": y}, nil
+}
+
+// mouseRecovery is the recovery hint of a mouse command the runner rejected
+const mouseRecovery = "Check that the element is visible and not covered by another element"
+
+// invalidMouseResponse reports unusable mouse flags
+func invalidMouseResponse(err error, startTime time.Time) *response.StandardResponse {
+	return response.Error(
+		errInvalidMouseOptions,
+		"Invalid mouse options: "+err.Error(),
+		"Check --button, --click-count, --modifiers and x,y positions",
+		map[string]interface{}{
+			"session_id": sessionID,
+		},
+		startTime,
+	)
+}
+
+var (
+	hoverSelector string
+	hoverTimeout  int
+	hoverMouse    mouseOptions
+	hoverLocator  locatorFlags
+)
+
+var elementHoverCmd = &cobra.Command{
+	Use:   "element-hover",
+	Short: "Move the mouse over an element",
+	Long: `Hover over an element, e.g. to open a dropdown menu that only appears on
+mouseover. --position hovers a point relative to the element's top-left corner.`,
+	Run: runElementHover,
+}
+
+var (
+	dragSelector       string
+	dragTarget         string
+	dragToX            float64
+	dragToY            float64
+	dragSourcePosition string
+	dragTargetPosition string
+	dragSteps          int
+	dragTimeout        int
+	dragLocator        locatorFlags
+)
+
+var elementDragCmd = &cobra.Command{
+	Use:   "element-drag",
+	Short: "Drag an element onto another element or a point",
+	Long: `Drag an element and drop it on the element matching --to (CSS selector or
+XPath), or on the page coordinates --to-x/--to-y, e.g. to reorder sortable
+lists or move canvas objects.
+
+--source-position and --target-position pick the grab and drop points
+relative to the elements' top-left corners; --steps sets how many mouse
+moves the drag to coordinates takes (some widgets ignore a single jump).`,
+	Run: runElementDrag,
+}
+
+var (
+	mouseX     float64
+	mouseY     float64
+	mouseSteps int
+	mouseClick mouseOptions
+)
+
+var mouseMoveCmd = &cobra.Command{
+	Use:   "mouse-move",
+	Short: "Move the mouse to page coordinates",
+	Long: `Move the mouse to --x/--y in CSS pixels from the top-left corner of the
+viewport. --steps interpolates intermediate mousemove events.`,
+	Run: runMouseMove,
+}
+
+var mouseClickCmd = &cobra.Command{
+	Use:   "mouse-click",
+	Short: "Click at page coordinates",
+	Long: `Click at --x/--y in CSS pixels from the top-left corner of the viewport,
+e.g. on canvas widgets that have no elements to locate. The response describes
+the element found at that point.`,
+	Run: runMouseClick,
+}
+
+func init() {
+	elementHoverCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	elementHoverCmd.Flags().StringVar(&hoverSelector, "element-selector", "", "CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementHoverCmd, &hoverLocator)
+	elementHoverCmd.Flags().StringVar(&hoverMouse.position, "position", "", "Point to hover as x,y relative to the element's top-left corner")
+	elementHoverCmd.Flags().StringSliceVar(&hoverMouse.modifiers, "modifiers", nil, "Modifier keys held while hovering (Alt, Control, ControlOrMeta, Meta, Shift)")
+	elementHoverCmd.Flags().IntVar(&hoverTimeout, "timeout", 30000, "Hover timeout in milliseconds")
+	elementHoverCmd.MarkFlagRequired("session-id")
+
+	elementDragCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	elementDragCmd.Flags().StringVar(&dragSelector, "element-selector", "", "Element to drag: CSS selector or XPath (or use a locator option below)")
+	addLocatorFlags(elementDragCmd, &dragLocator)
+	elementDragCmd.Flags().StringVar(&dragTarget, "to", "", "Drop target: CSS selector or XPath")
+	elementDragCmd.Flags().Float64Var(&dragToX, "to-x", 0, "Drop point x in viewport CSS pixels (instead of --to)")
+	elementDragCmd.Flags().Float64Var(&dragToY, "to-y", 0, "Drop point y in viewport CSS pixels (instead of --to)")
+	elementDragCmd.Flags().StringVar(&dragSourcePosition, "source-position", "", "Grab point as x,y relative to the dragged element")
+	elementDragCmd.Flags().StringVar(&dragTargetPosition, "target-position", "", "Drop point as x,y relative to the --to element")
+	elementDragCmd.Flags().IntVar(&dragSteps, "steps", 10, "Mouse moves used to reach --to-x/--to-y")
+	elementDragCmd.Flags().IntVar(&dragTimeout, "timeout", 30000, "Drag timeout in milliseconds")
+	elementDragCmd.MarkFlagRequired("session-id")
+
+	mouseMoveCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	mouseMoveCmd.Flags().Float64Var(&mouseX, "x", 0, "X coordinate in viewport CSS pixels (required)")
+	mouseMoveCmd.Flags().Float64Var(&mouseY, "y", 0, "Y coordinate in viewport CSS pixels (required)")
+	mouseMoveCmd.Flags().IntVar(&mouseSteps, "steps", 1, "Number of intermediate mousemove events")
+	mouseMoveCmd.MarkFlagRequired("session-id")
+	mouseMoveCmd.MarkFlagRequired("x")
+	mouseMoveCmd.MarkFlagRequired("y")
+
+	mouseClickCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	mouseClickCmd.Flags().Float64Var(&mouseX, "x", 0, "X coordinate in viewport CSS pixels (required)")
+	mouseClickCmd.Flags().Float64Var(&mouseY, "y", 0, "Y coordinate in viewport CSS pixels (required)")
+	mouseClickCmd.Flags().StringVar(&mouseClick.button, "button", "left", "Mouse button (left|right|middle)")
+	mouseClickCmd.Flags().IntVar(&mouseClick.clickCount, "click-count", 1, "Number of clicks (2 for a double-click)")
+	mouseClickCmd.Flags().StringSliceVar(&mouseClick.modifiers, "modifiers", nil, "Modifier keys held during the click (Alt, Control, ControlOrMeta, Meta, Shift)")
+	mouseClickCmd.Flags().IntVar(&mouseClick.delay, "delay-ms", 0, "Time between mousedown and mouseup in milliseconds")
+	mouseClickCmd.MarkFlagRequired("session-id")
+	mouseClickCmd.MarkFlagRequired("x")
+	mouseClickCmd.MarkFlagRequired("y")
+}
+
+func runElementHover(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	locator, err := hoverLocator.spec(cmd, hoverSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
+	hoverCmd := map[string]interface{}{
+		"command": "hover",
+		"timeout": hoverTimeout,
+	}
+	if err := hoverMouse.payload(hoverCmd); err != nil {
+		printResponse(invalidMouseResponse(err, startTime))
+		return
+	}
+	withLocator(hoverCmd, locator)
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, hoverCmd)
+	if err != nil {
+		details := map[string]interface{}{
+			"session_id":       sessionID,
+			"element_selector": locator.String(),
+		}
+		printResponse(commandFailure(err, sessionErr, "hover element", response.ErrElementNotClickable, mouseRecovery, details, startTime))
+		return
+	}
+
+	resp := response.Success(withSelectorMatch(map[string]interface{}{
+		"session_id":       sessionID,
+		"element_selector": locator.String(),
+		"hovered":          true,
+	}, result), startTime)
+	printResponse(resp)
+}
+
+func runElementDrag(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	locator, err := dragLocator.spec(cmd, dragSelector)
+	if err != nil {
+		printResponse(invalidLocatorResponse(err, startTime))
+		return
+	}
+
+	dragCmd := map[string]interface{}{
+		"command": "drag",
+		"timeout": dragTimeout,
+	}
+	if err := dragDestination(cmd, dragCmd); err != nil {
+		printResponse(invalidMouseResponse(err, startTime))
+		return
+	}
+	withLocator(dragCmd, locator)
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, dragCmd)
+	if err != nil {
+		details := map[string]interface{}{
+			"session_id":       sessionID,
+			"element_selector": locator.String(),
+		}
+		if dragTarget != "" {
+			details["to"] = dragTarget
+		}
+		printResponse(commandFailure(err, sessionErr, "drag element", response.ErrElementNotClickable, mouseRecovery, details, startTime))
+		return
+	}
+
+	data := map[string]interface{}{
+		"session_id":       sessionID,
+		"element_selector": locator.String(),
+		"dropped":          true,
+	}
+	if dragTarget != "" {
+		data["to"] = dragTarget
+	} else {
+		data["to_x"] = dragToX
+		data["to_y"] = dragToY
+	}
+	printResponse(response.Success(withSelectorMatch(data, result), startTime))
+}
+
+// dragDestination adds the drop target or point and the grab and drop
+// positions to a drag command
+func dragDestination(cmd *cobra.Command, command map[string]interface{}) error {
+	hasPoint := cmd.Flags().Changed("to-x") || cmd.Flags().Changed("to-y")
+	switch {
+	case dragTarget != "" && hasPoint:
+		return fmt.Errorf("use either --to or --to-x/--to-y")
+	case dragTarget != "":
+		command["target"] = dragTarget
+	case cmd.Flags().Changed("to-x") && cmd.Flags().Changed("to-y"):
+		if dragTargetPosition != "" {
+			return fmt.Errorf("--target-position requires --to")
+		}
+		if dragSteps < 1 {
+			return fmt.Errorf("--steps must be at least 1")
+		}
+		command["point"] = map[string]interface{}{"x": dragToX, "y": dragToY}
+		command["steps"] = dragSteps
+	default:
+		return fmt.Errorf("--to or both --to-x and --to-y are required")
+	}
+
+	positions := []struct{ flag, key, value string }{
+		{"--source-position", "sourcePosition", dragSourcePosition},
+		{"--target-position", "targetPosition", dragTargetPosition},
+	}
+	for _, p := range positions {
+		if p.value == "" {
+			continue
+		}
+		position, err := parsePoint(p.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", p.flag, err)
+		}
+		command[p.key] = position
+	}
+	return nil
+}
+
+func runMouseMove(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	if mouseSteps < 1 {
+		printResponse(invalidMouseResponse(fmt.Errorf("--steps must be at least 1"), startTime))
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	moveCmd := map[string]interface{}{
+		"command": "mouse-move",
+		"x":       mouseX,
+		"y":       mouseY,
+		"steps":   mouseSteps,
+	}
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, moveCmd)
+	if err != nil {
+		details := map[string]interface{}{
+			"session_id": sessionID,
+			"x":          mouseX,
+			"y":          mouseY,
+		}
+		printResponse(commandFailure(err, sessionErr, "move mouse", response.ErrElementNotClickable, mouseRecovery, details, startTime))
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id": sessionID,
+		"x":          mouseX,
+		"y":          mouseY,
+		"element":    result.Data["element"],
+	}, startTime)
+	printResponse(resp)
+}
+
+func runMouseClick(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	clickCmd := map[string]interface{}{
+		"command": "mouse-click",
+		"x":       mouseX,
+		"y":       mouseY,
+	}
+	if err := mouseClick.payload(clickCmd); err != nil {
+		printResponse(invalidMouseResponse(err, startTime))
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, clickCmd)
+	if err != nil {
+		details := map[string]interface{}{
+			"session_id": sessionID,
+			"x":          mouseX,
+			"y":          mouseY,
+		}
+		printResponse(commandFailure(err, sessionErr, "click", response.ErrElementNotClickable, mouseRecovery, details, startTime))
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"session_id":  sessionID,
+		"x":           mouseX,
+		"y":           mouseY,
+		"button":      mouseClick.button,
+		"click_count": mouseClick.clickCount,
+		"clicked":     true,
+		"element":     result.Data["element"],
+	}, startTime)
+	printResponse(resp)
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	a11yAuditSelector   string
+	a11yAuditRules      []string
+	a11yAuditOutputPath string
+	a11yAuditReportPath string
+	a11yAuditTimeout    int
+)
+
+var pageA11yAuditCmd = &cobra.Command{
+	Use:   "page-a11y-audit",
+	Short: "Run an accessibility audit on the current page",
+	Long: `Run the bundled accessibility rule set against the current page (or a subtree)
+and report violations as JSON and, optionally, as an HTML report.
+
+Rules: image-alt, label, button-name, link-name, color-contrast, heading-order,
+page-has-heading-one, landmark-one-main, landmark-unique, html-has-lang, document-title.
+
+The audit runs entirely inside the page, so it works offline and against local
+file:// fixtures.`,
+	Run: runPageA11yAudit,
+}
+
+func init() {
+	pageA11yAuditCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	pageA11yAuditCmd.Flags().StringVar(&a11yAuditSelector, "element-selector", "", "CSS selector of the subtree to audit (optional, omit for full page)")
+	pageA11yAuditCmd.Flags().StringSliceVar(&a11yAuditRules, "rules", nil, "Comma-separated rule IDs to run (default: all)")
+	pageA11yAuditCmd.Flags().StringVar(&a11yAuditOutputPath, "output-path", "", "JSON results file path (optional, omit to return violations in JSON)")
+	pageA11yAuditCmd.Flags().StringVar(&a11yAuditReportPath, "report-path", "", "HTML report file path (optional)")
+	pageA11yAuditCmd.Flags().IntVar(&a11yAuditTimeout, "timeout-ms", 30000, "Time to wait for the page and --element-selector to be ready, in milliseconds")
+
+	pageA11yAuditCmd.MarkFlagRequired("session-id")
+}
+
+func runPageA11yAudit(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	auditCmd := map[string]interface{}{
+		"command": "a11y-audit",
+		"timeout": a11yAuditTimeout,
+	}
+	if a11yAuditSelector != "" {
+		auditCmd["selector"] = a11yAuditSelector
+	}
+	if len(a11yAuditRules) > 0 {
+		auditCmd["rules"] = a11yAuditRules
+	}
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, auditCmd)
+	if err != nil {
+		resp := response.Error(
+			commandErrorCode(err, response.ErrPageLoadFailed),
+			"Failed to run accessibility audit: "+err.Error(),
+			"Verify session ID and that a page is loaded",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": a11yAuditSelector,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	if !result.Success {
+		resp := response.Error(
+			response.ErrScriptExecutionFailed,
+			"Accessibility audit failed: "+result.Error,
+			"Check rule IDs and element selector",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": a11yAuditSelector,
+				"rules":            a11yAuditRules,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	audit, err := decodeA11yAuditResult(result.Data)
+	if err != nil {
+		resp := response.Error(
+			response.ErrScriptExecutionFailed,
+			"Failed to decode audit results: "+err.Error(),
+			"Internal error",
+			map[string]interface{}{
+				"session_id": sessionID,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"session_id":      sessionID,
+		"url":             audit.URL,
+		"title":           audit.Title,
+		"rules_checked":   audit.RulesChecked,
+		"passes":          audit.Passes,
+		"violation_count": audit.ViolationCount,
+		"summary":         audit.Summary,
+	}
+
+	if a11yAuditOutputPath != "" {
+		content, _ := json.MarshalIndent(audit, "", "  ")
+		if err := os.WriteFile(a11yAuditOutputPath, content, 0644); err != nil {
+			resp := response.Error(
+				response.ErrPageLoadFailed,
+				"Failed to write audit results: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"output_path": a11yAuditOutputPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["output_path"] = a11yAuditOutputPath
+	} else {
+		responseData["violations"] = audit.Violations
+	}
+
+	if a11yAuditReportPath != "" {
+		if err := writeA11yReport(a11yAuditReportPath, audit); err != nil {
+			resp := response.Error(
+				response.ErrPageLoadFailed,
+				"Failed to write HTML report: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"report_path": a11yAuditReportPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["report_path"] = a11yAuditReportPath
+	}
+
+	resp := response.Success(responseData, startTime)
+	printResponse(resp)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	accessibilitySelector        string
+	accessibilityInterestingOnly bool
+	accessibilityOutputPath      string
+	accessibilityTimeout         int
+)
+
+var pageAccessibilityCmd = &cobra.Command{
+	Use:   "page-accessibility",
+	Short: "Get the accessibility tree of the page or an element",
+	Long: `Return Playwright's accessibility snapshot (roles, names, states) for the whole page
+or for the subtree rooted at an element.
+
+With Playwright releases that no longer provide page.accessibility the snapshot is
+returned in ARIA snapshot (YAML) form; the "format" field tells which one was used.`,
+	Run: runPageAccessibility,
+}
+
+func init() {
+	pageAccessibilityCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	pageAccessibilityCmd.Flags().StringVar(&accessibilitySelector, "element-selector", "", "CSS selector or XPath of the subtree root (optional, omit for full page)")
+	pageAccessibilityCmd.Flags().BoolVar(&accessibilityInterestingOnly, "interesting-only", true, "Prune nodes that are not interesting for assistive technology")
+	pageAccessibilityCmd.Flags().StringVar(&accessibilityOutputPath, "output-path", "", "Output file path (optional, omit to return the snapshot in JSON)")
+	pageAccessibilityCmd.Flags().IntVar(&accessibilityTimeout, "timeout-ms", 30000, "Timeout in milliseconds")
+
+	pageAccessibilityCmd.MarkFlagRequired("session-id")
+}
+
+func runPageAccessibility(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	accessibilityCmd := map[string]interface{}{
+		"command":         "accessibility",
+		"interestingOnly": accessibilityInterestingOnly,
+		"timeout":         accessibilityTimeout,
+	}
+	if accessibilitySelector != "" {
+		accessibilityCmd["selector"] = accessibilitySelector
+	}
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, accessibilityCmd)
+	if err != nil {
+		resp := response.Error(
+			commandErrorCode(err, response.ErrPageLoadFailed),
+			"Failed to get accessibility snapshot: "+err.Error(),
+			"Verify session ID and element selector",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": accessibilitySelector,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	if !result.Success {
+		resp := response.Error(
+			response.ErrElementNotFound,
+			"Accessibility snapshot failed: "+result.Error,
+			"Check if the page is loaded and the element exists",
+			map[string]interface{}{
+				"session_id":       sessionID,
+				"element_selector": accessibilitySelector,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"session_id": sessionID,
+		"format":     result.Data["format"],
+		"node_count": result.Data["node_count"],
+	}
+	if accessibilitySelector != "" {
+		responseData["selector"] = accessibilitySelector
+	}
+
+	// Write to file if output path is provided
+	if accessibilityOutputPath != "" {
+		var content []byte
+		if yaml, ok := result.Data["snapshot"].(string); ok {
+			content = []byte(yaml)
+		} else {
+			content, _ = json.MarshalIndent(result.Data["snapshot"], "", "  ")
+		}
+
+		if err := os.WriteFile(accessibilityOutputPath, content, 0644); err != nil {
+			resp := response.Error(
+				response.ErrPageLoadFailed,
+				"Failed to write accessibility snapshot: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"output_path": accessibilityOutputPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["output_path"] = accessibilityOutputPath
+	} else {
+		responseData["snapshot"] = result.Data["snapshot"]
+	}
+
+	resp := response.Success(responseData, startTime)
+	printResponse(resp)
+}
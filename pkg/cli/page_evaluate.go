@@ -46,10 +46,10 @@ func runPageEvaluate(cmd *cobra.Command, args []string) {
 		"timeout": evaluateTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, evaluateCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, evaluateCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrSessionNotFound,
+			commandErrorCode(err, response.ErrSessionNotFound),
 			"Failed to execute script: "+err.Error(),
 			"Verify session ID is valid and session is still active",
 			map[string]interface{}{
@@ -57,7 +57,7 @@ func runPageEvaluate(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -72,7 +72,7 @@ func runPageEvaluate(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -86,5 +86,5 @@ func runPageEvaluate(cmd *cobra.Command, args []string) {
 		"result":      scriptResult,
 		"result_type": resultType,
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 }
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/extract"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractSchemaPath string
+	extractOutputPath string
+	extractTimeout    int
+)
+
+var pageExtractCmd = &cobra.Command{
+	Use:   "page-extract",
+	Short: "Extract structured records from the page using a schema",
+	Long: `Extract typed records from the page in a single round trip using a JSON schema.
+
+Every element matching "root" becomes one record (omit "root" for a single
+record of the whole page). When "root" matches nothing the result is empty
+right away; use element-wait first for items that render late. Each field has
+a CSS selector relative to the item, a source (text, attribute, property,
+html), an optional regex (first capture group wins) and transform (trim,
+lower, upper, number, integer, boolean, url).
+"list": true collects all matches; nested "fields" produce objects. A bare
+string is shorthand for a text field.
+
+  {
+    "root": "ul.products > li",
+    "limit": 50,
+    "fields": {
+      "name": "h3",
+      "price": {"selector": ".price", "regex": "([0-9,]+)원", "transform": "integer"},
+      "link": {"selector": "a", "source": "attribute", "attribute": "href", "transform": "url"},
+      "in_stock": {"selector": "input.stock", "source": "property", "property": "checked"},
+      "tags": {"selector": ".tag", "list": true},
+      "options": {"selector": ".option", "list": true,
+                  "fields": {"label": "span", "value": {"source": "attribute", "attribute": "data-value"}}}
+    }
+  }`,
+	Run: runPageExtract,
+}
+
+func init() {
+	pageExtractCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	pageExtractCmd.Flags().StringVar(&extractSchemaPath, "schema", "", "Schema file path or inline JSON (required)")
+	pageExtractCmd.Flags().StringVar(&extractOutputPath, "output-path", "", "Output file path (optional, omit to return records in JSON)")
+	pageExtractCmd.Flags().IntVar(&extractTimeout, "timeout-ms", 30000, "Time to wait for the page to be ready, in milliseconds")
+
+	pageExtractCmd.MarkFlagRequired("session-id")
+	pageExtractCmd.MarkFlagRequired("schema")
+}
+
+func runPageExtract(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	schema, err := extract.LoadSchema(extractSchemaPath)
+	if err != nil {
+		resp := response.Error(
+			"INVALID_SCHEMA",
+			"Invalid extraction schema: "+err.Error(),
+			"Check the schema file against the format shown in 'webauto page-extract --help'",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"schema":     extractSchemaPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Send extract command to session
+	extractCmd := map[string]interface{}{
+		"command": "extract",
+		"schema":  schema,
+		"timeout": extractTimeout,
+	}
+
+	result, err := sendCommand(ctx, sessionMgr, sessionID, extractCmd)
+	if err != nil {
+		resp := response.Error(
+			commandErrorCode(err, response.ErrPageLoadFailed),
+			"Failed to extract records: "+err.Error(),
+			"Verify session ID and schema selectors",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"schema":     extractSchemaPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	if !result.Success {
+		resp := response.Error(
+			response.ErrElementNotFound,
+			"Extraction failed: "+result.Error,
+			"Check that schema selectors are valid CSS and regexes are valid JavaScript patterns",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"schema":     extractSchemaPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"session_id":     sessionID,
+		"url":            result.Data["url"],
+		"record_count":   result.Data["record_count"],
+		"root_count":     result.Data["root_count"],
+		"missing_fields": result.Data["missing_fields"],
+	}
+
+	// Write to file if output path is provided
+	if extractOutputPath != "" {
+		content, err := json.MarshalIndent(result.Data["records"], "", "  ")
+		if err == nil {
+			err = os.WriteFile(extractOutputPath, content, 0644)
+		}
+		if err != nil {
+			resp := response.Error(
+				response.ErrPageLoadFailed,
+				"Failed to write records file: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"output_path": extractOutputPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["output_path"] = extractOutputPath
+	} else {
+		responseData["records"] = result.Data["records"]
+	}
+
+	// Success response
+	resp := response.Success(responseData, startTime)
+	printResponse(resp)
+}
+
+// GetPageExtractCommand returns the page-extract command for registration
+func GetPageExtractCommand() *cobra.Command {
+	return pageExtractCmd
+}
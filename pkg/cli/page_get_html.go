@@ -50,10 +50,10 @@ func runPageGetHtml(cmd *cobra.Command, args []string) {
 		getHtmlCmd["selector"] = getHtmlSelector
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, getHtmlCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, getHtmlCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrPageLoadFailed,
+			commandErrorCode(err, response.ErrPageLoadFailed),
 			"Failed to get HTML: "+err.Error(),
 			"Verify session ID and element selector",
 			map[string]interface{}{
@@ -62,7 +62,7 @@ func runPageGetHtml(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -77,7 +77,7 @@ func runPageGetHtml(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -93,7 +93,7 @@ func runPageGetHtml(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -122,7 +122,7 @@ func runPageGetHtml(cmd *cobra.Command, args []string) {
 				},
 				startTime,
 			)
-			resp.Print()
+			printResponse(resp)
 			return
 		}
 		responseData["output_path"] = getHtmlOutputPath
@@ -133,7 +133,7 @@ func runPageGetHtml(cmd *cobra.Command, args []string) {
 
 	// Success response
 	resp := response.Success(responseData, startTime)
-	resp.Print()
+	printResponse(resp)
 }
 
 // GetPageGetHtmlCommand returns the page-get-html command for registration
@@ -47,10 +47,10 @@ func runPageNavigate(cmd *cobra.Command, args []string) {
 		"timeout":   navTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, navCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, navCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrPageNavigationFailed,
+			commandErrorCode(err, response.ErrPageNavigationFailed),
 			"Failed to navigate: "+err.Error(),
 			"Verify session ID and ensure URL is valid",
 			map[string]interface{}{
@@ -59,22 +59,26 @@ func runPageNavigate(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
 	if !result.Success {
+		code, recovery := response.ErrPageNavigationFailed, "Check URL and network connectivity"
+		if failure, _ := playwright.ClassifyFailure(result, nil); failure == response.ErrBotDetectionTriggered {
+			code, recovery = failure, "The site blocked the request; with a proxy pool the session has moved to the next proxy, retry with --retry-on BOT_DETECTION_TRIGGERED"
+		}
 		resp := response.Error(
-			response.ErrPageNavigationFailed,
+			code,
 			"Navigation failed: "+result.Error,
-			"Check URL and network connectivity",
+			recovery,
 			map[string]interface{}{
 				"session_id": sessionID,
 				"page_url":   pageURL,
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -90,5 +94,5 @@ func runPageNavigate(cmd *cobra.Command, args []string) {
 		"wait_until": waitUntil,
 		"timeout_ms": navTimeout,
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 }
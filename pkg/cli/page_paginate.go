@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/extract"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// Pagination strategies
+const (
+	paginateStrategyClick  = "click"
+	paginateStrategyScroll = "scroll"
+)
+
+var (
+	paginateSchemaPath   string
+	paginateStrategy     string
+	paginateNextSelector string
+	paginateStopSelector string
+	paginateMaxPages     int
+	paginateDedupeKeys   []string
+	paginateKeepGoing    bool
+	paginateSettle       int
+	paginateOutputPath   string
+	paginateTimeout      int
+)
+
+var pagePaginateCmd = &cobra.Command{
+	Use:   "page-paginate",
+	Short: "Extract records across paginated or infinitely scrolling lists",
+	Long: `Run a page-extract schema on the current page, advance to the next page, and
+repeat until a stop condition is met. Records are deduplicated across pages.
+
+Strategies:
+  click   Click --next-selector (numbered pager "다음", next button, "더보기")
+  scroll  Scroll to the bottom and wait for more items (infinite scroll)
+
+Stop reasons reported in stop_reason:
+  max_pages, next_not_found, next_disabled, content_unchanged, no_more_content,
+  no_new_items (a page yielded only duplicates), stop_selector_gone
+
+The schema "root" selector is used to detect that new items appeared.
+
+Examples:
+  webauto page-paginate --session-id ses_abc --schema products.json \
+    --next-selector "a.next" --max-pages 20 --dedupe-key link
+  webauto page-paginate --session-id ses_abc --schema feed.json --strategy scroll --max-pages 50`,
+	Run: runPagePaginate,
+}
+
+func init() {
+	pagePaginateCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	pagePaginateCmd.Flags().StringVar(&paginateSchemaPath, "schema", "", "Extraction schema file path or inline JSON, run on every page (required)")
+	pagePaginateCmd.Flags().StringVar(&paginateStrategy, "strategy", paginateStrategyClick, "Pagination strategy: click or scroll")
+	pagePaginateCmd.Flags().StringVar(&paginateNextSelector, "next-selector", "", "Selector of the next page / load more control (required for click strategy)")
+	pagePaginateCmd.Flags().StringVar(&paginateStopSelector, "stop-selector", "", "Stop when this selector no longer matches (optional)")
+	pagePaginateCmd.Flags().IntVar(&paginateMaxPages, "max-pages", 10, "Maximum number of pages to visit")
+	pagePaginateCmd.Flags().StringSliceVar(&paginateDedupeKeys, "dedupe-key", nil, "Record fields identifying an item (default: the whole record)")
+	pagePaginateCmd.Flags().BoolVar(&paginateKeepGoing, "continue-on-duplicates", false, "Keep paginating when a page yields no new items")
+	pagePaginateCmd.Flags().IntVar(&paginateSettle, "settle-ms", 0, "Extra wait after new content appears, in milliseconds")
+	pagePaginateCmd.Flags().StringVar(&paginateOutputPath, "output-path", "", "Output file path (optional, omit to return records in JSON)")
+	pagePaginateCmd.Flags().IntVar(&paginateTimeout, "timeout-ms", 10000, "Timeout for each page transition in milliseconds")
+
+	pagePaginateCmd.MarkFlagRequired("session-id")
+	pagePaginateCmd.MarkFlagRequired("schema")
+}
+
+func runPagePaginate(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	if paginateStrategy != paginateStrategyClick && paginateStrategy != paginateStrategyScroll {
+		resp := response.Error(
+			"INVALID_PAGINATION_STRATEGY",
+			fmt.Sprintf("Unknown pagination strategy: %s", paginateStrategy),
+			"Use --strategy click or --strategy scroll",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"strategy":   paginateStrategy,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+	if paginateStrategy == paginateStrategyClick && paginateNextSelector == "" {
+		resp := response.Error(
+			"INVALID_PAGINATION_STRATEGY",
+			"The click strategy requires --next-selector",
+			"Provide --next-selector or use --strategy scroll",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"strategy":   paginateStrategy,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	schema, err := extract.LoadSchema(paginateSchemaPath)
+	if err != nil {
+		resp := response.Error(
+			"INVALID_SCHEMA",
+			"Invalid extraction schema: "+err.Error(),
+			"Check the schema file against the format shown in 'webauto page-extract --help'",
+			map[string]interface{}{
+				"session_id": sessionID,
+				"schema":     paginateSchemaPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	seen := make(map[string]bool)
+	records := make([]interface{}, 0)
+	pages := make([]map[string]interface{}, 0)
+	duplicates := 0
+	stopReason := "max_pages"
+
+	for pageNumber := 1; ; pageNumber++ {
+		result, err := sendCommand(ctx, sessionMgr, sessionID, map[string]interface{}{
+			"command": "extract",
+			"schema":  schema,
+			"timeout": paginateTimeout,
+		})
+		if err == nil && !result.Success {
+			err = fmt.Errorf("%s", result.Error)
+		}
+		if err != nil {
+			resp := response.Error(
+				commandErrorCode(err, response.ErrElementNotFound),
+				fmt.Sprintf("Extraction failed on page %d: %s", pageNumber, err.Error()),
+				"Verify session ID and schema selectors",
+				map[string]interface{}{
+					"session_id":   sessionID,
+					"page":         pageNumber,
+					"record_count": len(records),
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+
+		pageRecords, _ := result.Data["records"].([]interface{})
+		newRecords := 0
+		for _, record := range pageRecords {
+			key := dedupeKey(record, paginateDedupeKeys)
+			if seen[key] {
+				duplicates++
+				continue
+			}
+			seen[key] = true
+			records = append(records, record)
+			newRecords++
+		}
+
+		pages = append(pages, map[string]interface{}{
+			"page":        pageNumber,
+			"url":         result.Data["url"],
+			"records":     len(pageRecords),
+			"new_records": newRecords,
+		})
+
+		if newRecords == 0 && pageNumber > 1 && !paginateKeepGoing {
+			stopReason = "no_new_items"
+			break
+		}
+		if pageNumber >= paginateMaxPages {
+			break
+		}
+
+		stepCmd := map[string]interface{}{
+			"command":  "paginate-step",
+			"strategy": paginateStrategy,
+			"timeout":  paginateTimeout,
+			"settleMs": paginateSettle,
+		}
+		if paginateNextSelector != "" {
+			stepCmd["nextSelector"] = paginateNextSelector
+		}
+		if paginateStopSelector != "" {
+			stepCmd["stopSelector"] = paginateStopSelector
+		}
+		if schema.Root != "" {
+			stepCmd["itemSelector"] = schema.Root
+		}
+
+		step, err := sendCommand(ctx, sessionMgr, sessionID, stepCmd)
+		if err == nil && !step.Success {
+			err = fmt.Errorf("%s", step.Error)
+		}
+		if err != nil {
+			resp := response.Error(
+				commandErrorCode(err, response.ErrElementNotClickable),
+				fmt.Sprintf("Failed to advance past page %d: %s", pageNumber, err.Error()),
+				"Check --next-selector and that the page is still open",
+				map[string]interface{}{
+					"session_id":    sessionID,
+					"page":          pageNumber,
+					"next_selector": paginateNextSelector,
+					"record_count":  len(records),
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+
+		if advanced, _ := step.Data["advanced"].(bool); !advanced {
+			stopReason, _ = step.Data["reason"].(string)
+			break
+		}
+	}
+
+	responseData := map[string]interface{}{
+		"session_id":         sessionID,
+		"strategy":           paginateStrategy,
+		"page_count":         len(pages),
+		"stop_reason":        stopReason,
+		"record_count":       len(records),
+		"duplicates_skipped": duplicates,
+		"pages":              pages,
+	}
+
+	// Write to file if output path is provided
+	if paginateOutputPath != "" {
+		content, err := json.MarshalIndent(records, "", "  ")
+		if err == nil {
+			err = os.WriteFile(paginateOutputPath, content, 0644)
+		}
+		if err != nil {
+			resp := response.Error(
+				response.ErrPageLoadFailed,
+				"Failed to write records file: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"output_path": paginateOutputPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["output_path"] = paginateOutputPath
+	} else {
+		responseData["records"] = records
+	}
+
+	// Success response
+	resp := response.Success(responseData, startTime)
+	printResponse(resp)
+}
+
+// dedupeKey identifies a record by the given fields, or by its full content.
+// encoding/json sorts map keys, so the encoding is stable.
+func dedupeKey(record interface{}, fields []string) string {
+	if object, ok := record.(map[string]interface{}); ok && len(fields) > 0 {
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			values[i] = object[field]
+		}
+		record = values
+	}
+
+	encoded, _ := json.Marshal(record)
+	return string(encoded)
+}
+
+// GetPagePaginateCommand returns the page-paginate command for registration
+func GetPagePaginateCommand() *cobra.Command {
+	return pagePaginateCmd
+}
@@ -54,10 +54,10 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 		"timeout":         pdfTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, pdfCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, pdfCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrPageLoadFailed,
+			commandErrorCode(err, response.ErrPageLoadFailed),
 			"Failed to generate PDF: "+err.Error(),
 			"Verify session ID and page is loaded",
 			map[string]interface{}{
@@ -66,7 +66,7 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -81,7 +81,7 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -97,7 +97,7 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -112,7 +112,7 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -128,7 +128,7 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -148,5 +148,5 @@ func runPagePdf(cmd *cobra.Command, args []string) {
 		"print_background": printBackground,
 		"file_size":        fileSize,
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 }
@@ -55,10 +55,10 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 		"timeout":  screenshotTimeout,
 	}
 
-	result, err := sessionMgr.SendCommand(ctx, sessionID, screenshotCmd)
+	result, err := sendCommand(ctx, sessionMgr, sessionID, screenshotCmd)
 	if err != nil {
 		resp := response.Error(
-			response.ErrPageLoadFailed,
+			commandErrorCode(err, response.ErrPageLoadFailed),
 			"Failed to take screenshot: "+err.Error(),
 			"Verify session ID and page is loaded",
 			map[string]interface{}{
@@ -67,7 +67,7 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -82,7 +82,7 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -98,7 +98,7 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -113,7 +113,7 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -129,7 +129,7 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -160,5 +160,5 @@ func runPageScreenshot(cmd *cobra.Command, args []string) {
 		"image_width":  imageWidth,
 		"image_height": imageHeight,
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 }
@@ -1,15 +1,45 @@
 package cli
 
 import (
+	"os"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+	"github.com/oa-plugins/webauto/pkg/response"
 	"github.com/spf13/cobra"
 )
 
+// progressMode selects how runtime setup progress is written to stderr
+var progressMode string
+
 var rootCmd = &cobra.Command{
 	Use:     "webauto",
 	Short:   "Playwright Agents-based intelligent browser automation",
 	Long:    `webauto is a Playwright Agents-based intelligent browser automation plugin for OA CLI system.
 It targets Korean tax/accounting services (Hometax, Wehago) with sophisticated UI automation and anti-bot capabilities.`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		startTime := time.Now()
+		if err := bootstrap.SetProgressMode(progressMode); err != nil {
+			printResponse(response.Error(
+				"INVALID_PROGRESS_MODE",
+				err.Error(),
+				"Use --progress text, json or quiet (or set "+bootstrap.ProgressEnv+")",
+				map[string]interface{}{
+					"progress": progressMode,
+				},
+				startTime,
+			))
+			return silenced(cmd, err)
+		}
+
+		if needsRuntime(cmd) {
+			if err := setupRuntime(startTime); err != nil {
+				return silenced(cmd, err)
+			}
+		}
+		return nil
+	},
 }
 
 func init() {
@@ -24,15 +54,48 @@ func init() {
 	rootCmd.AddCommand(elementGetAttributeCmd)
 	rootCmd.AddCommand(elementWaitCmd)
 	rootCmd.AddCommand(elementQueryAllCmd)
+	rootCmd.AddCommand(elementExtractTableCmd)
 	rootCmd.AddCommand(formFillCmd)
 	rootCmd.AddCommand(pageScreenshotCmd)
 	rootCmd.AddCommand(pageGetHtmlCmd)
+	rootCmd.AddCommand(pageExtractCmd)
+	rootCmd.AddCommand(pagePaginateCmd)
+	rootCmd.AddCommand(crawlCmd)
 	rootCmd.AddCommand(pagePdfCmd)
+	rootCmd.AddCommand(pageAccessibilityCmd)
+	rootCmd.AddCommand(pageA11yAuditCmd)
+	rootCmd.AddCommand(cookieListCmd)
+	rootCmd.AddCommand(cookieSetCmd)
+	rootCmd.AddCommand(cookieDeleteCmd)
+	rootCmd.AddCommand(cookieClearCmd)
+	rootCmd.AddCommand(cookieExportCmd)
+	rootCmd.AddCommand(cookieImportCmd)
+	rootCmd.AddCommand(storageGetCmd)
+	rootCmd.AddCommand(storageSetCmd)
+	rootCmd.AddCommand(storageClearCmd)
+	rootCmd.AddCommand(storageDumpCmd)
+	rootCmd.AddCommand(indexedDBDumpCmd)
+	rootCmd.AddCommand(keyboardPressCmd)
+	rootCmd.AddCommand(keyboardTypeCmd)
+	rootCmd.AddCommand(elementHoverCmd)
+	rootCmd.AddCommand(elementDragCmd)
+	rootCmd.AddCommand(mouseMoveCmd)
+	rootCmd.AddCommand(mouseClickCmd)
+	rootCmd.AddCommand(elementSelectCmd)
+	rootCmd.AddCommand(elementCheckCmd)
+	rootCmd.AddCommand(elementUncheckCmd)
 	rootCmd.AddCommand(sessionListCmd)
 	rootCmd.AddCommand(sessionCloseCmd)
+	rootCmd.AddCommand(sessionPoolCmd)
+	rootCmd.AddCommand(runtimeCmd)
+	rootCmd.AddCommand(doctorCmd)
 
 	// Global flags
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().IntVar(&retryCount, "retries", 0, "Number of retries for failed browser commands")
+	rootCmd.PersistentFlags().DurationVar(&retryBackoff, "retry-backoff", 500*time.Millisecond, "Delay before the first retry, doubled on each further retry")
+	rootCmd.PersistentFlags().StringVar(&progressMode, "progress", os.Getenv(bootstrap.ProgressEnv), "Runtime setup progress on stderr: text, json (NDJSON events) or quiet")
+	rootCmd.PersistentFlags().StringSliceVar(&retryOnCodes, "retry-on", nil, "Error codes to retry for any command (e.g. ELEMENT_NOT_FOUND,TIMEOUT_EXCEEDED)")
 }
 
 // Execute runs the root command
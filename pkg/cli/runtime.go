@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+	"github.com/oa-plugins/webauto/pkg/config"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installBrowserForce bool
+	installBrowserDeps  bool
+	bundleOutputPath    string
+	bundleBrowsers      []string
+	installFrom         string
+	installSHA256       string
+)
+
+var runtimeCmd = &cobra.Command{
+	Use:   "runtime",
+	Short: "Manage the Node.js and Playwright runtime",
+	Long: `Manage the runtime webauto sets up on first run: Node.js, the Playwright
+library and the browser binaries, all kept under the webauto cache directory.`,
+}
+
+var runtimeInstallBrowserCmd = &cobra.Command{
+	Use:   "install-browser <chromium|firefox|webkit>",
+	Short: "Install a Playwright browser binary",
+	Long: `Install a Playwright browser binary. Chromium is installed on first run;
+Firefox and WebKit are installed by browser-launch when first used, unless
+BROWSER_AUTO_INSTALL=false, in which case install them with this command.
+--with-deps also installs the browser's system libraries through the OS
+package manager, which needs root (or sudo). Installer output is written to
+stderr.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRuntimeInstallBrowser,
+}
+
+var runtimeBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Package the runtime into an archive for offline hosts",
+	Long: `Package Node.js, the Playwright node_modules and the browser binaries into one
+.tar.gz archive. Run it on a connected machine with the same OS and CPU
+architecture as the offline hosts, then install the archive there with
+"runtime install --from" or WEBAUTO_RUNTIME_BUNDLE.
+
+System libraries the browsers depend on are not part of the bundle and must
+be present on the target host.`,
+	Run: runRuntimeBundle,
+}
+
+var runtimeInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the runtime, optionally from an offline bundle",
+	Long: `Install the runtime. With --from (or WEBAUTO_RUNTIME_BUNDLE) the runtime is
+installed from a bundle created by "runtime bundle" without network access;
+otherwise Node.js, Playwright and Chromium are downloaded.`,
+	Run: runRuntimeInstall,
+}
+
+func init() {
+	runtimeInstallBrowserCmd.Flags().BoolVar(&installBrowserForce, "force", false, "Reinstall even if the browser is already installed")
+	runtimeInstallBrowserCmd.Flags().BoolVar(&installBrowserDeps, "with-deps", false, "Also install the browser's system libraries (needs root)")
+
+	runtimeBundleCmd.Flags().StringVar(&bundleOutputPath, "output-path", "", "Bundle file to write (default: webauto-runtime-<os>-<arch>.tar.gz)")
+	runtimeBundleCmd.Flags().StringSliceVar(&bundleBrowsers, "browsers", nil, "Browsers to include (default: all installed browsers)")
+
+	runtimeInstallCmd.Flags().StringVar(&installFrom, "from", "", "Runtime bundle (.tar.gz or .tar.xz) to install from (default: $"+bootstrap.RuntimeBundleEnv+")")
+	runtimeInstallCmd.Flags().StringVar(&installSHA256, "sha256", "", "Expected SHA-256 of the bundle, as printed by runtime bundle (default: $"+bootstrap.RuntimeBundleSHA256Env+")")
+
+	runtimeCmd.AddCommand(runtimeInstallBrowserCmd)
+	runtimeCmd.AddCommand(runtimeBundleCmd)
+	runtimeCmd.AddCommand(runtimeInstallCmd)
+}
+
+func runRuntimeInstallBrowser(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	browser := args[0]
+
+	if !bootstrap.IsSupportedBrowser(browser) {
+		resp := response.Error(
+			"INVALID_BROWSER_TYPE",
+			fmt.Sprintf("Unsupported browser type: %s", browser),
+			"Use one of: "+strings.Join(bootstrap.SupportedBrowsers, ", "),
+			map[string]interface{}{
+				"browser_type": browser,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	alreadyInstalled := bootstrap.IsBrowserInstalled(browser)
+	if !alreadyInstalled || installBrowserForce || installBrowserDeps {
+		if err := bootstrap.InstallBrowserWithProgress(runtimeNodePath(), browser, installBrowserDeps); err != nil {
+			resp := response.Error(
+				response.ErrPlaywrightNotInstalled,
+				"Failed to install browser: "+err.Error(),
+				"Check your internet connection and disk space, then retry",
+				map[string]interface{}{
+					"browser_type": browser,
+					"browsers_dir": bootstrap.GetBrowsersDir(),
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"browser_type":      browser,
+		"installed":         bootstrap.IsBrowserInstalled(browser),
+		"already_installed": alreadyInstalled && !installBrowserForce && !installBrowserDeps,
+		"browsers_dir":      bootstrap.GetBrowsersDir(),
+	}, startTime)
+	printResponse(resp)
+}
+
+func runRuntimeBundle(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	platform, err := bootstrap.GetPlatformInfo()
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_BUNDLE_FAILED",
+			"Failed to detect platform: "+err.Error(),
+			"Create the bundle on a supported OS and architecture",
+			nil,
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	outputPath := bundleOutputPath
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("webauto-runtime-%s-%s.tar.gz", platform.OS, platform.Arch)
+	}
+
+	// The bundle is built from a complete local runtime
+	if _, err := bootstrap.EnsureRuntime(); err != nil {
+		resp := response.Error(
+			"RUNTIME_BUNDLE_FAILED",
+			"Failed to set up the runtime to bundle: "+err.Error(),
+			"Run the bundle command on a machine with internet access",
+			map[string]interface{}{
+				"output_path": outputPath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	manifest, err := bootstrap.CreateBundle(outputPath, bundleBrowsers)
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_BUNDLE_FAILED",
+			"Failed to create runtime bundle: "+err.Error(),
+			"Check that the listed browsers are installed and the output path is writable",
+			map[string]interface{}{
+				"output_path": outputPath,
+				"browsers":    bundleBrowsers,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	var size int64
+	if info, err := os.Stat(outputPath); err == nil {
+		size = info.Size()
+	}
+	sum, _ := bootstrap.FileSHA256(outputPath)
+
+	resp := response.Success(map[string]interface{}{
+		"output_path":        outputPath,
+		"size_bytes":         size,
+		"sha256":             sum,
+		"os":                 manifest.OS,
+		"arch":               manifest.Arch,
+		"node_version":       manifest.NodeVersion,
+		"playwright_version": manifest.PlaywrightVersion,
+		"browsers":           manifest.Browsers,
+	}, startTime)
+	printResponse(resp)
+}
+
+func runRuntimeInstall(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	bundlePath := installFrom
+	if bundlePath == "" {
+		bundlePath = os.Getenv(bootstrap.RuntimeBundleEnv)
+	}
+	expectedSHA256 := installSHA256
+	if expectedSHA256 == "" {
+		expectedSHA256 = os.Getenv(bootstrap.RuntimeBundleSHA256Env)
+	}
+
+	if bundlePath == "" {
+		nodePath, err := bootstrap.EnsureRuntime()
+		if err != nil {
+			resp := response.Error(
+				"RUNTIME_INSTALL_FAILED",
+				"Failed to install runtime: "+err.Error(),
+				"Check your internet connection, or install from a bundle with --from",
+				nil,
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+
+		resp := response.Success(map[string]interface{}{
+			"source":             "download",
+			"node_path":          nodePath,
+			"playwright_version": bootstrap.InstalledPlaywrightVersion(),
+		}, startTime)
+		printResponse(resp)
+		return
+	}
+
+	manifest, err := bootstrap.InstallBundle(bundlePath, expectedSHA256)
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_INSTALL_FAILED",
+			"Failed to install runtime bundle: "+err.Error(),
+			"Create the bundle with \"webauto runtime bundle\" on a machine with the same OS and architecture",
+			map[string]interface{}{
+				"bundle_path": bundlePath,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"source":             "bundle",
+		"bundle_path":        bundlePath,
+		"bundle_verified":    expectedSHA256 != "",
+		"node_path":          bootstrap.GetInstalledNodePath(),
+		"node_version":       manifest.NodeVersion,
+		"playwright_version": manifest.PlaywrightVersion,
+		"browsers":           manifest.Browsers,
+	}, startTime)
+	printResponse(resp)
+}
+
+// runtimeNodePath returns the node executable used by the runtime commands,
+// which run without the first-run bootstrap
+func runtimeNodePath() string {
+	if path := os.Getenv("PLAYWRIGHT_NODE_PATH"); path != "" {
+		return path
+	}
+	if path := bootstrap.GetInstalledNodePath(); path != "" {
+		return path
+	}
+	return config.Load().PlaywrightNodePath
+}
+
+// GetRuntimeCommand returns the runtime command for registration
+func GetRuntimeCommand() *cobra.Command {
+	return runtimeCmd
+}
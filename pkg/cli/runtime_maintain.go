@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneDryRun     bool
+	uninstallDryRun bool
+	runtimeForce    bool
+)
+
+var runtimeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report installed runtime versions, disk usage and problems",
+	Run:   runRuntimeStatus,
+}
+
+var runtimeRepairCmd = &cobra.Command{
+	Use:   "repair [node|playwright|chromium|firefox|webkit]...",
+	Short: "Reinstall broken runtime components",
+	Long: `Reinstall runtime components. With no arguments every component that
+"runtime status" reports as missing, broken or incomplete is reinstalled.`,
+	Run: runRuntimeRepair,
+}
+
+var runtimeUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade Node.js, Playwright and browsers to the pinned versions",
+	Long: `Upgrade Node.js and Playwright to the versions pinned in this webauto build,
+reinstall the previously installed browsers for the new Playwright and prune
+the builds the old versions left behind.`,
+	Run: runRuntimeUpgrade,
+}
+
+var runtimeUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove Node.js, Playwright and all browser builds",
+	Long: `Remove Node.js, Playwright and all browser builds from the cache directory.
+Sessions, selectors and other user data are kept. The next command installs
+the runtime again.`,
+	Run: runRuntimeUninstall,
+}
+
+var runtimePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale browser builds and leftover downloads",
+	Run:   runRuntimePrune,
+}
+
+func init() {
+	runtimePruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List what would be removed without deleting anything")
+	runtimeUninstallCmd.Flags().BoolVar(&uninstallDryRun, "dry-run", false, "List what would be removed without deleting anything")
+	for _, c := range []*cobra.Command{runtimeRepairCmd, runtimeUpgradeCmd, runtimeUninstallCmd} {
+		c.Flags().BoolVar(&runtimeForce, "force", false, "Proceed even while browser sessions are running")
+	}
+
+	runtimeCmd.AddCommand(runtimeStatusCmd)
+	runtimeCmd.AddCommand(runtimeRepairCmd)
+	runtimeCmd.AddCommand(runtimeUpgradeCmd)
+	runtimeCmd.AddCommand(runtimeUninstallCmd)
+	runtimeCmd.AddCommand(runtimePruneCmd)
+}
+
+func runRuntimeStatus(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	status, err := bootstrap.InspectRuntime()
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_STATUS_FAILED",
+			"Failed to inspect runtime: "+err.Error(),
+			"Check permissions of the webauto cache directory",
+			map[string]interface{}{
+				"cache_dir": bootstrap.GetCacheDir(),
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	printResponse(response.Success(status, startTime))
+}
+
+func runRuntimeRepair(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	components := args
+	if len(components) == 0 {
+		status, err := bootstrap.InspectRuntime()
+		if err != nil {
+			printResponse(runtimeRepairError(err, nil, startTime))
+			return
+		}
+		components = brokenComponents(status)
+	}
+
+	for _, component := range components {
+		if component != bootstrap.ComponentNode && component != bootstrap.ComponentPlaywright && !bootstrap.IsSupportedBrowser(component) {
+			resp := response.Error(
+				"INVALID_RUNTIME_COMPONENT",
+				fmt.Sprintf("Unknown runtime component: %s", component),
+				"Use one of: node, playwright, "+strings.Join(bootstrap.SupportedBrowsers, ", "),
+				map[string]interface{}{
+					"component": component,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+	}
+
+	if resp := liveSessionsError(startTime); resp != nil {
+		printResponse(resp)
+		return
+	}
+
+	out := bootstrap.Progress().Output(bootstrap.StageBrowserInstall)
+	defer out.Close()
+
+	// Node.js first, Playwright next: later components are installed with them
+	repaired := []string{}
+	for _, component := range orderComponents(components) {
+		var err error
+		switch component {
+		case bootstrap.ComponentNode:
+			err = bootstrap.RepairNode()
+		case bootstrap.ComponentPlaywright:
+			err = bootstrap.RepairPlaywright(runtimeNodePath())
+		default:
+			err = bootstrap.RepairBrowser(runtimeNodePath(), component, out)
+		}
+		if err != nil {
+			printResponse(runtimeRepairError(fmt.Errorf("%s: %w", component, err), repaired, startTime))
+			return
+		}
+		repaired = append(repaired, component)
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"repaired": repaired,
+	}, startTime)
+	printResponse(resp)
+}
+
+// brokenComponents lists the components runtime status reports as broken
+func brokenComponents(status *bootstrap.RuntimeStatus) []string {
+	components := []string{}
+	if !status.Node.OK {
+		components = append(components, bootstrap.ComponentNode)
+	}
+	if !status.Playwright.OK {
+		components = append(components, bootstrap.ComponentPlaywright)
+	}
+
+	broken := map[string]bool{}
+	if !bootstrap.IsBrowserInstalled("chromium") {
+		broken["chromium"] = true
+	}
+	for _, build := range status.Browsers {
+		if !build.Complete {
+			broken[bootstrap.BrowserTypeOf(build.Name)] = true
+		}
+	}
+	for _, browserType := range bootstrap.SupportedBrowsers {
+		if broken[browserType] {
+			components = append(components, browserType)
+		}
+	}
+	return components
+}
+
+// orderComponents sorts components so Node.js comes before Playwright and
+// Playwright before the browsers, dropping duplicates
+func orderComponents(components []string) []string {
+	requested := map[string]bool{}
+	for _, component := range components {
+		requested[component] = true
+	}
+
+	ordered := []string{}
+	for _, component := range append([]string{bootstrap.ComponentNode, bootstrap.ComponentPlaywright}, bootstrap.SupportedBrowsers...) {
+		if requested[component] {
+			ordered = append(ordered, component)
+		}
+	}
+	return ordered
+}
+
+// runtimeRepairError reports a failed repair with what was already repaired
+func runtimeRepairError(err error, repaired []string, startTime time.Time) *response.StandardResponse {
+	return response.Error(
+		"RUNTIME_REPAIR_FAILED",
+		"Failed to repair runtime: "+err.Error(),
+		"Check your internet connection and disk space, then retry",
+		map[string]interface{}{
+			"repaired": repaired,
+		},
+		startTime,
+	)
+}
+
+func runRuntimeUpgrade(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	if resp := liveSessionsError(startTime); resp != nil {
+		printResponse(resp)
+		return
+	}
+
+	out := bootstrap.Progress().Output(bootstrap.StageBrowserInstall)
+	result, err := bootstrap.UpgradeRuntime(out)
+	out.Close()
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_UPGRADE_FAILED",
+			"Failed to upgrade runtime: "+err.Error(),
+			"Check your internet connection, then retry or run: webauto runtime repair",
+			result,
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	printResponse(response.Success(result, startTime))
+}
+
+func runRuntimeUninstall(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	if !uninstallDryRun {
+		if resp := liveSessionsError(startTime); resp != nil {
+			printResponse(resp)
+			return
+		}
+	}
+
+	removed, err := bootstrap.UninstallRuntime(uninstallDryRun)
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_UNINSTALL_FAILED",
+			"Failed to uninstall runtime: "+err.Error(),
+			"Close running sessions (see session-list) and retry",
+			map[string]interface{}{
+				"removed": removed,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	printResponse(response.Success(removedData(removed, uninstallDryRun), startTime))
+}
+
+func runRuntimePrune(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	removed, err := bootstrap.PruneRuntime(pruneDryRun)
+	if err != nil {
+		resp := response.Error(
+			"RUNTIME_PRUNE_FAILED",
+			"Failed to prune runtime: "+err.Error(),
+			"Check permissions of the webauto cache directory",
+			map[string]interface{}{
+				"removed": removed,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	printResponse(response.Success(removedData(removed, pruneDryRun), startTime))
+}
+
+// liveSessionsError refuses to change the runtime under running browser
+// sessions unless --force is given. It returns nil when it is safe to go on.
+func liveSessionsError(startTime time.Time) *response.StandardResponse {
+	if runtimeForce {
+		return nil
+	}
+
+	live := playwright.GetGlobalSessionManager().LiveSessions()
+	if len(live) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(live))
+	for _, session := range live {
+		ids = append(ids, session.ID)
+	}
+	return response.Error(
+		"SESSIONS_RUNNING",
+		fmt.Sprintf("%d browser session(s) are using the runtime", len(live)),
+		"Close them with session-close (see session-list), or pass --force",
+		map[string]interface{}{
+			"session_ids": ids,
+		},
+		startTime,
+	)
+}
+
+// removedData builds the response for prune and uninstall
+func removedData(removed []bootstrap.RemovedItem, dryRun bool) map[string]interface{} {
+	var freed int64
+	for _, item := range removed {
+		freed += item.SizeBytes
+	}
+	return map[string]interface{}{
+		"dry_run":     dryRun,
+		"removed":     removed,
+		"freed_bytes": freed,
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// needsRuntime reports whether cmd needs the Node.js runtime set up first.
+// The runtime commands manage the runtime themselves (e.g. installing from an
+// offline bundle) and doctor diagnoses a broken one, so neither may trigger
+// the online first-run setup; neither may help and shell completion.
+func needsRuntime(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == runtimeCmd || c == doctorCmd {
+			return false
+		}
+	}
+
+	switch cmd.Name() {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+	return true
+}
+
+// setupRuntime bootstraps the Node.js runtime on first run and points the
+// config at it. A failure is reported as a JSON response on stdout.
+func setupRuntime(startTime time.Time) error {
+	nodePath, err := bootstrap.EnsureRuntime()
+	if err != nil {
+		if bootstrap.Progress().Mode() == bootstrap.ProgressText {
+			fmt.Fprintf(os.Stderr, "❌ Failed to setup runtime: %v\n", err)
+		}
+		// Scripts still get a single JSON response on stdout
+		printResponse(response.Error(
+			"RUNTIME_SETUP_FAILED",
+			"Failed to setup runtime: "+err.Error(),
+			"Check network access (HTTPS_PROXY, "+bootstrap.NodeMirrorEnv+", "+bootstrap.CABundleEnv+") or install offline with "+bootstrap.RuntimeBundleEnv,
+			nil,
+			startTime,
+		))
+		return err
+	}
+
+	// Override config with bootstrapped Node.js path
+	os.Setenv("PLAYWRIGHT_NODE_PATH", nodePath)
+	return nil
+}
+
+// silenced returns err after turning off cobra's own error and usage output,
+// for errors already reported as a JSON response
+func silenced(cmd *cobra.Command, err error) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return err
+}
@@ -40,7 +40,7 @@ func runSessionClose(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -56,7 +56,7 @@ func runSessionClose(cmd *cobra.Command, args []string) {
 			},
 			startTime,
 		)
-		resp.Print()
+		printResponse(resp)
 		return
 	}
 
@@ -65,7 +65,7 @@ func runSessionClose(cmd *cobra.Command, args []string) {
 		"session_id": sessionID,
 		"closed_at":  time.Now().Format(time.RFC3339),
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 
 	_ = ctx // Avoid unused variable warning
 }
@@ -32,7 +32,7 @@ func runSessionList(cmd *cobra.Command, args []string) {
 	// Build session list for response
 	sessionList := make([]map[string]interface{}, 0, len(sessions))
 	for _, session := range sessions {
-		sessionList = append(sessionList, map[string]interface{}{
+		entry := map[string]interface{}{
 			"session_id":   session.ID,
 			"browser_type": session.BrowserType,
 			"headless":     session.Headless,
@@ -40,7 +40,17 @@ func runSessionList(cmd *cobra.Command, args []string) {
 			"port":         session.Port,
 			"created_at":   session.CreatedAt.Format(time.RFC3339),
 			"last_used_at": session.LastUsedAt.Format(time.RFC3339),
-		})
+		}
+		if session.SharedBrowser {
+			entry["shared_browser"] = true
+			entry["runner_id"] = session.RunnerID
+			entry["context_id"] = session.ContextID
+		}
+		if session.Proxy != nil {
+			// Proxy info never carries credentials
+			entry["proxy"] = session.Proxy
+		}
+		sessionList = append(sessionList, entry)
 	}
 
 	// Success response
@@ -48,5 +58,5 @@ func runSessionList(cmd *cobra.Command, args []string) {
 		"session_count": len(sessions),
 		"sessions":      sessionList,
 	}, startTime)
-	resp.Print()
+	printResponse(resp)
 }
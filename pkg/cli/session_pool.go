@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	poolBrowserType string
+	poolHeadless    bool
+)
+
+var sessionPoolCmd = &cobra.Command{
+	Use:   "session-pool",
+	Short: "Manage the warm pool of pre-launched browser runners",
+	Long: `Manage the warm session pool. With SESSION_POOL_SIZE > 0, browser-launch hands
+out an idle pre-launched runner (with a fresh browser context) instead of
+starting Node.js and a browser, then refills the pool in the background.
+Idle runners older than SESSION_POOL_IDLE_SECONDS are discarded.`,
+}
+
+var sessionPoolStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List idle runners in the pool",
+	Run:   runSessionPoolStatus,
+}
+
+var sessionPoolFillCmd = &cobra.Command{
+	Use:   "fill",
+	Short: "Launch runners until the pool is full",
+	Run:   runSessionPoolFill,
+}
+
+var sessionPoolDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Stop all idle runners in the pool",
+	Run:   runSessionPoolDrain,
+}
+
+func init() {
+	sessionPoolFillCmd.Flags().StringVar(&poolBrowserType, "browser-type", "chromium", "Browser type (chromium|firefox|webkit)")
+	sessionPoolFillCmd.Flags().BoolVar(&poolHeadless, "headless", true, "Headless mode")
+
+	sessionPoolCmd.AddCommand(sessionPoolStatusCmd)
+	sessionPoolCmd.AddCommand(sessionPoolFillCmd)
+	sessionPoolCmd.AddCommand(sessionPoolDrainCmd)
+}
+
+func runSessionPoolStatus(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	entries := sessionMgr.PoolEntries()
+	runners := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		runners = append(runners, map[string]interface{}{
+			"id":           entry.ID,
+			"browser_type": entry.BrowserType,
+			"headless":     entry.Headless,
+			"pid":          entry.PID,
+			"port":         entry.Port,
+			"created_at":   entry.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"enabled":      sessionMgr.PoolEnabled(),
+		"idle_count":   len(entries),
+		"idle_runners": runners,
+	}, startTime)
+	printResponse(resp)
+}
+
+func runSessionPoolFill(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	if !sessionMgr.PoolEnabled() {
+		resp := response.Error(
+			"SESSION_POOL_DISABLED",
+			"The warm session pool is disabled",
+			"Set SESSION_POOL_SIZE to the number of idle runners to keep",
+			map[string]interface{}{
+				"browser_type": poolBrowserType,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	launched, err := sessionMgr.FillPool(ctx, poolBrowserType, poolHeadless)
+	if err != nil {
+		resp := response.Error(
+			response.ErrBrowserLaunchFailed,
+			"Failed to fill session pool: "+err.Error(),
+			"Check Playwright installation and browser binaries",
+			map[string]interface{}{
+				"browser_type": poolBrowserType,
+				"headless":     poolHeadless,
+				"launched":     launched,
+			},
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	resp := response.Success(map[string]interface{}{
+		"browser_type": poolBrowserType,
+		"headless":     poolHeadless,
+		"launched":     launched,
+	}, startTime)
+	printResponse(resp)
+}
+
+func runSessionPoolDrain(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	resp := response.Success(map[string]interface{}{
+		"drained": sessionMgr.DrainPool(),
+	}, startTime)
+	printResponse(resp)
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+// Storage command error codes
+const (
+	errStorageOperationFailed = "STORAGE_OPERATION_FAILED"
+	errInvalidStorageRequest  = "INVALID_STORAGE_REQUEST"
+)
+
+// storageRecovery is the recovery hint of a storage command the runner rejected
+const storageRecovery = "Navigate the session to a web page or pass --origin; sessionStorage needs the page to be on that origin"
+
+var (
+	storageType       string
+	storageKey        string
+	storageValue      string
+	storageOrigin     string
+	storageOutputPath string
+)
+
+const storageOriginHelp = `--origin (e.g. https://www.wehago.com) selects another origin than the
+current page's. localStorage of any origin is reached through a temporary
+page that does not load the site; sessionStorage belongs to the session's
+tab and is only reachable for the current page's origin.`
+
+var storageGetCmd = &cobra.Command{
+	Use:   "storage-get",
+	Short: "Read localStorage or sessionStorage",
+	Long: `Read one key (--key) or every item of the localStorage (--type local,
+default) or sessionStorage (--type session) of an origin.
+
+` + storageOriginHelp,
+	Run: runStorageGet,
+}
+
+var storageSetCmd = &cobra.Command{
+	Use:   "storage-set",
+	Short: "Write a localStorage or sessionStorage item",
+	Long: `Set an item of the localStorage (--type local, default) or sessionStorage
+(--type session) of an origin. The value is stored as given; pass JSON text to
+seed structured app state.
+
+` + storageOriginHelp,
+	Run: runStorageSet,
+}
+
+var storageClearCmd = &cobra.Command{
+	Use:   "storage-clear",
+	Short: "Clear localStorage or sessionStorage",
+	Long: `Remove one item (--key) or every item of the localStorage (--type local,
+default) or sessionStorage (--type session) of an origin.
+
+` + storageOriginHelp,
+	Run: runStorageClear,
+}
+
+var storageDumpCmd = &cobra.Command{
+	Use:   "storage-dump",
+	Short: "Dump web storage of every origin",
+	Long: `Dump the localStorage of every origin the session has stored data for and
+the sessionStorage of the current page, grouped by origin. --origin limits the
+dump to one origin; --output-path writes it to a JSON file.`,
+	Run: runStorageDump,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{storageGetCmd, storageSetCmd, storageClearCmd, storageDumpCmd} {
+		cmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+		cmd.Flags().StringVar(&storageOrigin, "origin", "", "Origin to access (default: the current page's origin)")
+		cmd.MarkFlagRequired("session-id")
+	}
+
+	for _, cmd := range []*cobra.Command{storageGetCmd, storageSetCmd, storageClearCmd} {
+		cmd.Flags().StringVar(&storageType, "type", "local", "Storage type (local|session)")
+	}
+
+	storageGetCmd.Flags().StringVar(&storageKey, "key", "", "Item key (default: all items)")
+	storageSetCmd.Flags().StringVar(&storageKey, "key", "", "Item key (required)")
+	storageSetCmd.Flags().StringVar(&storageValue, "value", "", "Item value (required)")
+	storageClearCmd.Flags().StringVar(&storageKey, "key", "", "Remove only this item")
+	storageDumpCmd.Flags().StringVar(&storageOutputPath, "output-path", "", "Write the dump to a JSON file")
+
+	storageSetCmd.MarkFlagRequired("key")
+	storageSetCmd.MarkFlagRequired("value")
+}
+
+// storageDetails describes the storage request in error details
+func storageDetails() map[string]interface{} {
+	details := map[string]interface{}{
+		"session_id":   sessionID,
+		"storage_type": storageType,
+	}
+	if storageKey != "" {
+		details["key"] = storageKey
+	}
+	if storageOrigin != "" {
+		details["origin"] = storageOrigin
+	}
+	return details
+}
+
+// parseStorageType validates --type
+func parseStorageType() (string, error) {
+	switch strings.ToLower(storageType) {
+	case "local", "localstorage":
+		return "local", nil
+	case "session", "sessionstorage":
+		return "session", nil
+	default:
+		return "", fmt.Errorf("unknown storage type %q (use local or session)", storageType)
+	}
+}
+
+// runStorageCommand sends storage-get, storage-set or storage-clear and
+// prints the runner's data
+func runStorageCommand(name, action string, extra map[string]interface{}) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	kind, err := parseStorageType()
+	if err != nil {
+		resp := response.Error(
+			errInvalidStorageRequest,
+			err.Error(),
+			"Use --type local or --type session",
+			storageDetails(),
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	storageCmd := map[string]interface{}{
+		"command":     name,
+		"storageType": kind,
+	}
+	if storageOrigin != "" {
+		storageCmd["origin"] = storageOrigin
+	}
+	if storageKey != "" {
+		storageCmd["key"] = storageKey
+	}
+	for key, value := range extra {
+		storageCmd[key] = value
+	}
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, storageCmd)
+	if err != nil {
+		printResponse(commandFailure(err, sessionErr, action, errStorageOperationFailed, storageRecovery, storageDetails(), startTime))
+		return
+	}
+
+	data := map[string]interface{}{"session_id": sessionID}
+	for key, value := range result.Data {
+		data[key] = value
+	}
+	printResponse(response.Success(data, startTime))
+}
+
+func runStorageGet(cmd *cobra.Command, args []string) {
+	runStorageCommand("storage-get", "read storage", nil)
+}
+
+func runStorageSet(cmd *cobra.Command, args []string) {
+	runStorageCommand("storage-set", "write storage", map[string]interface{}{"value": storageValue})
+}
+
+func runStorageClear(cmd *cobra.Command, args []string) {
+	runStorageCommand("storage-clear", "clear storage", nil)
+}
+
+func runStorageDump(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	dumpCmd := map[string]interface{}{"command": "storage-dump"}
+	if storageOrigin != "" {
+		dumpCmd["origin"] = storageOrigin
+	}
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, dumpCmd)
+	if err != nil {
+		details := map[string]interface{}{"session_id": sessionID}
+		if storageOrigin != "" {
+			details["origin"] = storageOrigin
+		}
+		printResponse(commandFailure(err, sessionErr, "dump storage", errStorageOperationFailed, storageRecovery, details, startTime))
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"session_id": sessionID,
+		"url":        result.Data["url"],
+	}
+
+	dump := map[string]interface{}{
+		"local_storage":   result.Data["local_storage"],
+		"session_storage": result.Data["session_storage"],
+	}
+	if storageOutputPath != "" {
+		if err := writeJSONFile(storageOutputPath, dump); err != nil {
+			resp := response.Error(
+				errStorageOperationFailed,
+				"Failed to write storage dump: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"output_path": storageOutputPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["output_path"] = storageOutputPath
+	} else {
+		for key, value := range dump {
+			responseData[key] = value
+		}
+	}
+
+	printResponse(response.Success(responseData, startTime))
+}
+
+// writeJSONFile writes an indented JSON file readable by the owner only;
+// storage dumps often hold login tokens
+func writeJSONFile(path string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
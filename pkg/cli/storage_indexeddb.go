@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+	"github.com/oa-plugins/webauto/pkg/response"
+	"github.com/spf13/cobra"
+)
+
+var (
+	idbDatabase   string
+	idbStore      string
+	idbKeyLower   string
+	idbKeyUpper   string
+	idbLimit      int
+	idbOutputPath string
+)
+
+var indexedDBDumpCmd = &cobra.Command{
+	Use:   "indexeddb-dump",
+	Short: "Dump IndexedDB databases to JSON (read-only)",
+	Long: `Dump the IndexedDB databases of an origin: every object store with its key
+path, indexes, record count and records. Nothing is modified; a database
+that does not exist is reported instead of being created.
+
+--key-lower and --key-upper bound the primary keys (inclusive). A bound is
+parsed as JSON when possible (42, "42", ["a", 1]) and used as a string
+otherwise. --limit caps the records per store; truncated tells whether more
+records matched. Binary values (Blob, ArrayBuffer) are described, not copied.
+
+` + storageOriginHelp,
+	Run: runIndexedDBDump,
+}
+
+func init() {
+	indexedDBDumpCmd.Flags().StringVar(&sessionID, "session-id", "", "Session ID (required)")
+	indexedDBDumpCmd.Flags().StringVar(&storageOrigin, "origin", "", "Origin to access (default: the current page's origin)")
+	indexedDBDumpCmd.Flags().StringVar(&idbDatabase, "database", "", "Only this database")
+	indexedDBDumpCmd.Flags().StringVar(&idbStore, "store", "", "Only this object store")
+	indexedDBDumpCmd.Flags().StringVar(&idbKeyLower, "key-lower", "", "Lowest primary key to include")
+	indexedDBDumpCmd.Flags().StringVar(&idbKeyUpper, "key-upper", "", "Highest primary key to include")
+	indexedDBDumpCmd.Flags().IntVar(&idbLimit, "limit", 100, "Maximum records per object store")
+	indexedDBDumpCmd.Flags().StringVar(&idbOutputPath, "output-path", "", "Write the dump to a JSON file")
+
+	indexedDBDumpCmd.MarkFlagRequired("session-id")
+}
+
+// parseIDBKey reads a key range bound: JSON when it parses, a string otherwise
+func parseIDBKey(raw string) interface{} {
+	var key interface{}
+	if err := json.Unmarshal([]byte(raw), &key); err == nil {
+		switch key.(type) {
+		case float64, string, []interface{}:
+			return key
+		}
+	}
+	return raw
+}
+
+func runIndexedDBDump(cmd *cobra.Command, args []string) {
+	startTime := time.Now()
+	ctx := context.Background()
+
+	details := map[string]interface{}{"session_id": sessionID}
+	if storageOrigin != "" {
+		details["origin"] = storageOrigin
+	}
+	if idbDatabase != "" {
+		details["database"] = idbDatabase
+	}
+	if idbStore != "" {
+		details["store"] = idbStore
+	}
+
+	if idbLimit < 0 || (idbStore != "" && idbDatabase == "") {
+		resp := response.Error(
+			errInvalidStorageRequest,
+			"Invalid IndexedDB dump request",
+			"--limit must not be negative and --store requires --database",
+			details,
+			startTime,
+		)
+		printResponse(resp)
+		return
+	}
+
+	// Get global session manager (singleton pattern)
+	sessionMgr := playwright.GetGlobalSessionManager()
+
+	dumpCmd := map[string]interface{}{
+		"command": "indexeddb-dump",
+		"limit":   idbLimit,
+	}
+	if storageOrigin != "" {
+		dumpCmd["origin"] = storageOrigin
+	}
+	if idbDatabase != "" {
+		dumpCmd["database"] = idbDatabase
+	}
+	if idbStore != "" {
+		dumpCmd["store"] = idbStore
+	}
+	if idbKeyLower != "" {
+		dumpCmd["lower"] = parseIDBKey(idbKeyLower)
+	}
+	if idbKeyUpper != "" {
+		dumpCmd["upper"] = parseIDBKey(idbKeyUpper)
+	}
+
+	result, sessionErr, err := sendCheckedCommand(ctx, sessionMgr, dumpCmd)
+	if err != nil {
+		printResponse(commandFailure(err, sessionErr, "dump IndexedDB", errStorageOperationFailed, storageRecovery, details, startTime))
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"session_id":     sessionID,
+		"origin":         result.Data["origin"],
+		"database_count": result.Data["database_count"],
+	}
+
+	if idbOutputPath != "" {
+		dump := map[string]interface{}{
+			"origin":    result.Data["origin"],
+			"databases": result.Data["databases"],
+		}
+		if err := writeJSONFile(idbOutputPath, dump); err != nil {
+			resp := response.Error(
+				errStorageOperationFailed,
+				"Failed to write IndexedDB dump: "+err.Error(),
+				"Check file path and permissions",
+				map[string]interface{}{
+					"session_id":  sessionID,
+					"output_path": idbOutputPath,
+				},
+				startTime,
+			)
+			printResponse(resp)
+			return
+		}
+		responseData["output_path"] = idbOutputPath
+	} else {
+		responseData["databases"] = result.Data["databases"]
+	}
+
+	printResponse(response.Success(responseData, startTime))
+}
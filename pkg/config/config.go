@@ -20,10 +20,28 @@ type Config struct {
 	DefaultViewportWidth  int
 	DefaultViewportHeight int
 
+	// Install missing Firefox/WebKit binaries on first launch
+	BrowserAutoInstall bool
+
 	// Session
 	SessionMaxCount       int
 	SessionTimeoutSeconds int
 
+	// Warm session pool (idle runners per browser type/headless mode)
+	SessionPoolSize        int
+	SessionPoolIdleSeconds int
+
+	// Rate limiting
+	RateLimitConfigPath string
+	RateLimitPolicy     string
+
+	// Self-healing selectors
+	SelectorStorePath string
+
+	// Proxy pool used by sessions launched without --proxy (empty = none)
+	ProxyPoolPath  string
+	ProxyStatePath string
+
 	// Anti-Bot
 	EnableStealth        bool
 	EnableFingerprint    bool
@@ -44,9 +62,22 @@ func Load() *Config {
 		DefaultViewportWidth:  getEnvIntOrDefault("DEFAULT_VIEWPORT_WIDTH", 1920),
 		DefaultViewportHeight: getEnvIntOrDefault("DEFAULT_VIEWPORT_HEIGHT", 1080),
 
+		BrowserAutoInstall: getEnvBoolOrDefault("BROWSER_AUTO_INSTALL", true),
+
 		SessionMaxCount:       getEnvIntOrDefault("SESSION_MAX_COUNT", 10),
 		SessionTimeoutSeconds: getEnvIntOrDefault("SESSION_TIMEOUT_SECONDS", 3600),
 
+		SessionPoolSize:        getEnvIntOrDefault("SESSION_POOL_SIZE", 0),
+		SessionPoolIdleSeconds: getEnvIntOrDefault("SESSION_POOL_IDLE_SECONDS", 900),
+
+		RateLimitConfigPath: getEnvOrDefault("RATE_LIMIT_CONFIG_PATH", filepath.Join(getDefaultCachePath(), "ratelimit.json")),
+		RateLimitPolicy:     getEnvOrDefault("RATE_LIMIT_POLICY", ""),
+
+		SelectorStorePath: getEnvOrDefault("SELECTOR_STORE_PATH", filepath.Join(getDefaultCachePath(), "selectors.json")),
+
+		ProxyPoolPath:  getEnvOrDefault("PROXY_POOL_PATH", ""),
+		ProxyStatePath: getEnvOrDefault("PROXY_STATE_PATH", filepath.Join(getDefaultCachePath(), "proxy-state.json")),
+
 		EnableStealth:        getEnvBoolOrDefault("ENABLE_STEALTH", true),
 		EnableFingerprint:    getEnvBoolOrDefault("ENABLE_FINGERPRINT", true),
 		EnableBehaviorRandom: getEnvBoolOrDefault("ENABLE_BEHAVIOR_RANDOM", true),
@@ -0,0 +1,208 @@
+// Package cookies filters browser cookies and reads and writes them as JSON
+// or Netscape cookies.txt files.
+package cookies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Supported cookie file formats
+const (
+	// FormatJSON is a JSON array of cookies in Playwright's format. A
+	// Playwright storage state file ({"cookies": [...], "origins": [...]})
+	// is accepted on import.
+	FormatJSON = "json"
+
+	// FormatNetscape is the tab-separated cookies.txt format read by curl
+	// and wget
+	FormatNetscape = "netscape"
+)
+
+// Cookie is a browser cookie in Playwright's format. Expires is a Unix
+// timestamp in seconds, -1 for a session cookie.
+type Cookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	URL      string  `json:"url,omitempty"`
+	Domain   string  `json:"domain,omitempty"`
+	Path     string  `json:"path,omitempty"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite,omitempty"`
+}
+
+// Filter selects cookies by domain, path and name. Empty fields match
+// every cookie.
+type Filter struct {
+	// Domain matches the cookie's domain and its subdomains, with or
+	// without a leading dot (example.com matches .www.example.com)
+	Domain string
+
+	// Path matches the cookie's path exactly
+	Path string
+
+	// Name matches the cookie's name exactly
+	Name string
+}
+
+// IsEmpty reports whether the filter matches every cookie
+func (f Filter) IsEmpty() bool {
+	return f.Domain == "" && f.Path == "" && f.Name == ""
+}
+
+// Match reports whether a cookie passes the filter
+func (f Filter) Match(c Cookie) bool {
+	if f.Name != "" && c.Name != f.Name {
+		return false
+	}
+	if f.Path != "" && c.Path != f.Path {
+		return false
+	}
+	if f.Domain != "" {
+		want := strings.ToLower(strings.TrimPrefix(f.Domain, "."))
+		domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		if domain != want && !strings.HasSuffix(domain, "."+want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the cookies that pass the filter
+func (f Filter) Apply(cookies []Cookie) []Cookie {
+	matched := make([]Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		if f.Match(c) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// FromData converts the cookies array of a runner response
+func FromData(data interface{}) ([]Cookie, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var cookies []Cookie
+	if err := json.Unmarshal(raw, &cookies); err != nil {
+		return nil, fmt.Errorf("unexpected cookie data: %w", err)
+	}
+	return cookies, nil
+}
+
+// FormatFromPath infers the file format from a file extension: .txt is a
+// Netscape cookies.txt file, everything else JSON
+func FormatFromPath(path string) string {
+	if strings.ToLower(filepath.Ext(path)) == ".txt" {
+		return FormatNetscape
+	}
+	return FormatJSON
+}
+
+// ParseFormat validates a --format value; empty means infer from the path
+func ParseFormat(format, path string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "":
+		return FormatFromPath(path), nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatNetscape, "cookies.txt", "txt":
+		return FormatNetscape, nil
+	default:
+		return "", fmt.Errorf("unsupported cookie format %q (use %s or %s)", format, FormatJSON, FormatNetscape)
+	}
+}
+
+// detectFormat tells JSON from cookies.txt content by its first character
+func detectFormat(data []byte) string {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		return FormatJSON
+	}
+	return FormatNetscape
+}
+
+// ReadFile reads cookies from a file. An empty format is detected from the
+// content.
+func ReadFile(path, format string) ([]Cookie, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read cookie file: %w", err)
+	}
+	if format == "" {
+		format = detectFormat(data)
+	}
+
+	var cookies []Cookie
+	switch format {
+	case FormatNetscape:
+		cookies, err = ParseNetscape(data)
+	default:
+		cookies, err = parseJSON(data)
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	return cookies, format, nil
+}
+
+// WriteFile writes cookies to a file in the given format. The file is only
+// readable by the owner because cookies often carry session credentials.
+func WriteFile(path, format string, cookies []Cookie) error {
+	var data []byte
+	switch format {
+	case FormatNetscape:
+		data = FormatNetscapeFile(cookies)
+	default:
+		var err error
+		if data, err = json.MarshalIndent(cookies, "", "  "); err != nil {
+			return fmt.Errorf("failed to marshal cookies: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cookie file: %w", err)
+	}
+	return nil
+}
+
+// parseJSON reads a cookie array or a Playwright storage state file
+func parseJSON(data []byte) ([]Cookie, error) {
+	var cookies []Cookie
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		var state struct {
+			Cookies []Cookie `json:"cookies"`
+		}
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("failed to parse cookie JSON: %w", err)
+		}
+		cookies = state.Cookies
+	} else if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("failed to parse cookie JSON: %w", err)
+	}
+
+	for i, c := range cookies {
+		if c.Name == "" {
+			return nil, fmt.Errorf("cookie %d has no name", i+1)
+		}
+		if c.URL == "" && c.Domain == "" {
+			return nil, fmt.Errorf("cookie %q needs a domain or url", c.Name)
+		}
+		if c.URL == "" && c.Path == "" {
+			cookies[i].Path = "/"
+		}
+		// Playwright uses -1 for session cookies; a missing field decodes as 0
+		if c.Expires == 0 {
+			cookies[i].Expires = -1
+		}
+	}
+	return cookies, nil
+}
@@ -0,0 +1,153 @@
+package cookies
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	cookie := Cookie{Name: "sid", Domain: ".www.Example.com", Path: "/app"}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty filter", Filter{}, true},
+		{"same domain", Filter{Domain: "www.example.com"}, true},
+		{"parent domain", Filter{Domain: "example.com"}, true},
+		{"leading dot", Filter{Domain: ".example.com"}, true},
+		{"case insensitive", Filter{Domain: "EXAMPLE.COM"}, true},
+		{"subdomain does not match parent cookie", Filter{Domain: "api.www.example.com"}, false},
+		{"suffix is not a subdomain", Filter{Domain: "ample.com"}, false},
+		{"other domain", Filter{Domain: "example.org"}, false},
+		{"exact path", Filter{Path: "/app"}, true},
+		{"path prefix does not match", Filter{Path: "/"}, false},
+		{"name", Filter{Name: "sid"}, true},
+		{"name is case sensitive", Filter{Name: "SID"}, false},
+		{"all fields", Filter{Domain: "example.com", Path: "/app", Name: "sid"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(cookie); got != tt.want {
+				t.Errorf("%+v.Match() = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	all := []Cookie{
+		{Name: "a", Domain: "example.com", Path: "/"},
+		{Name: "b", Domain: ".shop.example.com", Path: "/"},
+		{Name: "c", Domain: "example.org", Path: "/"},
+	}
+	got := Filter{Domain: "example.com"}.Apply(all)
+	if len(got) != 2 || got[0].Name != "a" || got[1].Name != "b" {
+		t.Errorf("Apply() = %+v, want cookies a and b", got)
+	}
+	if !(Filter{}).IsEmpty() || (Filter{Name: "a"}).IsEmpty() {
+		t.Errorf("IsEmpty() is wrong")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"", "cookies.json", FormatJSON, false},
+		{"", "cookies.TXT", FormatNetscape, false},
+		{"", "cookies", FormatJSON, false},
+		{"json", "cookies.txt", FormatJSON, false},
+		{"Netscape", "out", FormatNetscape, false},
+		{"cookies.txt", "out", FormatNetscape, false},
+		{"yaml", "out", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.format, tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q, %q) = %q, %v; want %q, error %v", tt.format, tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Cookie
+		wantErr bool
+	}{
+		{
+			name: "cookie array with defaults",
+			data: `[{"name": "a", "value": "1", "domain": "example.com"}]`,
+			want: []Cookie{{Name: "a", Value: "1", Domain: "example.com", Path: "/", Expires: -1}},
+		},
+		{
+			name: "url cookie keeps no path",
+			data: `[{"name": "a", "url": "https://example.com", "expires": 1700000000}]`,
+			want: []Cookie{{Name: "a", URL: "https://example.com", Expires: 1700000000}},
+		},
+		{
+			name: "storage state",
+			data: `{"cookies": [{"name": "a", "domain": ".example.com", "path": "/x", "expires": -1, "httpOnly": true}], "origins": []}`,
+			want: []Cookie{{Name: "a", Domain: ".example.com", Path: "/x", Expires: -1, HTTPOnly: true}},
+		},
+		{name: "missing name", data: `[{"domain": "example.com"}]`, wantErr: true},
+		{name: "missing domain and url", data: `[{"name": "a"}]`, wantErr: true},
+		{name: "invalid json", data: `[{"name": }]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFileRoundTrip(t *testing.T) {
+	cookies := []Cookie{
+		{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1900000000, HTTPOnly: true, Secure: true},
+		{Name: "pref", Value: "", Domain: "www.example.com", Path: "/app", Expires: -1},
+	}
+
+	for _, format := range []string{FormatJSON, FormatNetscape} {
+		t.Run(format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cookies")
+			if err := WriteFile(path, format, cookies); err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm&0077 != 0 {
+				t.Errorf("cookie file mode = %v, want owner-only", perm)
+			}
+
+			got, detected, err := ReadFile(path, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if detected != format {
+				t.Errorf("detected format %q, want %q", detected, format)
+			}
+			if !reflect.DeepEqual(got, cookies) {
+				t.Errorf("round trip = %+v, want %+v", got, cookies)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package cookies
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// httpOnlyPrefix marks HttpOnly cookies in cookies.txt files (curl, the
+// "cookies.txt" browser extensions)
+const httpOnlyPrefix = "#HttpOnly_"
+
+const netscapeHeader = "# Netscape HTTP Cookie File\n# Exported by webauto\n\n"
+
+// ParseNetscape reads a Netscape cookies.txt file: one cookie per line with
+// the tab-separated fields domain, include subdomains, path, secure,
+// expires, name and value. Comment and blank lines are skipped.
+func ParseNetscape(data []byte) ([]Cookie, error) {
+	var cookies []Cookie
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if strings.HasPrefix(line, httpOnlyPrefix) {
+			httpOnly = true
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) == 6 {
+			// A cookie with an empty value may lose its trailing tab
+			fields = append(fields, "")
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("cookies.txt line %d: expected 7 tab-separated fields, got %d", lineNumber, len(fields))
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookies.txt line %d: invalid expiry %q", lineNumber, fields[4])
+		}
+		if expires <= 0 {
+			expires = -1
+		}
+
+		// A leading dot makes the cookie valid for subdomains; the include
+		// subdomains column says the same thing and wins when they disagree
+		domain := strings.TrimPrefix(fields[0], ".")
+		if strings.EqualFold(fields[1], "TRUE") {
+			domain = "." + domain
+		}
+
+		path := fields[2]
+		if path == "" {
+			path = "/"
+		}
+
+		cookies = append(cookies, Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Domain:   domain,
+			Path:     path,
+			Expires:  expires,
+			HTTPOnly: httpOnly,
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookies.txt: %w", err)
+	}
+	return cookies, nil
+}
+
+// FormatNetscapeFile writes cookies as a Netscape cookies.txt file.
+// SameSite has no column in the format and is dropped; session cookies get
+// an expiry of 0.
+func FormatNetscapeFile(cookies []Cookie) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(netscapeHeader)
+
+	for _, c := range cookies {
+		if c.HTTPOnly {
+			buf.WriteString(httpOnlyPrefix)
+		}
+
+		expires := int64(0)
+		if c.Expires > 0 {
+			expires = int64(c.Expires)
+		}
+
+		fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			c.Domain,
+			netscapeBool(strings.HasPrefix(c.Domain, ".")),
+			c.Path,
+			netscapeBool(c.Secure),
+			expires,
+			c.Name,
+			c.Value,
+		)
+	}
+	return buf.Bytes()
+}
+
+func netscapeBool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
@@ -0,0 +1,79 @@
+package cookies
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNetscape(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Cookie
+		wantErr bool
+	}{
+		{
+			name: "subdomain cookie",
+			data: ".example.com\tTRUE\t/\tTRUE\t1900000000\tsid\tabc\n",
+			want: []Cookie{{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1900000000, Secure: true}},
+		},
+		{
+			name: "host-only session cookie",
+			data: "www.example.com\tFALSE\t/app\tFALSE\t0\tpref\tdark\n",
+			want: []Cookie{{Name: "pref", Value: "dark", Domain: "www.example.com", Path: "/app", Expires: -1}},
+		},
+		{
+			name: "include subdomains column wins",
+			data: ".example.com\tFALSE\t/\tFALSE\t0\ta\t1\nexample.com\tTRUE\t/\tFALSE\t0\tb\t2\n",
+			want: []Cookie{
+				{Name: "a", Value: "1", Domain: "example.com", Path: "/", Expires: -1},
+				{Name: "b", Value: "2", Domain: ".example.com", Path: "/", Expires: -1},
+			},
+		},
+		{
+			name: "httponly prefix, comments and CRLF",
+			data: "# Netscape HTTP Cookie File\r\n\r\n#HttpOnly_.example.com\tTRUE\t/\tFALSE\t0\tsid\tx\r\n",
+			want: []Cookie{{Name: "sid", Value: "x", Domain: ".example.com", Path: "/", Expires: -1, HTTPOnly: true}},
+		},
+		{
+			name: "empty value without trailing tab",
+			data: "example.com\tFALSE\t/\tFALSE\t0\tempty\n",
+			want: []Cookie{{Name: "empty", Domain: "example.com", Path: "/", Expires: -1}},
+		},
+		{name: "too few fields", data: "example.com\tFALSE\t/\n", wantErr: true},
+		{name: "bad expiry", data: "example.com\tFALSE\t/\tFALSE\tsoon\ta\t1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNetscape([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNetscape() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNetscape() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNetscapeFile(t *testing.T) {
+	data := string(FormatNetscapeFile([]Cookie{
+		{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1900000000.5, HTTPOnly: true, Secure: true, SameSite: "Lax"},
+		{Name: "pref", Value: "dark", Domain: "www.example.com", Path: "/app", Expires: -1},
+	}))
+
+	want := []string{
+		"#HttpOnly_.example.com\tTRUE\t/\tTRUE\t1900000000\tsid\tabc",
+		"www.example.com\tFALSE\t/app\tFALSE\t0\tpref\tdark",
+	}
+	if !strings.HasPrefix(data, "# Netscape HTTP Cookie File\n") {
+		t.Errorf("missing cookies.txt header:\n%s", data)
+	}
+	for _, line := range want {
+		if !strings.Contains(data, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, data)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package crawl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Task is one URL waiting to be crawled
+type Task struct {
+	URL    string `json:"url"`
+	Depth  int    `json:"depth"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// Checkpoint is the resumable state of a crawl: every URL ever queued and the
+// tasks not finished yet (including those in flight when it was written)
+type Checkpoint struct {
+	Domains   []string  `json:"domains"`
+	Seen      []string  `json:"seen"`
+	Pending   []Task    `json:"pending"`
+	Pages     int       `json:"pages"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// loadCheckpoint reads a checkpoint; a missing file returns nil
+func loadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// save writes the checkpoint atomically
+func (c *Checkpoint) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
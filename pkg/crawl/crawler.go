@@ -0,0 +1,593 @@
+// Package crawl implements a concurrent crawler on top of a pool of browser
+// sessions. Every page visit goes through the SessionManager, so per-domain
+// rate limits and retry policies apply as they do to single commands.
+package crawl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/extract"
+	"github.com/oa-plugins/webauto/pkg/ipc"
+	"github.com/oa-plugins/webauto/pkg/playwright"
+)
+
+// Stop reasons
+const (
+	StopCompleted   = "completed"
+	StopMaxPages    = "max_pages"
+	StopInterrupted = "interrupted"
+)
+
+// Result line types written to the NDJSON output
+const (
+	ResultPage    = "page"
+	ResultFailure = "failure"
+	ResultSkipped = "skipped"
+)
+
+// The checkpoint is rewritten after this many finished pages, or when the
+// last one is older than checkpointInterval, and always at the end of a run
+const (
+	checkpointEvery    = 25
+	checkpointInterval = 10 * time.Second
+)
+
+// linkSchema collects absolute link targets; the href property is already
+// resolved against the document base URL
+var linkSchema = &extract.Schema{
+	Fields: map[string]*extract.Field{
+		"links": {Selector: "a[href]", Source: extract.SourceProperty, Property: "href", List: true},
+	},
+}
+
+// Options configures a crawl
+type Options struct {
+	Seeds       []string
+	Concurrency int
+	MaxDepth    int
+	MaxPages    int
+
+	// SameDomain restricts discovered links to the hosts of the seeds
+	SameDomain bool
+
+	// Robots enables robots.txt checks for UserAgent
+	Robots    bool
+	UserAgent string
+
+	// Schema is run on every page; nil records only page metadata
+	Schema *extract.Schema
+
+	BrowserType string
+	Headless    bool
+	Timeout     time.Duration
+	WaitUntil   string
+	Retry       playwright.RetryPolicy
+
+	OutputPath     string
+	CheckpointPath string
+	Resume         bool
+}
+
+// Result is one line of the NDJSON output
+type Result struct {
+	Type       string      `json:"type"`
+	URL        string      `json:"url"`
+	FinalURL   string      `json:"final_url,omitempty"`
+	Title      string      `json:"title,omitempty"`
+	Depth      int         `json:"depth"`
+	Parent     string      `json:"parent,omitempty"`
+	SessionID  string      `json:"session_id,omitempty"`
+	Records    interface{} `json:"records,omitempty"`
+	LinksFound int         `json:"links_found,omitempty"`
+	Code       string      `json:"code,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Attempts   int         `json:"attempts,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+
+	// LinkCode and LinkError report a page whose links could not be read;
+	// the page itself was crawled, but nothing was discovered from it
+	LinkCode  string `json:"link_code,omitempty"`
+	LinkError string `json:"link_error,omitempty"`
+}
+
+// Summary reports the outcome of a crawl
+type Summary struct {
+	Sessions   int    `json:"sessions"`
+	Pages      int    `json:"pages"`
+	Failures   int    `json:"failures"`
+	Skipped    int    `json:"skipped"`
+	Discovered int    `json:"discovered"`
+	Pending    int    `json:"pending"`
+	Resumed    bool   `json:"resumed"`
+	StopReason string `json:"stop_reason"`
+}
+
+// Crawler runs a crawl over a pool of sessions
+type Crawler struct {
+	opts    Options
+	sm      *playwright.SessionManager
+	robots  *robotsCache
+	domains map[string]bool
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	queue    []Task
+	inFlight map[string]Task
+	seen     map[string]bool
+	started  int
+	stopped  bool
+	summary  Summary
+	out      *os.File
+
+	// Checkpoints are snapshotted under mu and written under saveMu
+	unsaved     int
+	snapshotAt  time.Time
+	snapshotSeq uint64
+	saveMu      sync.Mutex
+	savedSeq    uint64
+}
+
+// New validates the options and creates a crawler
+func New(sm *playwright.SessionManager, opts Options) (*Crawler, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.OutputPath == "" {
+		return nil, fmt.Errorf("an output path is required")
+	}
+	if opts.CheckpointPath == "" {
+		opts.CheckpointPath = opts.OutputPath + ".checkpoint.json"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	c := &Crawler{
+		opts:     opts,
+		sm:       sm,
+		domains:  make(map[string]bool),
+		inFlight: make(map[string]Task),
+		seen:     make(map[string]bool),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	if opts.Robots {
+		c.robots = newRobotsCache(opts.UserAgent)
+	}
+
+	return c, nil
+}
+
+// Run crawls until the queue is exhausted, MaxPages is reached or ctx is
+// cancelled. The checkpoint is written periodically and when the run ends so
+// an interrupted crawl can be resumed with Options.Resume.
+func (c *Crawler) Run(ctx context.Context) (*Summary, error) {
+	if err := c.restore(); err != nil {
+		return nil, err
+	}
+	if len(c.queue) == 0 {
+		c.summary.StopReason = StopCompleted
+		return &c.summary, nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !c.summary.Resumed {
+		flags |= os.O_TRUNC
+	}
+	out, err := os.OpenFile(c.opts.OutputPath, flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer out.Close()
+	c.out = out
+
+	sessions, err := c.openSessions()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, id := range sessions {
+			_ = c.sm.Close(id)
+		}
+	}()
+	c.summary.Sessions = len(sessions)
+
+	// Wake idle workers when the crawl is interrupted
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.stopped = true
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, id := range sessions {
+		wg.Add(1)
+		go func(sessionID string) {
+			defer wg.Done()
+			c.work(ctx, sessionID)
+		}(id)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	switch {
+	case ctx.Err() != nil:
+		c.summary.StopReason = StopInterrupted
+	case len(c.queue) > 0:
+		c.summary.StopReason = StopMaxPages
+	default:
+		c.summary.StopReason = StopCompleted
+	}
+	c.summary.Pending = len(c.queue) + len(c.inFlight)
+	checkpoint, seq := c.snapshot()
+	c.mu.Unlock()
+
+	if err := c.saveCheckpoint(checkpoint, seq); err != nil {
+		return &c.summary, err
+	}
+	return &c.summary, nil
+}
+
+// restore loads the checkpoint when resuming, otherwise queues the seeds
+func (c *Crawler) restore() error {
+	if c.opts.Resume {
+		checkpoint, err := loadCheckpoint(c.opts.CheckpointPath)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			for _, domain := range checkpoint.Domains {
+				c.domains[domain] = true
+			}
+			for _, seen := range checkpoint.Seen {
+				c.seen[seen] = true
+			}
+			c.queue = append(c.queue, checkpoint.Pending...)
+			c.started = checkpoint.Pages
+			c.summary.Resumed = true
+			return nil
+		}
+	}
+
+	for _, seed := range c.opts.Seeds {
+		normalized, target, err := normalizeURL(seed)
+		if err != nil {
+			return fmt.Errorf("invalid seed URL %q: %w", seed, err)
+		}
+		c.domains[target.Host] = true
+		if !c.seen[normalized] {
+			c.seen[normalized] = true
+			c.queue = append(c.queue, Task{URL: normalized})
+		}
+	}
+
+	if len(c.queue) == 0 {
+		return fmt.Errorf("no seed URLs to crawl")
+	}
+	return nil
+}
+
+// openSessions launches up to Concurrency sessions within SessionMaxCount
+func (c *Crawler) openSessions() ([]string, error) {
+	size := c.opts.Concurrency
+	if available := c.sm.Available(); available < size {
+		size = available
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("no session slots available (SESSION_MAX_COUNT reached)")
+	}
+
+	sessions := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		// Sessions outlive an interrupt so they can be closed cleanly
+		session, err := c.sm.Create(context.Background(), c.opts.BrowserType, c.opts.Headless, "")
+		if err != nil {
+			if len(sessions) > 0 {
+				// Crawl with the sessions we have
+				fmt.Fprintf(os.Stderr, "Warning: crawling with %d of %d sessions: %v\n", len(sessions), size, err)
+				break
+			}
+			return nil, fmt.Errorf("failed to launch crawl session: %w", err)
+		}
+		sessions = append(sessions, session.ID)
+	}
+
+	return sessions, nil
+}
+
+func (c *Crawler) work(ctx context.Context, sessionID string) {
+	for {
+		task, ok := c.next()
+		if !ok {
+			return
+		}
+
+		result, links := c.visit(ctx, sessionID, task)
+		if checkpoint, seq := c.finish(ctx, task, result, links); checkpoint != nil {
+			if err := c.saveCheckpoint(checkpoint, seq); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save crawl checkpoint: %v\n", err)
+			}
+		}
+	}
+}
+
+// next blocks until a task is available or the crawl is over
+func (c *Crawler) next() (Task, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		if c.stopped {
+			return Task{}, false
+		}
+		if c.opts.MaxPages > 0 && c.started >= c.opts.MaxPages {
+			return Task{}, false
+		}
+		if len(c.queue) > 0 {
+			task := c.queue[0]
+			c.queue = c.queue[1:]
+			c.inFlight[task.URL] = task
+			c.started++
+			return task, true
+		}
+		if len(c.inFlight) == 0 {
+			return Task{}, false
+		}
+		c.cond.Wait()
+	}
+}
+
+// finish records a visit and queues newly discovered links. It returns a
+// checkpoint snapshot when one is due, for the caller to write after c.mu is
+// released.
+func (c *Crawler) finish(ctx context.Context, task Task, result Result, links []Task) (*Checkpoint, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.cond.Broadcast()
+
+	delete(c.inFlight, task.URL)
+
+	// Visits cut short by an interrupt stay pending for the next run
+	if ctx.Err() != nil {
+		c.queue = append([]Task{task}, c.queue...)
+		c.started--
+		return nil, 0
+	}
+
+	switch result.Type {
+	case ResultPage:
+		c.summary.Pages++
+	case ResultFailure:
+		c.summary.Failures++
+	case ResultSkipped:
+		c.summary.Skipped++
+		c.started--
+	}
+
+	for _, link := range links {
+		if c.seen[link.URL] {
+			continue
+		}
+		c.seen[link.URL] = true
+		c.queue = append(c.queue, link)
+		c.summary.Discovered++
+	}
+
+	if line, err := json.Marshal(result); err == nil {
+		if _, err := c.out.Write(append(line, '\n')); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write crawl result: %v\n", err)
+		}
+	}
+
+	c.unsaved++
+	if c.unsaved < checkpointEvery && time.Since(c.snapshotAt) < checkpointInterval {
+		return nil, 0
+	}
+	return c.snapshot()
+}
+
+// snapshot captures the crawl state with a sequence number; the caller
+// holds c.mu
+func (c *Crawler) snapshot() (*Checkpoint, uint64) {
+	c.unsaved = 0
+	c.snapshotAt = time.Now()
+	c.snapshotSeq++
+	return c.checkpoint(), c.snapshotSeq
+}
+
+// saveCheckpoint writes a snapshot unless a newer one is already on disk
+func (c *Crawler) saveCheckpoint(checkpoint *Checkpoint, seq uint64) error {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	if seq <= c.savedSeq {
+		return nil
+	}
+	if err := checkpoint.save(c.opts.CheckpointPath); err != nil {
+		return err
+	}
+	c.savedSeq = seq
+	return nil
+}
+
+// checkpoint builds the checkpoint of the crawl state; the caller holds c.mu
+func (c *Crawler) checkpoint() *Checkpoint {
+	checkpoint := &Checkpoint{
+		Domains:   make([]string, 0, len(c.domains)),
+		Seen:      make([]string, 0, len(c.seen)),
+		Pending:   make([]Task, 0, len(c.queue)+len(c.inFlight)),
+		Pages:     c.started - len(c.inFlight),
+		UpdatedAt: time.Now(),
+	}
+	for domain := range c.domains {
+		checkpoint.Domains = append(checkpoint.Domains, domain)
+	}
+	for seen := range c.seen {
+		checkpoint.Seen = append(checkpoint.Seen, seen)
+	}
+	for _, task := range c.inFlight {
+		checkpoint.Pending = append(checkpoint.Pending, task)
+	}
+	checkpoint.Pending = append(checkpoint.Pending, c.queue...)
+	return checkpoint
+}
+
+// visit loads one page, runs the extraction schema and collects links
+func (c *Crawler) visit(ctx context.Context, sessionID string, task Task) (Result, []Task) {
+	result := Result{
+		URL:       task.URL,
+		Depth:     task.Depth,
+		Parent:    task.Parent,
+		SessionID: sessionID,
+		Timestamp: time.Now(),
+	}
+
+	target, err := url.Parse(task.URL)
+	if err != nil {
+		result.Type = ResultFailure
+		result.Code = "INVALID_URL"
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	if c.robots != nil {
+		allowed, err := c.robots.Allowed(ctx, target)
+		if err != nil {
+			result.Type = ResultSkipped
+			result.Code = "ROBOTS_UNAVAILABLE"
+			result.Error = err.Error()
+			return result, nil
+		}
+		if !allowed {
+			result.Type = ResultSkipped
+			result.Code = "ROBOTS_DISALLOWED"
+			return result, nil
+		}
+	}
+
+	navigate := map[string]interface{}{
+		"command": "navigate",
+		"url":     task.URL,
+		"timeout": c.opts.Timeout.Milliseconds(),
+	}
+	if c.opts.WaitUntil != "" {
+		navigate["waitUntil"] = c.opts.WaitUntil
+	}
+	resp, ok := c.send(ctx, sessionID, navigate, &result)
+	if !ok {
+		return result, nil
+	}
+	result.FinalURL, _ = resp.Data["url"].(string)
+	result.Title, _ = resp.Data["title"].(string)
+
+	if c.opts.Schema != nil {
+		resp, ok := c.send(ctx, sessionID, map[string]interface{}{
+			"command": "extract",
+			"schema":  c.opts.Schema,
+			"timeout": c.opts.Timeout.Milliseconds(),
+		}, &result)
+		if !ok {
+			return result, nil
+		}
+		result.Records = resp.Data["records"]
+	}
+
+	result.Type = ResultPage
+	if task.Depth >= c.opts.MaxDepth {
+		return result, nil
+	}
+
+	// A failed link read keeps the page's own result
+	var linkResult Result
+	resp, ok = c.send(ctx, sessionID, map[string]interface{}{
+		"command": "extract",
+		"schema":  linkSchema,
+		"timeout": c.opts.Timeout.Milliseconds(),
+	}, &linkResult)
+	result.Attempts += linkResult.Attempts
+	if !ok {
+		result.LinkCode, result.LinkError = linkResult.Code, linkResult.Error
+		return result, nil
+	}
+
+	links := c.discover(resp.Data["records"], task)
+	result.LinksFound = len(links)
+	return result, links
+}
+
+// send runs one command with the retry policy; failures are recorded on result
+func (c *Crawler) send(ctx context.Context, sessionID string, command map[string]interface{}, result *Result) (*ipc.NodeResponse, bool) {
+	// Bound each command so a hung page cannot stall its worker forever
+	commandCtx, cancel := context.WithTimeout(ctx, c.opts.Timeout+10*time.Second)
+	defer cancel()
+
+	resp, report, err := c.sm.SendCommandWithRetry(commandCtx, sessionID, command, c.opts.Retry)
+	if report != nil {
+		result.Attempts += report.Attempts
+	}
+	if err == nil && resp.Success {
+		return resp, true
+	}
+
+	result.Type = ResultFailure
+	result.Code, result.Error = playwright.ClassifyFailure(resp, err)
+	return nil, false
+}
+
+// discover normalizes link targets and keeps those the crawl may follow
+func (c *Crawler) discover(records interface{}, task Task) []Task {
+	list, _ := records.([]interface{})
+	if len(list) == 0 {
+		return nil
+	}
+	record, _ := list[0].(map[string]interface{})
+	hrefs, _ := record["links"].([]interface{})
+
+	unique := make(map[string]bool)
+	links := make([]Task, 0, len(hrefs))
+	for _, href := range hrefs {
+		raw, _ := href.(string)
+		normalized, target, err := normalizeURL(raw)
+		if err != nil || unique[normalized] {
+			continue
+		}
+		if c.opts.SameDomain && !c.domains[target.Host] {
+			continue
+		}
+		unique[normalized] = true
+		links = append(links, Task{URL: normalized, Depth: task.Depth + 1, Parent: task.URL})
+	}
+	return links
+}
+
+// normalizeURL drops fragments and keeps only http(s) URLs
+func normalizeURL(raw string) (string, *url.URL, error) {
+	target, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", nil, err
+	}
+	target.Scheme = strings.ToLower(target.Scheme)
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return "", nil, fmt.Errorf("unsupported scheme %q", target.Scheme)
+	}
+	target.Host = strings.ToLower(target.Host)
+	target.Fragment = ""
+	target.RawFragment = ""
+	if target.Path == "" {
+		target.Path = "/"
+	}
+	return target.String(), target, nil
+}
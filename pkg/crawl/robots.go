@@ -0,0 +1,203 @@
+package crawl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsRule is one Allow/Disallow line of a robots.txt group
+type robotsRule struct {
+	allow   bool
+	length  int
+	matcher *regexp.Regexp
+}
+
+// newRobotsRule compiles a path pattern supporting * and a trailing $
+func newRobotsRule(allow bool, pattern string) robotsRule {
+	anchored := strings.HasSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(pattern, "$")), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return robotsRule{allow: allow, length: len(pattern), matcher: regexp.MustCompile(expr)}
+}
+
+// robotsGroup holds the rules that apply to this crawler
+type robotsGroup struct {
+	rules []robotsRule
+}
+
+// allowed applies the longest matching rule; Allow wins ties
+func (g *robotsGroup) allowed(path string) bool {
+	best := -1
+	allow := true
+	for _, rule := range g.rules {
+		if !rule.matcher.MatchString(path) {
+			continue
+		}
+		if rule.length > best || (rule.length == best && rule.allow) {
+			best = rule.length
+			allow = rule.allow
+		}
+	}
+	return allow
+}
+
+// productToken returns the product name of a user agent, e.g. "webauto" for
+// "webauto/1.2 (+https://example.com/bot)"
+func productToken(userAgent string) string {
+	token, _, _ := strings.Cut(strings.TrimSpace(userAgent), "/")
+	if fields := strings.Fields(token); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// parseRobots extracts the group for userAgent, falling back to "*". Groups
+// match when their user-agent equals the crawler's product token, ignoring
+// case (RFC 9309 section 2.2.1).
+func parseRobots(r io.Reader, userAgent string) *robotsGroup {
+	agent := productToken(userAgent)
+	var specific, wildcard *robotsGroup
+	var current []*robotsGroup
+	inRules := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A user-agent line after rules starts a new group
+			if inRules {
+				current = nil
+				inRules = false
+			}
+			group := &robotsGroup{}
+			switch {
+			case value == "*":
+				if wildcard == nil {
+					wildcard = group
+				}
+				current = append(current, wildcard)
+			case agent != "" && strings.EqualFold(productToken(value), agent):
+				if specific == nil {
+					specific = group
+				}
+				current = append(current, specific)
+			default:
+				current = append(current, group)
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				// "Disallow:" with no path allows everything
+				continue
+			}
+			for _, group := range current {
+				group.rules = append(group.rules, newRobotsRule(key == "allow", value))
+			}
+		}
+	}
+
+	if specific != nil {
+		return specific
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	return &robotsGroup{}
+}
+
+// robotsCache fetches and caches robots.txt per origin
+type robotsCache struct {
+	userAgent string
+	client    *http.Client
+
+	mu     sync.Mutex
+	groups map[string]*robotsGroup
+}
+
+func newRobotsCache(userAgent string) *robotsCache {
+	return &robotsCache{
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		groups:    make(map[string]*robotsGroup),
+	}
+}
+
+// Allowed reports whether robots.txt of the URL's origin permits crawling it.
+// A missing robots.txt (4xx other than 401/403) allows everything and 401/403
+// disallow the whole site. An unreachable one (network error or 5xx)
+// disallows the URL with an error and is fetched again for the next URL.
+func (c *robotsCache) Allowed(ctx context.Context, target *url.URL) (bool, error) {
+	origin := target.Scheme + "://" + target.Host
+
+	c.mu.Lock()
+	group, ok := c.groups[origin]
+	c.mu.Unlock()
+
+	if !ok {
+		var err error
+		group, err = c.fetch(ctx, origin)
+		if err != nil {
+			return false, err
+		}
+		c.mu.Lock()
+		c.groups[origin] = group
+		c.mu.Unlock()
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	return group.allowed(path), nil
+}
+
+// fetch loads the group for origin; an error means robots.txt is
+// unreachable and the result must not be cached
+func (c *robotsCache) fetch(ctx context.Context, origin string) (*robotsGroup, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsGroup{}, nil
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("robots.txt unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("robots.txt unreachable: HTTP %d", resp.StatusCode)
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return &robotsGroup{rules: []robotsRule{newRobotsRule(false, "/")}}, nil
+	case resp.StatusCode != http.StatusOK:
+		return &robotsGroup{}, nil
+	}
+
+	return parseRobots(io.LimitReader(resp.Body, 512*1024), c.userAgent), nil
+}
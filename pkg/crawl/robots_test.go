@@ -0,0 +1,132 @@
+package crawl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const testRobots = `# comment
+User-agent: *
+Disallow: /private/
+Allow: /private/public/
+Disallow: /*.pdf$
+
+User-agent: webauto
+User-agent: otherbot
+Disallow: /admin
+Disallow:
+
+User-agent: strictbot
+Disallow: /
+`
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{"wildcard allows unlisted path", "somebot", "/index.html", true},
+		{"wildcard disallows prefix", "somebot", "/private/data", false},
+		{"longer allow wins", "somebot", "/private/public/page", true},
+		{"anchored pattern matches", "somebot", "/docs/file.pdf", false},
+		{"anchored pattern needs the end", "somebot", "/docs/file.pdf?x=1", true},
+		{"specific group replaces wildcard", "webauto", "/private/data", true},
+		{"specific group rule", "webauto", "/admin/users", false},
+		{"agent matching ignores case", "WebAuto", "/admin", false},
+		{"product token of a full agent", "webauto/1.2 (+https://example.com/bot)", "/admin", false},
+		{"agent containing a group name", "notwebauto", "/private/data", false},
+		{"group name containing the agent", "auto", "/private/data", false},
+		{"shared group", "otherbot", "/admin", false},
+		{"disallow all", "strictbot", "/", false},
+		{"empty agent uses wildcard", "", "/private/x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := parseRobots(strings.NewReader(testRobots), tt.userAgent)
+			if got := group.allowed(tt.path); got != tt.want {
+				t.Errorf("allowed(%q) for %q = %v, want %v", tt.path, tt.userAgent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotsRuleTies(t *testing.T) {
+	group := &robotsGroup{rules: []robotsRule{
+		newRobotsRule(false, "/page"),
+		newRobotsRule(true, "/page"),
+	}}
+	if !group.allowed("/page") {
+		t.Errorf("allowed(/page) = false, want Allow to win a tie")
+	}
+}
+
+func TestParseRobotsEmpty(t *testing.T) {
+	group := parseRobots(strings.NewReader(""), "webauto")
+	if !group.allowed("/anything") {
+		t.Errorf("empty robots.txt should allow everything")
+	}
+}
+
+func TestRobotsCacheStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    bool
+		wantErr bool
+		fetches int32
+	}{
+		{"found", http.StatusOK, false, false, 1},
+		{"missing allows all", http.StatusNotFound, true, false, 1},
+		{"forbidden disallows all", http.StatusForbidden, false, false, 1},
+		{"server error disallows and is not cached", http.StatusServiceUnavailable, false, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches.Add(1)
+				w.WriteHeader(tt.status)
+				w.Write([]byte("User-agent: *\nDisallow: /private/\n"))
+			}))
+			defer server.Close()
+
+			cache := newRobotsCache("webauto")
+			target, _ := url.Parse(server.URL + "/private/page")
+			for i := 0; i < 2; i++ {
+				got, err := cache.Allowed(context.Background(), target)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Allowed() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("Allowed() = %v, want %v", got, tt.want)
+				}
+			}
+			if got := fetches.Load(); got != tt.fetches {
+				t.Errorf("robots.txt fetched %d times, want %d", got, tt.fetches)
+			}
+		})
+	}
+}
+
+func TestRobotsCacheUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	target, _ := url.Parse(server.URL + "/page")
+	server.Close()
+
+	cache := newRobotsCache("webauto")
+	if allowed, err := cache.Allowed(context.Background(), target); err == nil || allowed {
+		t.Errorf("Allowed() = %v, %v; want false with an error", allowed, err)
+	}
+	if len(cache.groups) != 0 {
+		t.Errorf("unreachable robots.txt was cached")
+	}
+}
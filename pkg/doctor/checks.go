@@ -0,0 +1,538 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+)
+
+// minDevShmBytes is the /dev/shm size below which Chromium tends to crash on
+// large pages (Docker's default is 64MB)
+const minDevShmBytes = 512 << 20
+
+// maxLibraryScanFiles bounds how many browser files are checked with ldd
+const maxLibraryScanFiles = 400
+
+// launchScript starts a browser the way the session runner does, loads a
+// page and prints the browser version
+const launchScript = `
+const launcher = require('playwright')[process.env.WEBAUTO_DOCTOR_BROWSER];
+(async () => {
+  const browser = await launcher.launch({ headless: true });
+  const page = await browser.newPage();
+  await page.setContent('<p>webauto doctor</p>');
+  const version = browser.version();
+  await browser.close();
+  console.log(version);
+})().catch((error) => {
+  console.error(error.message);
+  process.exit(1);
+});
+`
+
+// checkCacheDir verifies the cache directory is writable
+func checkCacheDir(ctx context.Context, opts Options) []Check {
+	return []Check{timed("cache_dir", func() Check {
+		dir := bootstrap.GetCacheDir()
+		details := map[string]interface{}{"path": dir}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return Check{
+				Status:      StatusFail,
+				Message:     fmt.Sprintf("Cannot create cache directory: %v", err),
+				Remediation: fmt.Sprintf("Create %s and make it writable for this user, or run with a HOME that is writable", dir),
+				Details:     details,
+			}
+		}
+
+		file, err := os.CreateTemp(dir, ".doctor-*")
+		if err != nil {
+			return Check{
+				Status:      StatusFail,
+				Message:     fmt.Sprintf("Cache directory is not writable: %v", err),
+				Remediation: fmt.Sprintf("Fix the permissions of %s (e.g. chown -R $(id -u) %s)", dir, dir),
+				Details:     details,
+			}
+		}
+		file.Close()
+		os.Remove(file.Name())
+
+		return Check{Status: StatusPass, Message: "Cache directory is writable", Details: details}
+	})}
+}
+
+// checkNode verifies the node binary runs
+func checkNode(ctx context.Context, opts Options) []Check {
+	return []Check{timed("node_binary", func() Check {
+		details := map[string]interface{}{"path": opts.NodePath, "expected_version": bootstrap.NodeVersion}
+
+		output, err := runCommand(ctx, 10*time.Second, "", nil, opts.NodePath, "--version")
+		if err != nil {
+			return Check{
+				Status:      StatusFail,
+				Message:     fmt.Sprintf("Node.js does not run: %v", err),
+				Remediation: "Run: webauto runtime repair node",
+				Details:     details,
+			}
+		}
+
+		version := strings.TrimSpace(output)
+		details["version"] = version
+		if version != bootstrap.NodeVersion {
+			return Check{
+				Status:      StatusWarn,
+				Message:     fmt.Sprintf("Node.js %s differs from the tested version %s", version, bootstrap.NodeVersion),
+				Remediation: "Unset PLAYWRIGHT_NODE_PATH to use the bundled Node.js, or run: webauto runtime upgrade",
+				Details:     details,
+			}
+		}
+		return Check{Status: StatusPass, Message: "Node.js " + version, Details: details}
+	})}
+}
+
+// checkPlaywright verifies the Playwright library can be imported
+func checkPlaywright(ctx context.Context, opts Options) []Check {
+	return []Check{timed("playwright_import", func() Check {
+		details := map[string]interface{}{"node_modules": bootstrap.GetNodeModulesDir(), "expected_version": bootstrap.PlaywrightVersion}
+
+		script := "require('playwright'); console.log(require('playwright/package.json').version)"
+		output, err := runCommand(ctx, 20*time.Second, bootstrap.GetCacheDir(), nil, opts.NodePath, "-e", script)
+		if err != nil {
+			return Check{
+				Status:      StatusFail,
+				Message:     "Cannot import Playwright: " + errorSummary(err.Error()),
+				Remediation: "Run: webauto runtime repair playwright",
+				Details:     details,
+			}
+		}
+
+		version := strings.TrimSpace(output)
+		details["version"] = version
+		if version != bootstrap.PlaywrightVersion {
+			return Check{
+				Status:      StatusWarn,
+				Message:     fmt.Sprintf("Playwright %s differs from the pinned version %s", version, bootstrap.PlaywrightVersion),
+				Remediation: "Run: webauto runtime upgrade",
+				Details:     details,
+			}
+		}
+		return Check{Status: StatusPass, Message: "Playwright " + version, Details: details}
+	})}
+}
+
+// checkBrowsers smoke-tests a headless launch of each browser type
+func checkBrowsers(ctx context.Context, opts Options) []Check {
+	browserTypes := opts.BrowserTypes
+	if len(browserTypes) == 0 {
+		browserTypes = bootstrap.SupportedBrowsers
+	}
+
+	checks := make([]Check, 0, len(browserTypes))
+	for _, browserType := range browserTypes {
+		browserType := browserType
+		checks = append(checks, timed("browser_launch:"+browserType, func() Check {
+			details := map[string]interface{}{"browser_type": browserType}
+
+			if !bootstrap.IsBrowserInstalled(browserType) {
+				if browserType == "chromium" {
+					return Check{
+						Status:      StatusFail,
+						Message:     "Chromium is not installed",
+						Remediation: "Run: webauto runtime install-browser chromium",
+						Details:     details,
+					}
+				}
+				return Check{
+					Status:      StatusSkip,
+					Message:     fmt.Sprintf("%s is not installed (installed on first use)", browserType),
+					Remediation: "Run: webauto runtime install-browser " + browserType,
+					Details:     details,
+				}
+			}
+
+			if opts.SkipLaunch {
+				return Check{Status: StatusSkip, Message: "Installed; launch test skipped", Details: details}
+			}
+
+			env := []string{
+				"PLAYWRIGHT_BROWSERS_PATH=" + bootstrap.GetBrowsersDir(),
+				"WEBAUTO_DOCTOR_BROWSER=" + browserType,
+			}
+			output, err := runCommand(ctx, opts.LaunchTimeout, bootstrap.GetCacheDir(), env, opts.NodePath, "-e", launchScript)
+			if err != nil {
+				message, remediation := classifyLaunchError(browserType, err.Error())
+				return Check{Status: StatusFail, Message: message, Remediation: remediation, Details: details}
+			}
+
+			details["version"] = strings.TrimSpace(output)
+			return Check{Status: StatusPass, Message: fmt.Sprintf("%s %s launched headless", browserType, details["version"]), Details: details}
+		}))
+	}
+	return checks
+}
+
+// classifyLaunchError turns a failed launch into a message and a fix
+func classifyLaunchError(browserType, errText string) (string, string) {
+	message := "Launch failed: " + errorSummary(errText)
+
+	switch {
+	case strings.Contains(errText, "Executable doesn't exist"):
+		return message, "Run: webauto runtime install-browser " + browserType
+	case strings.Contains(errText, "Host system is missing dependencies"),
+		strings.Contains(errText, "error while loading shared libraries"),
+		strings.Contains(errText, "missing dependencies"):
+		return message, fmt.Sprintf("Install the browser's system libraries (see the shared_libraries check), e.g. sudo npx playwright install-deps %s on Debian/Ubuntu", browserType)
+	case strings.Contains(errText, "No usable sandbox"),
+		strings.Contains(errText, "without --no-sandbox"),
+		strings.Contains(errText, "user namespace"):
+		return message, "Enable unprivileged user namespaces (sysctl kernel.unprivileged_userns_clone=1) or run as a non-root user; in containers allow user namespaces in the seccomp/AppArmor profile"
+	case strings.Contains(errText, "crashed"), strings.Contains(errText, "Target page, context or browser has been closed"):
+		return message, "The browser crashed; check the dev_shm check and the container's memory limit"
+	case strings.Contains(errText, "timed out"):
+		return message, "The browser did not start in time; check CPU and memory limits, then retry"
+	default:
+		return message, "Run: webauto runtime repair " + browserType
+	}
+}
+
+// checkSharedLibraries resolves the shared libraries of each installed
+// browser build with ldd (Linux only)
+func checkSharedLibraries(ctx context.Context, opts Options) []Check {
+	return []Check{timed("shared_libraries", func() Check {
+		if runtime.GOOS != "linux" {
+			return Check{Status: StatusSkip, Message: "Only checked on Linux"}
+		}
+		if _, err := exec.LookPath("ldd"); err != nil {
+			return Check{Status: StatusSkip, Message: "ldd is not available"}
+		}
+
+		builds, err := bootstrap.ListBrowserBuilds()
+		if err != nil {
+			return Check{Status: StatusWarn, Message: err.Error()}
+		}
+
+		missing := map[string][]string{}
+		scanned := 0
+		for _, build := range builds {
+			if !build.Complete || build.Stale {
+				continue
+			}
+
+			libs := map[string]bool{}
+			for _, file := range elfFiles(filepath.Join(bootstrap.GetBrowsersDir(), build.Dir), maxLibraryScanFiles-scanned) {
+				scanned++
+				for _, lib := range missingLibraries(ctx, file) {
+					libs[lib] = true
+				}
+			}
+			if len(libs) > 0 {
+				missing[build.Dir] = sortedKeys(libs)
+			}
+		}
+
+		details := map[string]interface{}{"files_scanned": scanned}
+		if len(missing) == 0 {
+			return Check{Status: StatusPass, Message: "All browser libraries resolve", Details: details}
+		}
+
+		broken := make([]string, 0, len(missing))
+		for dir := range missing {
+			broken = append(broken, dir)
+		}
+		sort.Strings(broken)
+
+		details["missing"] = missing
+		return Check{
+			Status:      StatusFail,
+			Message:     fmt.Sprintf("Missing system libraries for %s", strings.Join(broken, ", ")),
+			Remediation: "Install the missing libraries: sudo npx playwright install-deps (Debian/Ubuntu), or the distribution packages that provide the listed .so files",
+			Details:     details,
+		}
+	})}
+}
+
+// elfFiles lists up to limit ELF executables and shared objects below dir
+func elfFiles(dir string, limit int) []string {
+	files := []string{}
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || len(files) >= limit {
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if info.Mode()&0111 == 0 && !strings.Contains(info.Name(), ".so") {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+
+		magic := make([]byte, 4)
+		if _, err := io.ReadFull(file, magic); err == nil && bytes.Equal(magic, []byte("\x7fELF")) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+// missingLibraries returns the libraries ldd reports as "not found"
+func missingLibraries(ctx context.Context, file string) []string {
+	output, _ := runCommand(ctx, 10*time.Second, "", nil, "ldd", file)
+
+	libs := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(line, "=> not found") {
+			libs = append(libs, strings.TrimSpace(strings.TrimSuffix(line, "=> not found")))
+		}
+	}
+	return libs
+}
+
+// checkSandbox reports whether the browsers can use their sandbox (Linux only)
+func checkSandbox(ctx context.Context, opts Options) []Check {
+	return []Check{timed("sandbox", func() Check {
+		if runtime.GOOS != "linux" {
+			return Check{Status: StatusSkip, Message: "Only checked on Linux"}
+		}
+
+		details := map[string]interface{}{
+			"euid":      os.Geteuid(),
+			"container": inContainer(),
+		}
+
+		restricted := ""
+		switch {
+		case readProcValue("/proc/sys/kernel/unprivileged_userns_clone") == "0":
+			restricted = "kernel.unprivileged_userns_clone=0"
+		case readProcValue("/proc/sys/user/max_user_namespaces") == "0":
+			restricted = "user.max_user_namespaces=0"
+		case readProcValue("/proc/sys/kernel/apparmor_restrict_unprivileged_userns") == "1":
+			restricted = "kernel.apparmor_restrict_unprivileged_userns=1"
+		}
+		details["userns_restriction"] = restricted
+
+		// Playwright launches Chromium with its sandbox disabled, so a
+		// namespace restriction is only informational; a browser that still
+		// trips over it is reported by the launch check
+		if restricted != "" {
+			return Check{
+				Status:  StatusPass,
+				Message: "Unprivileged user namespaces are disabled (" + restricted + "); not needed while the browser sandbox is off (Playwright's default)",
+				Details: details,
+			}
+		}
+		return Check{Status: StatusPass, Message: "Browser sandbox requirements are met", Details: details}
+	})}
+}
+
+// checkDevShm verifies /dev/shm is large enough for Chromium (Linux only)
+func checkDevShm(ctx context.Context, opts Options) []Check {
+	return []Check{timed("dev_shm", func() Check {
+		if runtime.GOOS != "linux" {
+			return Check{Status: StatusSkip, Message: "Only checked on Linux"}
+		}
+
+		size, err := devShmSize()
+		if err != nil {
+			return Check{
+				Status:      StatusWarn,
+				Message:     fmt.Sprintf("Cannot read /dev/shm: %v", err),
+				Remediation: "Mount a tmpfs on /dev/shm (Docker: --shm-size=1g)",
+			}
+		}
+
+		details := map[string]interface{}{"size_bytes": size, "recommended_bytes": minDevShmBytes}
+		if size < minDevShmBytes {
+			return Check{
+				Status:      StatusWarn,
+				Message:     fmt.Sprintf("/dev/shm is only %d MB; Chromium may crash on large pages", size>>20),
+				Remediation: "Give the container more shared memory (Docker: --shm-size=1g, Kubernetes: an emptyDir with medium: Memory on /dev/shm)",
+				Details:     details,
+			}
+		}
+		return Check{Status: StatusPass, Message: fmt.Sprintf("/dev/shm is %d MB", size>>20), Details: details}
+	})}
+}
+
+// checkFonts verifies fontconfig has fonts covering Korean, Japanese and
+// Chinese, without which CJK text renders as boxes in screenshots and PDFs
+func checkFonts(ctx context.Context, opts Options) []Check {
+	return []Check{timed("cjk_fonts", func() Check {
+		if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+			return Check{Status: StatusSkip, Message: "CJK fonts ship with the operating system"}
+		}
+		if _, err := exec.LookPath("fc-list"); err != nil {
+			return Check{
+				Status:      StatusWarn,
+				Message:     "fontconfig (fc-list) is not installed; browsers may render no text",
+				Remediation: "Install fontconfig and a CJK font: sudo apt-get install fontconfig fonts-noto-cjk",
+			}
+		}
+
+		coverage := map[string]interface{}{}
+		missing := []string{}
+		for _, lang := range []string{"ko", "ja", "zh"} {
+			output, _ := runCommand(ctx, 10*time.Second, "", nil, "fc-list", ":lang="+lang, "family")
+			families := nonEmptyLines(output)
+			coverage[lang] = len(families)
+			if len(families) == 0 {
+				missing = append(missing, lang)
+			}
+		}
+
+		details := map[string]interface{}{"font_families": coverage}
+		if len(missing) > 0 {
+			message := fmt.Sprintf("No fonts for %s", strings.Join(missing, ", "))
+			if missing[0] == "ko" {
+				message += "; Korean text renders as boxes in page-screenshot and page-pdf"
+			}
+			return Check{
+				Status:      StatusWarn,
+				Message:     message,
+				Remediation: "Install a CJK font, then run fc-cache -f: sudo apt-get install fonts-noto-cjk (Debian/Ubuntu) or sudo dnf install google-noto-sans-cjk-fonts (Fedora/RHEL)",
+				Details:     details,
+			}
+		}
+		return Check{Status: StatusPass, Message: "Fonts cover Korean, Japanese and Chinese", Details: details}
+	})}
+}
+
+// checkSessions reports saved sessions whose runner is gone or idle too long
+func checkSessions(ctx context.Context, opts Options) []Check {
+	return []Check{timed("stale_sessions", func() Check {
+		if opts.Sessions == nil {
+			return Check{Status: StatusSkip, Message: "Session check disabled"}
+		}
+
+		stale := opts.Sessions.StaleSessions()
+		if len(stale) == 0 {
+			return Check{Status: StatusPass, Message: "No stale sessions"}
+		}
+
+		sessions := make([]map[string]interface{}, 0, len(stale))
+		for _, entry := range stale {
+			sessions = append(sessions, map[string]interface{}{
+				"session_id": entry.Session.ID,
+				"pid":        entry.Session.PID,
+				"reason":     entry.Reason,
+			})
+		}
+		return Check{
+			Status:      StatusWarn,
+			Message:     fmt.Sprintf("%d stale session(s) hold session slots", len(stale)),
+			Remediation: "Close them with: webauto session-close --session-id <id>",
+			Details:     map[string]interface{}{"sessions": sessions},
+		}
+	})}
+}
+
+// runCommand runs a command with a timeout and returns its stdout. On failure
+// the error carries stderr (or stdout) for diagnosis.
+func runCommand(ctx context.Context, timeout time.Duration, dir string, env []string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return stdout.String(), fmt.Errorf("timed out after %s", timeout)
+		}
+		text := strings.TrimSpace(stderr.String())
+		if text == "" {
+			text = strings.TrimSpace(stdout.String())
+		}
+		if text == "" {
+			return stdout.String(), err
+		}
+		return stdout.String(), fmt.Errorf("%s", text)
+	}
+	return stdout.String(), nil
+}
+
+// inContainer guesses whether the process runs inside a container
+func inContainer() bool {
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return true
+	}
+	data, err := os.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return false
+	}
+	text := string(data)
+	return strings.Contains(text, "docker") || strings.Contains(text, "kubepods") || strings.Contains(text, "containerd")
+}
+
+// readProcValue reads a trimmed /proc value, or "" when it does not exist
+func readProcValue(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// errorSummary picks the line of a Node.js error output that names the
+// error, falling back to the first line
+func errorSummary(text string) string {
+	summary := text
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = summary[:i]
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Error:") || strings.Contains(line, "Error: ") {
+			summary = line
+			break
+		}
+	}
+	if len(summary) > 300 {
+		summary = summary[:300] + "..."
+	}
+	return summary
+}
+
+func nonEmptyLines(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
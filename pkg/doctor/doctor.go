@@ -0,0 +1,123 @@
+// Package doctor runs environment diagnostics for the webauto runtime: the
+// Node.js binary, the Playwright library, browser launches, system
+// libraries, fonts, shared memory, sandboxing, the cache directory and
+// leftover sessions.
+package doctor
+
+import (
+	"context"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/playwright"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Check is the result of one diagnostic
+type Check struct {
+	Name        string                 `json:"name"`
+	Status      string                 `json:"status"`
+	Message     string                 `json:"message"`
+	Remediation string                 `json:"remediation,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+	DurationMs  int64                  `json:"duration_ms"`
+}
+
+// Summary counts checks by status
+type Summary struct {
+	Pass int `json:"pass"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+	Skip int `json:"skip"`
+}
+
+// Report is the outcome of a doctor run
+type Report struct {
+	Checks  []Check `json:"checks"`
+	Summary Summary `json:"summary"`
+	Healthy bool    `json:"healthy"`
+}
+
+// Options selects which checks run
+type Options struct {
+	// NodePath is the node executable used for the Playwright checks
+	NodePath string
+
+	// BrowserTypes are smoke-tested with a real launch; browsers that are
+	// not installed are skipped, except chromium which is required
+	BrowserTypes []string
+
+	// SkipLaunch disables the browser launch smoke tests
+	SkipLaunch bool
+
+	// LaunchTimeout bounds each browser launch smoke test
+	LaunchTimeout time.Duration
+
+	// Sessions is used to find stale sessions (skipped when nil)
+	Sessions *playwright.SessionManager
+}
+
+// Run executes all checks in order and summarizes them
+func Run(ctx context.Context, opts Options) *Report {
+	if opts.LaunchTimeout <= 0 {
+		opts.LaunchTimeout = 60 * time.Second
+	}
+
+	checks := []func(context.Context, Options) []Check{
+		checkCacheDir,
+		checkNode,
+		checkPlaywright,
+		checkBrowsers,
+		checkSharedLibraries,
+		checkSandbox,
+		checkDevShm,
+		checkFonts,
+		checkSessions,
+	}
+
+	report := &Report{Checks: []Check{}}
+	for _, check := range checks {
+		report.Checks = append(report.Checks, check(ctx, opts)...)
+	}
+
+	for _, check := range report.Checks {
+		switch check.Status {
+		case StatusPass:
+			report.Summary.Pass++
+		case StatusWarn:
+			report.Summary.Warn++
+		case StatusFail:
+			report.Summary.Fail++
+		case StatusSkip:
+			report.Summary.Skip++
+		}
+	}
+	report.Healthy = report.Summary.Fail == 0
+	return report
+}
+
+// timed runs fn and stamps the resulting check with its duration
+func timed(name string, fn func() Check) Check {
+	start := time.Now()
+	check := fn()
+	check.Name = name
+	check.DurationMs = time.Since(start).Milliseconds()
+	return check
+}
+
+// Failures returns the checks that failed
+func (r *Report) Failures() []Check {
+	failed := []Check{}
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package doctor
+
+import "syscall"
+
+// devShmSize returns the size of the /dev/shm filesystem in bytes
+func devShmSize() (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs("/dev/shm", &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Blocks) * int64(stat.Bsize), nil
+}
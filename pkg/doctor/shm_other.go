@@ -0,0 +1,10 @@
+//go:build !linux
+
+package doctor
+
+import "errors"
+
+// devShmSize is only implemented on Linux
+func devShmSize() (int64, error) {
+	return 0, errors.New("/dev/shm is not available on this platform")
+}
@@ -0,0 +1,51 @@
+// Package export writes extracted tabular data to files.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// FormatFromPath infers the output format from a file extension,
+// defaulting to JSON
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	default:
+		return FormatJSON
+	}
+}
+
+// WriteCSV writes a header row followed by data rows as CSV
+func WriteCSV(path string, headers []string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if len(headers) > 0 {
+		if err := writer.Write(headers); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+// WriteXLSX writes a header row followed by data rows as a single-sheet
+// Excel workbook. Cells are stored as inline strings, so no shared string
+// table or styles are needed.
+func WriteXLSX(path string, headers []string, rows [][]string) error {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", []byte(xlsxWorkbook)},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/worksheets/sheet1.xml", buildSheetXML(headers, rows)},
+	}
+
+	for _, part := range parts {
+		w, err := archive.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", part.name, err)
+		}
+		if _, err := w.Write(part.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finalize workbook: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+
+	return nil
+}
+
+func buildSheetXML(headers []string, rows [][]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	rowNumber := 0
+	writeRow := func(cells []string) {
+		rowNumber++
+		fmt.Fprintf(&buf, `<row r="%d">`, rowNumber)
+		for i, value := range cells {
+			fmt.Fprintf(&buf, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(i), rowNumber)
+			xml.EscapeText(&buf, []byte(value))
+			buf.WriteString(`</t></is></c>`)
+		}
+		buf.WriteString(`</row>`)
+	}
+
+	if len(headers) > 0 {
+		writeRow(headers)
+	}
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	buf.WriteString(`</sheetData></worksheet>`)
+	return buf.Bytes()
+}
+
+// columnName converts a 0-based column index to a spreadsheet column (A, B, ..., AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
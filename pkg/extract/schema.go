@@ -0,0 +1,168 @@
+// Package extract defines the schema used for structured scraping. The
+// schema is validated here and evaluated by the runner in a single round trip.
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Field sources
+const (
+	SourceText      = "text"
+	SourceAttribute = "attribute"
+	SourceProperty  = "property"
+	SourceHTML      = "html"
+)
+
+// Field transforms
+const (
+	TransformTrim    = "trim"
+	TransformLower   = "lower"
+	TransformUpper   = "upper"
+	TransformNumber  = "number"
+	TransformInteger = "integer"
+	TransformBoolean = "boolean"
+	TransformURL     = "url"
+)
+
+var validSources = map[string]bool{
+	SourceText:      true,
+	SourceAttribute: true,
+	SourceProperty:  true,
+	SourceHTML:      true,
+}
+
+var validTransforms = map[string]bool{
+	TransformTrim:    true,
+	TransformLower:   true,
+	TransformUpper:   true,
+	TransformNumber:  true,
+	TransformInteger: true,
+	TransformBoolean: true,
+	TransformURL:     true,
+}
+
+// Schema describes the records to extract from a page. Every element matching
+// Root becomes one record; without Root the whole document is one record.
+type Schema struct {
+	Root   string            `json:"root,omitempty"`
+	Limit  int               `json:"limit,omitempty"`
+	Fields map[string]*Field `json:"fields"`
+}
+
+// Field describes one value of a record. Selector is a CSS selector relative
+// to the enclosing item (empty selects the item itself). A field with nested
+// Fields produces an object per match; List collects every match instead of
+// the first.
+type Field struct {
+	Selector  string            `json:"selector,omitempty"`
+	Source    string            `json:"source,omitempty"`
+	Attribute string            `json:"attribute,omitempty"`
+	Property  string            `json:"property,omitempty"`
+	Regex     string            `json:"regex,omitempty"`
+	Transform string            `json:"transform,omitempty"`
+	List      bool              `json:"list,omitempty"`
+	Limit     int               `json:"limit,omitempty"`
+	Fields    map[string]*Field `json:"fields,omitempty"`
+	Default   interface{}       `json:"default,omitempty"`
+}
+
+// UnmarshalJSON accepts a bare string as shorthand for a text field
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var selector string
+	if err := json.Unmarshal(data, &selector); err == nil {
+		*f = Field{Selector: selector}
+		return nil
+	}
+
+	type plain Field
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = Field(decoded)
+	return nil
+}
+
+// LoadSchema reads a schema from a file path or, when the value starts with
+// "{", from inline JSON
+func LoadSchema(value string) (*Schema, error) {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		content, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema file: %w", err)
+		}
+		data = content
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+// Validate checks sources, transforms and required names of every field
+func (s *Schema) Validate() error {
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("schema must declare at least one field")
+	}
+	if s.Limit < 0 {
+		return fmt.Errorf("schema limit must not be negative")
+	}
+	return validateFields("", s.Fields)
+}
+
+func validateFields(prefix string, fields map[string]*Field) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		field := fields[name]
+		if field == nil {
+			return fmt.Errorf("field %s: definition is empty", path)
+		}
+		if field.Source != "" && !validSources[field.Source] {
+			return fmt.Errorf("field %s: unknown source %q (use text, attribute, property or html)", path, field.Source)
+		}
+		if field.Source == SourceAttribute && field.Attribute == "" {
+			return fmt.Errorf("field %s: source attribute requires \"attribute\"", path)
+		}
+		if field.Source == SourceProperty && field.Property == "" {
+			return fmt.Errorf("field %s: source property requires \"property\"", path)
+		}
+		if field.Transform != "" && !validTransforms[field.Transform] {
+			return fmt.Errorf("field %s: unknown transform %q", path, field.Transform)
+		}
+		if field.Limit < 0 {
+			return fmt.Errorf("field %s: limit must not be negative", path)
+		}
+		if len(field.Fields) > 0 {
+			if field.Source != "" || field.Regex != "" || field.Transform != "" {
+				return fmt.Errorf("field %s: nested fields cannot be combined with source, regex or transform", path)
+			}
+			if err := validateFields(path, field.Fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package extract
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Field
+		wantErr bool
+	}{
+		{"shorthand string", `"h3.title"`, Field{Selector: "h3.title"}, false},
+		{"empty shorthand selects the item", `""`, Field{}, false},
+		{
+			"object form",
+			`{"selector": "a", "source": "attribute", "attribute": "href", "transform": "url"}`,
+			Field{Selector: "a", Source: SourceAttribute, Attribute: "href", Transform: TransformURL},
+			false,
+		},
+		{"nested shorthand", `{"list": true, "fields": {"label": "span"}}`, Field{List: true, Fields: map[string]*Field{"label": {Selector: "span"}}}, false},
+		{"number", `42`, Field{}, true},
+		{"selector of the wrong type", `{"selector": 1}`, Field{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Field
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"shorthand fields", `{"root": "li", "fields": {"name": "h3", "price": ".price"}}`, ""},
+		{"missing selector selects the item", `{"root": "a", "fields": {"href": {"source": "attribute", "attribute": "href"}}}`, ""},
+		{"no fields", `{"root": "li", "fields": {}}`, "at least one field"},
+		{"negative limit", `{"limit": -1, "fields": {"name": "h3"}}`, "limit must not be negative"},
+		{"empty definition", `{"fields": {"name": null}}`, "field name: definition is empty"},
+		{"unknown source", `{"fields": {"name": {"selector": "h3", "source": "value"}}}`, `unknown source "value"`},
+		{"unknown transform", `{"fields": {"price": {"selector": ".price", "transform": "float"}}}`, `unknown transform "float"`},
+		{"attribute without name", `{"fields": {"link": {"selector": "a", "source": "attribute"}}}`, `requires "attribute"`},
+		{"property without name", `{"fields": {"on": {"selector": "input", "source": "property"}}}`, `requires "property"`},
+		{
+			"nested list",
+			`{"fields": {"options": {"selector": ".option", "list": true, "limit": 5,
+				"fields": {"label": "span", "value": {"source": "attribute", "attribute": "data-value"}}}}}`,
+			"",
+		},
+		{
+			"error inside a nested list names the path",
+			`{"fields": {"options": {"selector": ".option", "list": true,
+				"fields": {"value": {"source": "attribute"}}}}}`,
+			"field options.value:",
+		},
+		{
+			"nested list with a transform",
+			`{"fields": {"options": {"selector": ".option", "list": true, "transform": "trim",
+				"fields": {"label": "span"}}}}`,
+			"nested fields cannot be combined",
+		},
+		{"negative field limit", `{"fields": {"tags": {"selector": ".tag", "list": true, "limit": -2}}}`, "field tags: limit must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+
+			err := schema.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(`{"root": "li", "fields": {"name": "h3"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"inline JSON", ` {"fields": {"title": "h1"}}`, false},
+		{"file", path, false},
+		{"missing file", filepath.Join(t.TempDir(), "missing.json"), true},
+		{"invalid schema", `{"fields": {}}`, true},
+		{"malformed JSON", `{"fields": `, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := LoadSchema(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(schema.Fields) == 0 {
+				t.Error("LoadSchema() returned a schema without fields")
+			}
+		})
+	}
+}
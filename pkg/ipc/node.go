@@ -20,6 +20,14 @@ type NodeResponse struct {
 	Success bool                   `json:"success"`
 	Data    map[string]interface{} `json:"data,omitempty"`
 	Error   string                 `json:"error,omitempty"`
+
+	// URL is the page's URL after a session runner command, successful or
+	// not
+	URL string `json:"url,omitempty"`
+
+	// Chunk marks an intermediate part of a streamed response, sent ahead
+	// of the final response of the same command
+	Chunk bool `json:"chunk,omitempty"`
 }
 
 // NodeExecutor handles Node.js subprocess execution
@@ -0,0 +1,212 @@
+package playwright
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+	"github.com/oa-plugins/webauto/pkg/ipc"
+)
+
+// runnerProcess is a launched Node.js session runner with its browser
+type runnerProcess struct {
+	id      string
+	pid     int
+	port    int
+	version string
+	process interface{} // *exec.Cmd when launched here, *os.Process when adopted
+}
+
+// kill terminates the runner process
+func (r *runnerProcess) kill() {
+	switch p := r.process.(type) {
+	case *exec.Cmd:
+		if p.Process != nil {
+			_ = p.Process.Kill()
+		}
+	case *os.Process:
+		_ = p.Kill()
+	}
+}
+
+// launchRunner starts the session runner script and waits for its launch
+// response (browser version and IPC port)
+func launchRunner(ctx context.Context, nodePath string, opts LaunchOptions) (*runnerProcess, error) {
+	// Ensure session runner script is available and configure launch parameters
+	scriptPath, err := ensureSessionRunnerScript()
+	if err != nil {
+		return nil, err
+	}
+
+	runnerConfig := map[string]interface{}{
+		"browserType": opts.BrowserType,
+		"headless":    opts.Headless,
+		"shared":      opts.SharedBrowser,
+	}
+
+	configJSON, err := json.Marshal(runnerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode runner config: %w", err)
+	}
+
+	// Create command to run Node.js script
+	cmd := exec.CommandContext(ctx, nodePath, scriptPath)
+
+	// Set working directory to cache dir so Node.js can find playwright module
+	cmd.Dir = bootstrap.GetCacheDir()
+
+	// Set environment variables required by the Playwright runner
+	browsersDir := bootstrap.GetBrowsersDir()
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("PLAYWRIGHT_BROWSERS_PATH=%s", browsersDir),
+		fmt.Sprintf("WEBAUTO_RUNNER_CONFIG=%s", string(configJSON)),
+	)
+
+	// Get stdout pipe for reading launch response
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	// Get stderr pipe for error messages
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	// Start the process (non-blocking)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start browser process: %w", err)
+	}
+
+	// Read first line of output (launch response) with timeout
+	type scanResult struct {
+		data []byte
+		err  error
+	}
+	scanChan := make(chan scanResult, 1)
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		if scanner.Scan() {
+			scanChan <- scanResult{data: scanner.Bytes(), err: nil}
+		} else {
+			scanChan <- scanResult{data: nil, err: scanner.Err()}
+		}
+	}()
+
+	// Wait for scan result or timeout
+	var scanData []byte
+	select {
+	case result := <-scanChan:
+		if result.err != nil || result.data == nil {
+			// Read stderr for error details
+			stderrData, _ := io.ReadAll(stderr)
+			cmd.Process.Kill()
+			if len(stderrData) > 0 {
+				return nil, fmt.Errorf("failed to read browser launch response, stderr: %s", string(stderrData))
+			}
+			return nil, fmt.Errorf("failed to read browser launch response")
+		}
+		scanData = result.data
+	case <-time.After(30 * time.Second):
+		// Timeout waiting for response
+		stderrData, _ := io.ReadAll(stderr)
+		cmd.Process.Kill()
+		if len(stderrData) > 0 {
+			return nil, fmt.Errorf("timeout waiting for browser launch response, stderr: %s", string(stderrData))
+		}
+		return nil, fmt.Errorf("timeout waiting for browser launch response")
+	}
+
+	// Parse launch response
+	var response ipc.NodeResponse
+	if err := json.Unmarshal(scanData, &response); err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("failed to parse launch response: %w", err)
+	}
+
+	if !response.Success {
+		cmd.Process.Kill()
+		errMsg := "unknown error"
+		if response.Error != "" {
+			errMsg = response.Error
+		}
+		// Playwright reports a missing browser binary as "Executable doesn't exist"
+		if strings.Contains(errMsg, "Executable doesn't exist") {
+			return nil, fmt.Errorf("%w: %s", ErrBrowserNotInstalled, errMsg)
+		}
+		return nil, fmt.Errorf("browser launch failed: %s", errMsg)
+	}
+
+	// Extract browser info from response
+	browserVersion, _ := response.Data["version"].(string)
+	isConnected, _ := response.Data["isConnected"].(bool)
+	port, _ := response.Data["port"].(float64) // JSON numbers are float64
+
+	// Log successful launch (for debugging)
+	if !isConnected {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("browser launched but is not connected")
+	}
+
+	if port == 0 {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("failed to get TCP port from browser launch response")
+	}
+
+	return &runnerProcess{
+		id:      "run_" + uuid.New().String()[:8],
+		pid:     cmd.Process.Pid,
+		port:    int(port),
+		version: browserVersion,
+		process: cmd,
+	}, nil
+}
+
+// ErrBrowserNotInstalled is returned when the requested browser binary is
+// missing and cannot be installed automatically
+var ErrBrowserNotInstalled = errors.New("playwright browser not installed")
+
+// ensureBrowser makes sure the browser binary for browserType is installed,
+// installing it on demand when BROWSER_AUTO_INSTALL allows. The on-demand
+// install never installs system libraries, which would need root; that stays
+// with "runtime install-browser --with-deps". Installer output goes to stderr
+// so it never mixes with the JSON response on stdout.
+func (sm *SessionManager) ensureBrowser(browserType string) error {
+	if !bootstrap.IsSupportedBrowser(browserType) {
+		return fmt.Errorf("unsupported browser type: %s (supported: %s)", browserType, strings.Join(bootstrap.SupportedBrowsers, ", "))
+	}
+	if bootstrap.IsBrowserInstalled(browserType) {
+		return nil
+	}
+
+	if !sm.cfg.BrowserAutoInstall {
+		return fmt.Errorf("%w: %s", ErrBrowserNotInstalled, browserType)
+	}
+
+	if err := bootstrap.InstallBrowserWithProgress(sm.cfg.PlaywrightNodePath, browserType, false); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrBrowserNotInstalled, browserType, err)
+	}
+	return nil
+}
+
+// describeFailure formats a failed runner exchange for error messages
+func describeFailure(resp *ipc.NodeResponse, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if resp != nil && resp.Error != "" {
+		return resp.Error
+	}
+	return "unknown error"
+}
@@ -2,6 +2,7 @@ package playwright
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -70,7 +71,7 @@ func (sm *SessionManager) flushSessionsToDisk() {
 	for _, session := range sm.sessions {
 		if err := session.session.saveSession(); err != nil {
 			// Log error but continue with other sessions
-			fmt.Printf("Warning: failed to flush session %s: %v\n", session.session.ID, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to flush session %s: %v\n", session.session.ID, err)
 		}
 	}
 }
@@ -0,0 +1,307 @@
+package playwright
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/internal/filelock"
+	"github.com/oa-plugins/webauto/pkg/bootstrap"
+)
+
+// PoolEntry is an idle, pre-launched runner waiting in the warm pool. Entries
+// are files under <cache>/pool so every webauto process shares the pool; a
+// runner is claimed by atomically renaming its file.
+type PoolEntry struct {
+	ID          string    `json:"id"`
+	BrowserType string    `json:"browser_type"`
+	Headless    bool      `json:"headless"`
+	PID         int       `json:"pid"`
+	Port        int       `json:"port"`
+	Browser     string    `json:"browser"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func poolDir() string {
+	return filepath.Join(bootstrap.GetCacheDir(), "pool")
+}
+
+// poolKey identifies the launch options a runner was started with
+func poolKey(browserType string, headless bool) string {
+	if headless {
+		return browserType + "-headless"
+	}
+	return browserType + "-headed"
+}
+
+// PoolEnabled reports whether SESSION_POOL_SIZE enables the warm pool
+func (sm *SessionManager) PoolEnabled() bool {
+	return sm.cfg.SessionPoolSize > 0
+}
+
+// PoolEntries returns the idle runners of all keys, oldest first
+func (sm *SessionManager) PoolEntries() []*PoolEntry {
+	entries, _ := readPoolEntries("")
+	return entries
+}
+
+// claimWarmRunner takes an idle runner for the given options out of the pool.
+// Expired or unresponsive runners met on the way are killed.
+func (sm *SessionManager) claimWarmRunner(browserType string, headless bool) (*runnerProcess, bool) {
+	if !sm.PoolEnabled() {
+		return nil, false
+	}
+
+	entries, paths := readPoolEntries(poolKey(browserType, headless))
+	for i, entry := range entries {
+		claimed := paths[i] + ".claimed"
+		if err := os.Rename(paths[i], claimed); err != nil {
+			continue // Claimed by another process
+		}
+		_ = os.Remove(claimed)
+
+		if sm.poolEntryExpired(entry) || !runnerAlive(entry.Port) {
+			killPID(entry.PID)
+			continue
+		}
+
+		process, err := os.FindProcess(entry.PID)
+		if err != nil {
+			continue
+		}
+		return &runnerProcess{
+			id:      entry.ID,
+			pid:     entry.PID,
+			port:    entry.Port,
+			version: entry.Browser,
+			process: process,
+		}, true
+	}
+
+	return nil, false
+}
+
+// FillPool launches runners until the pool for the given options holds
+// SESSION_POOL_SIZE idle runners. Only one filler runs per key at a time;
+// concurrent calls return immediately.
+func (sm *SessionManager) FillPool(ctx context.Context, browserType string, headless bool) (int, error) {
+	if !sm.PoolEnabled() {
+		return 0, nil
+	}
+
+	key := poolKey(browserType, headless)
+	if err := os.MkdirAll(poolDir(), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create pool directory: %w", err)
+	}
+
+	// The lock is held for the whole fill, however long launches take, and
+	// is dropped by the OS if this process dies
+	lock, err := filelock.TryAcquire(filepath.Join(poolDir(), key+".lock"))
+	if err != nil {
+		return 0, err
+	}
+	if lock == nil {
+		return 0, nil
+	}
+	defer lock.Release()
+
+	entries, paths := readPoolEntries(key)
+	idle := 0
+	for i, entry := range entries {
+		if sm.poolEntryExpired(entry) || !runnerAlive(entry.Port) {
+			if os.Remove(paths[i]) == nil {
+				killPID(entry.PID)
+			}
+			continue
+		}
+		idle++
+	}
+
+	if idle < sm.cfg.SessionPoolSize {
+		if err := sm.ensureBrowser(browserType); err != nil {
+			return 0, err
+		}
+	}
+
+	launched := 0
+	for idle < sm.cfg.SessionPoolSize {
+		if err := ctx.Err(); err != nil {
+			return launched, err
+		}
+
+		// Pool runners must outlive this process, so they are not tied to ctx
+		runner, err := launchRunner(context.Background(), sm.cfg.PlaywrightNodePath, LaunchOptions{
+			BrowserType: browserType,
+			Headless:    headless,
+		})
+		if err != nil {
+			return launched, err
+		}
+
+		entry := &PoolEntry{
+			ID:          runner.id,
+			BrowserType: browserType,
+			Headless:    headless,
+			PID:         runner.pid,
+			Port:        runner.port,
+			Browser:     runner.version,
+			CreatedAt:   time.Now(),
+		}
+		if err := writePoolEntry(key, entry); err != nil {
+			runner.kill()
+			return launched, err
+		}
+
+		idle++
+		launched++
+	}
+
+	return launched, nil
+}
+
+// DrainPool kills every idle runner and empties the pool
+func (sm *SessionManager) DrainPool() int {
+	entries, paths := readPoolEntries("")
+	drained := 0
+	for i, entry := range entries {
+		if os.Remove(paths[i]) != nil {
+			continue
+		}
+		killPID(entry.PID)
+		drained++
+	}
+	return drained
+}
+
+// refillPool starts a detached "session-pool fill" process so the pool is
+// topped up after this (usually short-lived) CLI invocation exits. The fill
+// launches as many runners as are missing, so one per process is enough.
+func (sm *SessionManager) refillPool(browserType string, headless bool) {
+	if !sm.PoolEnabled() {
+		return
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	cmd := exec.Command(executable, "session-pool", "fill",
+		"--browser-type", browserType,
+		"--headless="+strconv.FormatBool(headless),
+	)
+	detachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to refill session pool: %v\n", err)
+		return
+	}
+	_ = cmd.Process.Release()
+}
+
+func (sm *SessionManager) poolEntryExpired(entry *PoolEntry) bool {
+	maxIdle := time.Duration(sm.cfg.SessionPoolIdleSeconds) * time.Second
+	return maxIdle > 0 && time.Since(entry.CreatedAt) > maxIdle
+}
+
+// readPoolEntries returns the entries for key (all keys when empty), oldest first
+func readPoolEntries(key string) ([]*PoolEntry, []string) {
+	dirEntries, err := os.ReadDir(poolDir())
+	if err != nil {
+		return nil, nil
+	}
+
+	type loaded struct {
+		entry *PoolEntry
+		path  string
+	}
+	var found []loaded
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		if key != "" && !strings.HasPrefix(name, key+"_") {
+			continue
+		}
+
+		path := filepath.Join(poolDir(), name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry PoolEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		found = append(found, loaded{entry: &entry, path: path})
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].entry.CreatedAt.Before(found[j].entry.CreatedAt)
+	})
+
+	entries := make([]*PoolEntry, len(found))
+	paths := make([]string, len(found))
+	for i, item := range found {
+		entries[i] = item.entry
+		paths[i] = item.path
+	}
+	return entries, paths
+}
+
+// writePoolEntry publishes an entry atomically so claimers never see a
+// partially written file
+func writePoolEntry(key string, entry *PoolEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pool entry: %w", err)
+	}
+
+	path := filepath.Join(poolDir(), key+"_"+entry.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pool entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to publish pool entry: %w", err)
+	}
+	return nil
+}
+
+// runnerAlive pings a runner over its IPC port
+func runnerAlive(port int) bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), time.Second)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write([]byte("{\"command\":\"ping\"}\n")); err != nil {
+		return false
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return false
+	}
+	var resp struct {
+		Success bool `json:"success"`
+	}
+	return json.Unmarshal(line, &resp) == nil && resp.Success
+}
+
+func killPID(pid int) {
+	if process, err := os.FindProcess(pid); err == nil {
+		_ = process.Kill()
+	}
+}
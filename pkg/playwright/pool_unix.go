@@ -0,0 +1,14 @@
+//go:build !windows
+
+package playwright
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachProcess starts the command in its own process group so it survives
+// the parent's terminal session
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
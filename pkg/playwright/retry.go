@@ -0,0 +1,178 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/oa-plugins/webauto/pkg/ipc"
+	"github.com/oa-plugins/webauto/pkg/ratelimit"
+	"github.com/oa-plugins/webauto/pkg/response"
+)
+
+// maxRetryBackoff caps the exponential backoff between attempts
+const maxRetryBackoff = 30 * time.Second
+
+// idempotentCommands lists runner commands that can be repeated without
+// changing the page beyond what a single successful run would do
+var idempotentCommands = map[string]bool{
+	"navigate":      true,
+	"type":          true,
+	"get-text":      true,
+	"get-attribute": true,
+	"wait":          true,
+	"query-all":     true,
+	"get-html":      true,
+	"screenshot":    true,
+	"pdf":           true,
+	"ping":          true,
+}
+
+// transientErrorCodes are retried for idempotent commands without opt-in
+var transientErrorCodes = map[string]bool{
+	response.ErrTimeoutExceeded:       true,
+	response.ErrBrowserConnectionLost: true,
+	response.ErrRateLimitExceeded:     true,
+}
+
+// RetryPolicy controls how SendCommandWithRetry repeats failed commands
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt (0 = no retries)
+	MaxRetries int
+
+	// Backoff is the delay before the first retry; it doubles on every retry
+	Backoff time.Duration
+
+	// RetryOn holds error codes the user opted into. Commands failing with
+	// these codes are retried even when they are not idempotent.
+	RetryOn map[string]bool
+}
+
+// ShouldRetry reports whether a command that failed with code may be repeated
+func (p RetryPolicy) ShouldRetry(command, code string) bool {
+	if p.RetryOn[code] {
+		return true
+	}
+	return idempotentCommands[command] && transientErrorCodes[code]
+}
+
+// backoff returns the delay before the given retry (1-based)
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+// ExecutionReport records every attempt made for a single command
+type ExecutionReport struct {
+	Attempts int
+	Errors   []response.AttemptError
+}
+
+// SendCommandWithRetry sends a command and retries failed attempts according
+// to policy. Both transport errors and unsuccessful runner responses count as
+// failed attempts. The returned response/error are those of the last attempt.
+// A streamed command is not retried once it has passed a chunk on, since a
+// new attempt would hand the same elements over again.
+func (sm *SessionManager) SendCommandWithRetry(ctx context.Context, sessionID string, command map[string]interface{}, policy RetryPolicy) (*ipc.NodeResponse, *ExecutionReport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	commandName, _ := command["command"].(string)
+	report := &ExecutionReport{}
+
+	var streamed atomic.Bool
+	if handler := chunkHandlerFrom(ctx); handler != nil {
+		ctx = WithChunkHandler(ctx, func(data map[string]interface{}) {
+			streamed.Store(true)
+			handler(data)
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptStart := time.Now()
+		resp, err := sm.SendCommand(ctx, sessionID, command)
+		report.Attempts = attempt
+
+		if err == nil && resp.Success {
+			return resp, report, nil
+		}
+
+		code, message := ClassifyFailure(resp, err)
+		report.Errors = append(report.Errors, response.AttemptError{
+			Attempt:    attempt,
+			Command:    commandName,
+			Code:       code,
+			Message:    message,
+			DurationMs: time.Since(attemptStart).Milliseconds(),
+		})
+
+		if attempt > policy.MaxRetries || streamed.Load() || !policy.ShouldRetry(commandName, code) {
+			return resp, report, err
+		}
+
+		delay := policy.backoff(attempt)
+		var exceeded *ratelimit.ExceededError
+		if errors.As(err, &exceeded) && exceeded.RetryAfter > delay {
+			delay = exceeded.RetryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, report, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// ClassifyFailure maps a failed SendCommand result to an error code and message
+func ClassifyFailure(resp *ipc.NodeResponse, err error) (string, string) {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, ratelimit.ErrLimitExceeded):
+			return response.ErrRateLimitExceeded, err.Error()
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+			return response.ErrTimeoutExceeded, err.Error()
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return response.ErrTimeoutExceeded, err.Error()
+		case errors.Is(err, errSessionClosed), errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed), errors.As(err, &netErr):
+			return response.ErrBrowserConnectionLost, err.Error()
+		case strings.HasPrefix(err.Error(), "session not found"):
+			return response.ErrSessionNotFound, err.Error()
+		default:
+			return response.ErrScriptExecutionFailed, err.Error()
+		}
+	}
+
+	if resp == nil {
+		return response.ErrScriptExecutionFailed, "empty response"
+	}
+
+	message := resp.Error
+	switch {
+	case strings.Contains(message, "Timeout") && strings.Contains(message, "exceeded"):
+		return response.ErrTimeoutExceeded, message
+	case isBotDetection(message):
+		return response.ErrBotDetectionTriggered, message
+	case strings.HasPrefix(message, "Element not found"), strings.HasPrefix(message, "No elements found"):
+		return response.ErrElementNotFound, message
+	case strings.Contains(message, "net::ERR_"), strings.Contains(message, "NS_ERROR_"):
+		return response.ErrPageNavigationFailed, message
+	case strings.Contains(message, "Target closed"), strings.Contains(message, "has been closed"):
+		return response.ErrBrowserConnectionLost, message
+	default:
+		return response.ErrScriptExecutionFailed, message
+	}
+}
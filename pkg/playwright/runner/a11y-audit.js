@@ -0,0 +1,397 @@
+// Bundled accessibility rule set used by the `a11y-audit` runner command.
+//
+// auditPage is serialized and executed inside the page by page.evaluate, so
+// it must stay self-contained: no closures over module scope, no imports.
+// All checks run offline against whatever document is loaded (including
+// file:// fixtures).
+
+function auditPage({ rules, selector }) {
+  const MAX_NODES_PER_RULE = 50;
+  const root = selector ? document.querySelector(selector) : document.body;
+  if (!root) {
+    throw new Error(`Element not found: ${selector}`);
+  }
+
+  const normalize = (value) => (value || '').replace(/\s+/g, ' ').trim();
+
+  const uniqueId = (element) =>
+    element.id && document.querySelectorAll(`#${CSS.escape(element.id)}`).length === 1
+      ? `#${CSS.escape(element.id)}`
+      : null;
+
+  const cssPath = (element) => {
+    const parts = [];
+    let node = element;
+    while (node && node.nodeType === Node.ELEMENT_NODE && node !== document.documentElement) {
+      const id = uniqueId(node);
+      if (id) {
+        parts.unshift(id);
+        break;
+      }
+      let part = node.tagName.toLowerCase();
+      const parent = node.parentElement;
+      if (parent) {
+        const siblings = Array.from(parent.children).filter((child) => child.tagName === node.tagName);
+        if (siblings.length > 1) {
+          part += `:nth-of-type(${siblings.indexOf(node) + 1})`;
+        }
+      }
+      parts.unshift(part);
+      node = parent;
+    }
+    return parts.join(' > ');
+  };
+
+  const snippet = (element) => {
+    const html = element.outerHTML || '';
+    return html.length > 200 ? `${html.slice(0, 200)}…` : html;
+  };
+
+  const isHidden = (element) => {
+    for (let node = element; node && node.nodeType === Node.ELEMENT_NODE; node = node.parentElement) {
+      if (node.getAttribute('aria-hidden') === 'true' || node.hidden) {
+        return true;
+      }
+      const style = getComputedStyle(node);
+      if (style.display === 'none' || style.visibility === 'hidden') {
+        return true;
+      }
+    }
+    return false;
+  };
+
+  const textOf = (element) => {
+    let text = '';
+    for (const node of element.childNodes) {
+      if (node.nodeType === Node.TEXT_NODE) {
+        text += node.textContent;
+      } else if (node.nodeType === Node.ELEMENT_NODE && !isHidden(node)) {
+        if (node.tagName === 'IMG') {
+          text += ` ${node.getAttribute('alt') || ''} `;
+        } else {
+          text += ` ${node.getAttribute('aria-label') || textOf(node)} `;
+        }
+      }
+    }
+    return normalize(text);
+  };
+
+  // Simplified accessible name computation (aria-labelledby, aria-label,
+  // associated <label>, wrapping <label>, title, content)
+  const accessibleName = (element, { useContent = false, usePlaceholder = false } = {}) => {
+    const labelledBy = element.getAttribute('aria-labelledby');
+    if (labelledBy) {
+      const name = normalize(
+        labelledBy
+          .split(/\s+/)
+          .map((id) => document.getElementById(id))
+          .filter(Boolean)
+          .map((node) => node.textContent)
+          .join(' '),
+      );
+      if (name) {
+        return name;
+      }
+    }
+
+    const ariaLabel = normalize(element.getAttribute('aria-label'));
+    if (ariaLabel) {
+      return ariaLabel;
+    }
+
+    if (element.labels && element.labels.length > 0) {
+      const name = normalize(Array.from(element.labels).map((label) => label.textContent).join(' '));
+      if (name) {
+        return name;
+      }
+    }
+
+    if (useContent) {
+      const content = textOf(element);
+      if (content) {
+        return content;
+      }
+    }
+
+    const title = normalize(element.getAttribute('title'));
+    if (title) {
+      return title;
+    }
+
+    if (usePlaceholder) {
+      return normalize(element.getAttribute('placeholder'));
+    }
+
+    return '';
+  };
+
+  const parseColor = (value) => {
+    const match = (value || '').match(/rgba?\(([^)]+)\)/);
+    if (!match) {
+      return null;
+    }
+    const parts = match[1].split(/[\s,/]+/).filter(Boolean).map(Number);
+    return { r: parts[0], g: parts[1], b: parts[2], a: parts.length > 3 ? parts[3] : 1 };
+  };
+
+  const blend = (top, bottom) => ({
+    r: top.r * top.a + bottom.r * (1 - top.a),
+    g: top.g * top.a + bottom.g * (1 - top.a),
+    b: top.b * top.a + bottom.b * (1 - top.a),
+    a: 1,
+  });
+
+  const backgroundOf = (element) => {
+    const layers = [];
+    for (let node = element; node && node.nodeType === Node.ELEMENT_NODE; node = node.parentElement) {
+      const color = parseColor(getComputedStyle(node).backgroundColor);
+      if (color && color.a > 0) {
+        layers.push(color);
+        if (color.a >= 1) {
+          break;
+        }
+      }
+    }
+    return layers.reduceRight((acc, layer) => blend(layer, acc), { r: 255, g: 255, b: 255, a: 1 });
+  };
+
+  const luminance = ({ r, g, b }) => {
+    const channel = (value) => {
+      const c = value / 255;
+      return c <= 0.03928 ? c / 12.92 : ((c + 0.055) / 1.055) ** 2.4;
+    };
+    return 0.2126 * channel(r) + 0.7152 * channel(g) + 0.0722 * channel(b);
+  };
+
+  const contrastRatio = (a, b) => {
+    const l1 = luminance(a);
+    const l2 = luminance(b);
+    return (Math.max(l1, l2) + 0.05) / (Math.min(l1, l2) + 0.05);
+  };
+
+  const visible = (list) => Array.from(list).filter((element) => !isHidden(element));
+  const within = (query) => visible(root.querySelectorAll(query));
+
+  const ruleSet = {
+    'image-alt': {
+      impact: 'critical',
+      description: 'Images must have alternate text',
+      help: 'Add an alt attribute (alt="" for decorative images) or aria-label',
+      check: () =>
+        within('img, input[type="image"], area[href]')
+          .filter((element) => {
+            const role = element.getAttribute('role');
+            if (role === 'presentation' || role === 'none') {
+              return false;
+            }
+            return !element.hasAttribute('alt') && !accessibleName(element);
+          })
+          .map((element) => ({ element, message: 'Element has no alt attribute or accessible name' })),
+    },
+
+    label: {
+      impact: 'critical',
+      description: 'Form controls must have labels',
+      help: 'Associate a <label for>, wrap the control in a <label>, or add aria-label/aria-labelledby',
+      check: () =>
+        within('input, select, textarea')
+          .filter((element) => {
+            const type = (element.getAttribute('type') || 'text').toLowerCase();
+            if (['hidden', 'submit', 'reset', 'button', 'image'].includes(type)) {
+              return false;
+            }
+            return !accessibleName(element);
+          })
+          .map((element) => ({
+            element,
+            message: element.getAttribute('placeholder')
+              ? 'Control is only described by its placeholder'
+              : 'Control has no accessible name',
+          })),
+    },
+
+    'button-name': {
+      impact: 'critical',
+      description: 'Buttons must have discernible text',
+      help: 'Give the button text content, a value, or aria-label',
+      check: () =>
+        within('button, [role="button"], input[type="submit"], input[type="button"], input[type="reset"]')
+          .filter((element) => {
+            if (element.tagName === 'INPUT') {
+              const type = element.getAttribute('type');
+              return !normalize(element.value) && type !== 'submit' && type !== 'reset' && !accessibleName(element);
+            }
+            return !accessibleName(element, { useContent: true });
+          })
+          .map((element) => ({ element, message: 'Button has no accessible name' })),
+    },
+
+    'link-name': {
+      impact: 'serious',
+      description: 'Links must have discernible text',
+      help: 'Give the link text content, an image with alt text, or aria-label',
+      check: () =>
+        within('a[href]')
+          .filter((element) => !accessibleName(element, { useContent: true }))
+          .map((element) => ({ element, message: 'Link has no accessible name' })),
+    },
+
+    'color-contrast': {
+      impact: 'serious',
+      description: 'Text must have sufficient color contrast (WCAG AA)',
+      help: 'Use at least 4.5:1 for normal text and 3:1 for large text',
+      check: () => {
+        const failures = [];
+        const candidates = within('*').filter((element) =>
+          Array.from(element.childNodes).some(
+            (node) => node.nodeType === Node.TEXT_NODE && normalize(node.textContent),
+          ),
+        );
+
+        for (const element of candidates.slice(0, 2000)) {
+          const style = getComputedStyle(element);
+          const foreground = parseColor(style.color);
+          if (!foreground) {
+            continue;
+          }
+          const background = backgroundOf(element);
+          const ratio = contrastRatio(blend(foreground, background), background);
+          const fontSize = parseFloat(style.fontSize);
+          const bold = Number(style.fontWeight) >= 700 || style.fontWeight === 'bold';
+          const large = fontSize >= 24 || (bold && fontSize >= 18.66);
+          const required = large ? 3 : 4.5;
+          if (ratio < required) {
+            failures.push({
+              element,
+              message: `Contrast ratio ${ratio.toFixed(2)}:1 is below ${required}:1`,
+              data: { contrast_ratio: Math.round(ratio * 100) / 100, required_ratio: required },
+            });
+          }
+        }
+        return failures;
+      },
+    },
+
+    'heading-order': {
+      impact: 'moderate',
+      description: 'Heading levels should only increase by one',
+      help: 'Do not skip heading levels (e.g. h2 followed by h4)',
+      check: () => {
+        const failures = [];
+        let previous = 0;
+        for (const heading of within('h1, h2, h3, h4, h5, h6')) {
+          const level = Number(heading.tagName.slice(1));
+          if (previous > 0 && level > previous + 1) {
+            failures.push({ element: heading, message: `Heading level jumps from h${previous} to h${level}` });
+          }
+          previous = level;
+        }
+        return failures;
+      },
+    },
+
+    'page-has-heading-one': {
+      impact: 'moderate',
+      description: 'Page should contain a level-one heading',
+      help: 'Add an <h1> describing the page',
+      check: () =>
+        within('h1, [role="heading"][aria-level="1"]').length === 0
+          ? [{ element: root, message: 'No level-one heading found' }]
+          : [],
+    },
+
+    'landmark-one-main': {
+      impact: 'moderate',
+      description: 'Page should have exactly one main landmark',
+      help: 'Wrap the primary content in a single <main> element',
+      check: () => {
+        const mains = within('main, [role="main"]');
+        if (mains.length === 0) {
+          return [{ element: root, message: 'No main landmark found' }];
+        }
+        return mains.slice(1).map((element) => ({ element, message: 'Duplicate main landmark' }));
+      },
+    },
+
+    'landmark-unique': {
+      impact: 'minor',
+      description: 'Repeated landmarks of the same type should have distinct labels',
+      help: 'Add aria-label to distinguish multiple nav/aside/region landmarks',
+      check: () => {
+        const failures = [];
+        const groups = {};
+        for (const element of within('nav, aside, [role="navigation"], [role="complementary"], [role="region"]')) {
+          const role = element.getAttribute('role') || element.tagName.toLowerCase();
+          const key = `${role}|${accessibleName(element)}`;
+          groups[key] = (groups[key] || 0) + 1;
+          if (groups[key] > 1) {
+            failures.push({ element, message: `Landmark "${role}" is not uniquely labelled` });
+          }
+        }
+        return failures;
+      },
+    },
+
+    'html-has-lang': {
+      impact: 'serious',
+      description: 'The <html> element must have a lang attribute',
+      help: 'Add lang="ko" (or the page language) to <html>',
+      check: () =>
+        normalize(document.documentElement.getAttribute('lang'))
+          ? []
+          : [{ element: document.documentElement, message: 'Missing lang attribute' }],
+    },
+
+    'document-title': {
+      impact: 'serious',
+      description: 'Documents must have a <title>',
+      help: 'Add a descriptive <title> element',
+      check: () =>
+        normalize(document.title) ? [] : [{ element: document.documentElement, message: 'Document has no title' }],
+    },
+  };
+
+  const selected = Array.isArray(rules) && rules.length > 0 ? rules : Object.keys(ruleSet);
+  const unknown = selected.filter((id) => !ruleSet[id]);
+  if (unknown.length > 0) {
+    throw new Error(`Unknown accessibility rules: ${unknown.join(', ')}`);
+  }
+
+  const violations = [];
+  const passes = [];
+  const summary = { critical: 0, serious: 0, moderate: 0, minor: 0 };
+
+  for (const id of selected) {
+    const rule = ruleSet[id];
+    const failures = rule.check();
+    if (failures.length === 0) {
+      passes.push(id);
+      continue;
+    }
+
+    summary[rule.impact] += failures.length;
+    violations.push({
+      rule: id,
+      impact: rule.impact,
+      description: rule.description,
+      help: rule.help,
+      node_count: failures.length,
+      nodes: failures.slice(0, MAX_NODES_PER_RULE).map(({ element, message, data }) => ({
+        selector: cssPath(element),
+        html: snippet(element),
+        message,
+        ...(data || {}),
+      })),
+    });
+  }
+
+  return {
+    rules_checked: selected,
+    passes,
+    violations,
+    violation_count: violations.reduce((total, violation) => total + violation.node_count, 0),
+    summary,
+  };
+}
+
+module.exports = { auditPage };
@@ -0,0 +1,106 @@
+// Schema-driven extraction for the page-extract command. extractWithSchema
+// runs inside the page via page.evaluate, so it must stay self-contained.
+
+function extractWithSchema(schema) {
+  const normalize = (value) => (value || '').replace(/\s+/g, ' ').trim();
+  const missing = {};
+
+  function transform(value, name) {
+    if (value === null || value === undefined || !name) {
+      return value;
+    }
+    const text = String(value);
+    switch (name) {
+      case 'trim':
+        return text.trim();
+      case 'lower':
+        return text.toLowerCase();
+      case 'upper':
+        return text.toUpperCase();
+      case 'number': {
+        const parsed = parseFloat(text.replace(/[^0-9.\-]/g, ''));
+        return Number.isNaN(parsed) ? null : parsed;
+      }
+      case 'integer': {
+        const parsed = parseInt(text.replace(/[^0-9\-]/g, ''), 10);
+        return Number.isNaN(parsed) ? null : parsed;
+      }
+      case 'boolean':
+        return !['', '0', 'false', 'no', 'off'].includes(text.trim().toLowerCase());
+      case 'url':
+        try {
+          return new URL(text, document.baseURI).href;
+        } catch (error) {
+          return null;
+        }
+      default:
+        return value;
+    }
+  }
+
+  function readValue(element, field) {
+    let value;
+    switch (field.source || 'text') {
+      case 'attribute':
+        value = element.getAttribute(field.attribute);
+        break;
+      case 'property':
+        value = element[field.property];
+        break;
+      case 'html':
+        value = element.innerHTML;
+        break;
+      default:
+        value = normalize(element.innerText !== undefined ? element.innerText : element.textContent);
+    }
+
+    if (field.regex && value !== null && value !== undefined) {
+      const match = String(value).match(new RegExp(field.regex));
+      value = match ? (match.length > 1 ? match[1] : match[0]) : null;
+    }
+
+    value = transform(value, field.transform);
+    if ((value === null || value === undefined) && field.default !== undefined) {
+      return field.default;
+    }
+    return value === undefined ? null : value;
+  }
+
+  function extractField(scope, field, path) {
+    const targets = field.selector ? Array.from(scope.querySelectorAll(field.selector)) : [scope];
+    const read = (element) => (field.fields ? extractRecord(element, field.fields, path) : readValue(element, field));
+
+    if (field.list) {
+      const limited = field.limit > 0 ? targets.slice(0, field.limit) : targets;
+      return limited.map(read);
+    }
+
+    if (targets.length === 0) {
+      missing[path] = (missing[path] || 0) + 1;
+      return field.default !== undefined ? field.default : null;
+    }
+    return read(targets[0]);
+  }
+
+  function extractRecord(scope, fields, prefix) {
+    const record = {};
+    Object.keys(fields).forEach((name) => {
+      const path = prefix ? `${prefix}.${name}` : name;
+      record[name] = extractField(scope, fields[name], path);
+    });
+    return record;
+  }
+
+  const roots = schema.root ? Array.from(document.querySelectorAll(schema.root)) : [document.documentElement];
+  const items = schema.limit > 0 ? roots.slice(0, schema.limit) : roots;
+  const records = items.map((item) => extractRecord(item, schema.fields, ''));
+
+  return {
+    records,
+    record_count: records.length,
+    root_count: roots.length,
+    missing_fields: missing,
+  };
+}
+
+module.exports = { extractWithSchema };
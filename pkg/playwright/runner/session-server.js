@@ -1,7 +1,13 @@
 const net = require('net');
 const { chromium, firefox, webkit } = require('playwright');
+const { auditPage } = require('./a11y-audit');
+const { extractTableGrid } = require('./table-extract');
+const { extractWithSchema } = require('./schema-extract');
+const { webStorage, dumpIndexedDB } = require('./web-storage');
 
 const DEFAULT_TIMEOUT = 30_000;
+const DEFAULT_CHUNK_SIZE = 500;
+const DEFAULT_CONTEXT_ID = 'default';
 
 function parseConfig() {
   const raw = process.env.WEBAUTO_RUNNER_CONFIG;
@@ -18,6 +24,7 @@ function parseConfig() {
     return {
       browserType: parsed.browserType,
       headless: parsed.headless !== undefined ? Boolean(parsed.headless) : true,
+      shared: Boolean(parsed.shared),
     };
   } catch (error) {
     throw new Error(`Failed to parse runner config: ${error.message}`);
@@ -37,22 +44,500 @@ function resolveBrowserLauncher(browserType) {
   }
 }
 
-function toCommandError(error) {
+function toCommandError(error, extra = {}) {
   return JSON.stringify({
     success: false,
     error: error instanceof Error ? error.message : String(error),
+    ...extra,
   });
 }
 
-async function handleCommand(page, command) {
+// Converts "/pattern/flags" into a RegExp; any other string is matched as text.
+function toTextMatcher(value) {
+  if (typeof value !== 'string') {
+    return value;
+  }
+
+  const match = value.match(/^\/(.+)\/([dgimsuy]*)$/);
+  if (match) {
+    return new RegExp(match[1], match[2]);
+  }
+
+  return value;
+}
+
+// Builds a Playwright locator from a command. `command.locator` may combine a
+// CSS/XPath selector (used as the search scope) with one getBy* engine, filters
+// and an index; commands without it fall back to `command.selector`.
+function resolveLocator(page, command) {
+  const spec = command.locator || { selector: command.selector };
+  const exact = Boolean(spec.exact);
+
+  let scope = page;
+  if (spec.selector) {
+    scope = page.locator(spec.selector);
+  }
+
+  let locator;
+  if (spec.role) {
+    const options = {};
+    if (spec.name !== undefined) {
+      options.name = toTextMatcher(spec.name);
+      options.exact = exact;
+    }
+    locator = scope.getByRole(spec.role, options);
+  } else if (spec.label) {
+    locator = scope.getByLabel(toTextMatcher(spec.label), { exact });
+  } else if (spec.text) {
+    locator = scope.getByText(toTextMatcher(spec.text), { exact });
+  } else if (spec.placeholder) {
+    locator = scope.getByPlaceholder(toTextMatcher(spec.placeholder), { exact });
+  } else if (spec.testId) {
+    locator = scope.getByTestId(spec.testId);
+  } else if (spec.selector) {
+    locator = scope;
+  } else {
+    throw new Error('Locator requires a selector or a getBy option');
+  }
+
+  if (spec.hasText || spec.has) {
+    const filter = {};
+    if (spec.hasText) {
+      filter.hasText = toTextMatcher(spec.hasText);
+    }
+    if (spec.has) {
+      filter.has = page.locator(spec.has);
+    }
+    locator = locator.filter(filter);
+  }
+
+  if (typeof spec.nth === 'number') {
+    locator = locator.nth(spec.nth);
+  }
+
+  return locator;
+}
+
+const FINGERPRINT_POLL_MS = 250;
+const FINGERPRINT_MIN_SCORE = 0.5;
+const FINGERPRINT_ATTRIBUTES = [
+  'id',
+  'name',
+  'type',
+  'class',
+  'placeholder',
+  'aria-label',
+  'role',
+  'href',
+  'title',
+  'for',
+  'data-testid',
+];
+
+// Runs in the page: records what an element looks like so it can be found
+// again after the site's markup changes.
+function captureFingerprint(element, attributeNames) {
+  const rect = element.getBoundingClientRect();
+  const attributes = {};
+  for (const name of attributeNames) {
+    const value = element.getAttribute(name);
+    if (value !== null) {
+      attributes[name] = value;
+    }
+  }
+
+  return {
+    tag: element.tagName.toLowerCase(),
+    text: (element.textContent || '').replace(/\s+/g, ' ').trim().slice(0, 200),
+    attributes,
+    position: {
+      x: Math.round(rect.x),
+      y: Math.round(rect.y),
+      width: Math.round(rect.width),
+      height: Math.round(rect.height),
+    },
+  };
+}
+
+// Runs in the page: scores every rendered element against a fingerprint and
+// returns a unique CSS path to the best match above minScore.
+function findByFingerprint({ fingerprint, minScore }) {
+  const normalize = (value) => (value || '').replace(/\s+/g, ' ').trim().toLowerCase();
+  const similarity = (a, b) => {
+    const left = normalize(a);
+    const right = normalize(b);
+    if (left === right) {
+      return 1;
+    }
+    if (!left || !right) {
+      return 0;
+    }
+    const leftTokens = new Set(left.split(/[\s_-]+/).filter(Boolean));
+    const rightTokens = new Set(right.split(/[\s_-]+/).filter(Boolean));
+    let shared = 0;
+    for (const token of leftTokens) {
+      if (rightTokens.has(token)) {
+        shared += 1;
+      }
+    }
+    return (2 * shared) / (leftTokens.size + rightTokens.size);
+  };
+  const uniqueId = (element) =>
+    element.id && document.querySelectorAll(`#${CSS.escape(element.id)}`).length === 1
+      ? `#${CSS.escape(element.id)}`
+      : null;
+  const cssPath = (element) => {
+    const parts = [];
+    let node = element;
+    while (node && node.nodeType === Node.ELEMENT_NODE && node !== document.documentElement) {
+      const id = uniqueId(node);
+      if (id) {
+        parts.unshift(id);
+        break;
+      }
+      let part = node.tagName.toLowerCase();
+      const parent = node.parentElement;
+      if (parent) {
+        const siblings = Array.from(parent.children).filter((child) => child.tagName === node.tagName);
+        if (siblings.length > 1) {
+          part += `:nth-of-type(${siblings.indexOf(node) + 1})`;
+        }
+      }
+      parts.unshift(part);
+      node = parent;
+    }
+    return parts.join(' > ');
+  };
+
+  const expectedAttributes = fingerprint.attributes || {};
+  const attributeNames = Object.keys(expectedAttributes);
+  const position = fingerprint.position;
+
+  let best = null;
+  for (const element of document.querySelectorAll('body *')) {
+    const rect = element.getBoundingClientRect();
+    if (rect.width === 0 && rect.height === 0) {
+      continue;
+    }
+
+    let score = 0;
+    let weight = 0;
+
+    score += element.tagName.toLowerCase() === fingerprint.tag ? 0.2 : 0;
+    weight += 0.2;
+
+    if (fingerprint.text) {
+      score += 0.3 * similarity((element.textContent || '').slice(0, 400), fingerprint.text);
+      weight += 0.3;
+    }
+
+    if (attributeNames.length > 0) {
+      let matched = 0;
+      for (const name of attributeNames) {
+        matched += similarity(element.getAttribute(name), expectedAttributes[name]);
+      }
+      score += (0.35 * matched) / attributeNames.length;
+      weight += 0.35;
+    }
+
+    if (position) {
+      const distance = Math.hypot(rect.x - position.x, rect.y - position.y);
+      score += 0.15 * Math.max(0, 1 - distance / 500);
+      weight += 0.15;
+    }
+
+    const normalized = score / weight;
+    if (!best || normalized > best.score) {
+      best = { element, score: normalized };
+    }
+  }
+
+  if (!best || best.score < minScore) {
+    return null;
+  }
+
+  return { selector: cssPath(best.element), score: Math.round(best.score * 1000) / 1000 };
+}
+
+async function firstMatchingCandidate(locators, specs) {
+  for (let i = 0; i < locators.length; i += 1) {
+    if ((await locators[i].count()) > 0) {
+      const { description, ...locator } = specs[i];
+      return {
+        element: locators[i],
+        selector: description || specs[i].selector,
+        locator,
+        strategy: i === 0 ? 'primary' : 'fallback',
+        candidate_index: i,
+      };
+    }
+  }
+  return null;
+}
+
+// Resolves the element for a command with self-healing: ranked candidate
+// locators are tried in order, then the page is scored against the stored
+// fingerprint. Returns the locator and a description of what matched.
+//
+// The caller's timeout is split across the stages: the candidates are awaited
+// together for their share, and the fingerprint, when there is one, gets the
+// rest as one more candidate.
+async function locateElement(page, command, timeout) {
+  const hasCandidates = Array.isArray(command.candidates) && command.candidates.length > 0;
+  if (!hasCandidates && !command.fingerprint) {
+    return { element: resolveLocator(page, command), match: null };
+  }
+
+  const specs = hasCandidates ? command.candidates : [command.locator || { selector: command.selector }];
+  const locators = specs.map((spec) => resolveLocator(page, { locator: spec }));
+  const deadline = Date.now() + timeout;
+  const candidateWait = command.fingerprint ? Math.floor((timeout * specs.length) / (specs.length + 1)) : timeout;
+
+  let match = await firstMatchingCandidate(locators, specs);
+  if (!match) {
+    // Give dynamic content a chance to render before declaring every candidate broken
+    const combined = locators.slice(1).reduce((acc, locator) => acc.or(locator), locators[0]);
+    try {
+      await combined.first().waitFor({ state: 'attached', timeout: candidateWait });
+      match = await firstMatchingCandidate(locators, specs);
+    } catch (error) {
+      // Fall through to fingerprint matching
+    }
+  }
+
+  while (!match && command.fingerprint) {
+    const found = await page.evaluate(findByFingerprint, {
+      fingerprint: command.fingerprint,
+      minScore: FINGERPRINT_MIN_SCORE,
+    });
+    if (found) {
+      match = {
+        element: page.locator(found.selector),
+        selector: found.selector,
+        locator: { selector: found.selector },
+        strategy: 'fingerprint',
+        score: found.score,
+      };
+      break;
+    }
+    const remaining = deadline - Date.now();
+    if (remaining <= 0) {
+      break;
+    }
+    await page.waitForTimeout(Math.min(FINGERPRINT_POLL_MS, remaining));
+  }
+
+  if (!match) {
+    throw new Error(`Element not found: ${command.selector}`);
+  }
+
+  const { element, ...details } = match;
+  if (command.captureFingerprint) {
+    details.fingerprint = await element.first().evaluate(captureFingerprint, FINGERPRINT_ATTRIBUTES);
+  }
+
+  return { element, match: details };
+}
+
+// Reads text and attributes of the matches from start up to end (0 for all)
+// in a single evaluateAll pass instead of one round trip per element and
+// property
+async function collectElements(locator, options) {
+  return locator.evaluateAll((elements, opts) => {
+    const end = opts.end > 0 ? Math.min(opts.end, elements.length) : elements.length;
+    const items = elements.slice(opts.start, end).map((element, offset) => {
+      const item = { index: opts.start + offset };
+      if (opts.getText) {
+        let text = element.textContent;
+        if (opts.trim && text) {
+          text = text.replace(/^\s+|\s+$/g, '').replace(/\s+/g, ' ');
+        }
+        item.text = text;
+      }
+      if (opts.attributeNames.length > 0) {
+        item.attributes = {};
+        opts.attributeNames.forEach((name) => {
+          item.attributes[name] = element.getAttribute(name);
+        });
+      }
+      return item;
+    });
+    return { count: elements.length, items };
+  }, options);
+}
+
+// Snapshot used to detect that a pagination step produced new content
+async function pageSignature(page, itemSelector) {
+  return page.evaluate((selector) => {
+    const items = selector ? document.querySelectorAll(selector) : [];
+    const text = (element) => (element ? (element.textContent || '').trim().slice(0, 200) : '');
+    return JSON.stringify({
+      url: window.location.href,
+      count: items.length,
+      first: text(items[0]),
+      last: text(items[items.length - 1]),
+      height: document.body ? document.body.scrollHeight : 0,
+    });
+  }, itemSelector || null);
+}
+
+async function waitForSignatureChange(page, itemSelector, before, timeout) {
+  const deadline = Date.now() + timeout;
+  while (Date.now() < deadline) {
+    // The page may be navigating; treat evaluation errors as "not yet"
+    const current = await pageSignature(page, itemSelector).catch(() => null);
+    if (current !== null && current !== before) {
+      return true;
+    }
+    await page.waitForTimeout(200);
+  }
+  return false;
+}
+
+function countAccessibilityNodes(node) {
+  if (!node) {
+    return 0;
+  }
+  return 1 + (node.children || []).reduce((total, child) => total + countAccessibilityNodes(child), 0);
+}
+
+const BOT_BLOCK_STATUSES = new Set([403, 429, 503]);
+const BOT_CHALLENGE_TITLE = /just a moment|attention required|access denied|pardon our interruption|are you a robot|verify you are human/i;
+const BOT_CHALLENGE_SELECTORS = [
+  'iframe[src*="challenges.cloudflare.com"]',
+  '#challenge-form',
+  '#cf-challenge-running',
+  '#px-captcha',
+  'iframe[src*="captcha-delivery.com"]',
+];
+
+// Returns why a navigation looks blocked by bot protection, or null. Only a
+// blocking HTTP status served with a known interstitial (a challenge page
+// title or challenge widget) counts: CAPTCHA widgets and "access denied"
+// titles also appear on ordinary login and error pages.
+async function detectBotChallenge(page, navigation) {
+  const status = navigation ? navigation.status() : 0;
+  if (!BOT_BLOCK_STATUSES.has(status)) {
+    return null;
+  }
+
+  const title = await page.title().catch(() => '');
+  if (BOT_CHALLENGE_TITLE.test(title)) {
+    return `HTTP ${status}, challenge page "${title}"`;
+  }
+
+  for (const selector of BOT_CHALLENGE_SELECTORS) {
+    if ((await page.locator(selector).count().catch(() => 0)) > 0) {
+      return `HTTP ${status}, challenge element ${selector}`;
+    }
+  }
+  return null;
+}
+
+// Runs fn with a page showing origin: the session's page when it is already
+// there (or no origin is given), otherwise a temporary page in the same
+// context whose navigation is answered locally, so the origin's storage is
+// reachable without loading the site. sessionStorage is per tab and only
+// reachable on the session's page.
+async function withOriginPage(page, origin, options, fn) {
+  const current = /^https?:/.test(page.url()) ? new URL(page.url()).origin : null;
+  let target = current;
+  if (origin) {
+    target = new URL(origin).origin;
+    if (!/^https?:/.test(target)) {
+      throw new Error(`Invalid origin: ${origin} (use http(s)://host[:port])`);
+    }
+  }
+  if (!target) {
+    throw new Error('The page has no web origin; navigate first or pass an origin');
+  }
+  if (target === current) {
+    return fn(page);
+  }
+  if (options.tabOnly) {
+    throw new Error(`sessionStorage is per tab; navigate the session to ${target} first`);
+  }
+
+  const temp = await page.context().newPage();
+  try {
+    await temp.route('**/*', (route) =>
+      route.fulfill({ status: 200, contentType: 'text/html', body: '<!DOCTYPE html><title></title>' })
+    );
+    await temp.goto(`${target}/`, { timeout: options.timeout });
+    return await fn(temp);
+  } finally {
+    await temp.close().catch(() => {});
+  }
+}
+
+// Picks the Playwright pointer options (button, clickCount, delay, modifiers,
+// position) a command carries
+function pointerOptions(command) {
+  const options = {};
+  for (const name of ['button', 'clickCount', 'delay', 'modifiers', 'position']) {
+    if (command[name] !== undefined) {
+      options[name] = command[name];
+    }
+  }
+  return options;
+}
+
+// Runs in the page: describes the element at viewport coordinates
+function describeElementAt({ x, y }) {
+  const element = document.elementFromPoint(x, y);
+  if (!element) {
+    return null;
+  }
+  return {
+    tag: element.tagName.toLowerCase(),
+    id: element.id || null,
+    class: element.getAttribute('class'),
+    text: (element.textContent || '').replace(/\s+/g, ' ').trim().slice(0, 100),
+  };
+}
+
+// Describes the element that has keyboard focus, for keyboard commands
+function describeActiveElement() {
+  const element = document.activeElement;
+  if (!element || element === document.body) {
+    return null;
+  }
+  return {
+    tag: element.tagName.toLowerCase(),
+    id: element.id || null,
+    name: element.getAttribute('name'),
+    type: element.getAttribute('type'),
+  };
+}
+
+// Focuses the command's element, if it names one, before a keyboard command
+async function focusTarget(page, command, timeout) {
+  if (!command.locator && !command.selector) {
+    return null;
+  }
+  const { element, match } = await locateElement(page, command, timeout);
+  await element.focus({ timeout });
+  return match;
+}
+
+async function handleCommand(page, command, emit) {
   const timeout = typeof command.timeout === 'number' ? command.timeout : DEFAULT_TIMEOUT;
 
   switch (command.command) {
     case 'navigate': {
-      await page.goto(command.url, {
+      const navigation = await page.goto(command.url, {
         waitUntil: command.waitUntil || 'load',
         timeout,
       });
+      if (command.detectBot) {
+        const signal = await detectBotChallenge(page, navigation);
+        if (signal) {
+          return {
+            success: false,
+            error: `Bot detection triggered: ${signal}`,
+          };
+        }
+      }
       return {
         success: true,
         data: {
@@ -63,13 +548,14 @@ async function handleCommand(page, command) {
     }
 
     case 'click': {
-      const element = page.locator(command.selector);
-      await element.click({ timeout });
+      const { element, match } = await locateElement(page, command, timeout);
+      await element.click({ timeout, ...pointerOptions(command) });
       return {
         success: true,
         data: {
           selector: command.selector,
           clicked: true,
+          selector_match: match,
         },
       };
     }
@@ -91,7 +577,7 @@ async function handleCommand(page, command) {
     }
 
     case 'type': {
-      const element = page.locator(command.selector);
+      const { element, match } = await locateElement(page, command, timeout);
       await element.fill(command.text, { timeout });
       return {
         success: true,
@@ -99,6 +585,9 @@ async function handleCommand(page, command) {
           selector: command.selector,
           text: command.text,
           typed: true,
+          // Read back: input masks and maxlength may change what was typed
+          value: await element.inputValue({ timeout }).catch(() => null),
+          selector_match: match,
         },
       };
     }
@@ -124,65 +613,57 @@ async function handleCommand(page, command) {
     }
 
     case 'get-text': {
-      const element = page.locator(command.selector);
-      const count = await element.count();
-      if (count === 0) {
+      const { element, match } = await locateElement(page, command, timeout);
+      const texts = await element.evaluateAll((elements) => elements.map((el) => el.textContent));
+      if (texts.length === 0) {
         throw new Error(`Element not found: ${command.selector}`);
       }
 
-      let text;
-      if (count === 1) {
-        text = await element.textContent({ timeout });
-      } else {
-        text = await element.allTextContents();
-      }
-
       return {
         success: true,
         data: {
           selector: command.selector,
-          text,
-          element_count: count,
+          text: texts.length === 1 ? texts[0] : texts,
+          element_count: texts.length,
+          selector_match: match,
         },
       };
     }
 
     case 'get-attribute': {
-      const element = page.locator(command.selector);
-      const count = await element.count();
-      if (count === 0) {
+      const { element, match } = await locateElement(page, command, timeout);
+      const values = await element.evaluateAll(
+        (elements, name) => elements.map((el) => el.getAttribute(name)),
+        command.attributeName,
+      );
+      if (values.length === 0) {
         throw new Error(`Element not found: ${command.selector}`);
       }
 
-      let attributeValue;
-      if (count === 1) {
-        attributeValue = await element.getAttribute(command.attributeName, { timeout });
-      } else {
-        const values = [];
-        for (let i = 0; i < count; i += 1) {
-          values.push(await element.nth(i).getAttribute(command.attributeName));
-        }
-        attributeValue = values;
-      }
-
       return {
         success: true,
         data: {
           selector: command.selector,
           attribute_name: command.attributeName,
-          attribute_value: attributeValue,
-          element_count: count,
+          attribute_value: values.length === 1 ? values[0] : values,
+          element_count: values.length,
+          selector_match: match,
         },
       };
     }
 
     case 'wait': {
-      const element = page.locator(command.selector);
       const startTime = Date.now();
+      const waitCondition = command.waitCondition || 'visible';
+      // Healing only makes sense when waiting for an element to appear
+      const { element, match } =
+        waitCondition === 'visible' || waitCondition === 'attached'
+          ? await locateElement(page, command, timeout)
+          : { element: resolveLocator(page, command), match: null };
 
       await element.waitFor({
-        state: command.waitCondition || 'visible',
-        timeout,
+        state: waitCondition,
+        timeout: Math.max(timeout - (Date.now() - startTime), 1),
       });
 
       const waitedMs = Date.now() - startTime;
@@ -191,44 +672,65 @@ async function handleCommand(page, command) {
         success: true,
         data: {
           selector: command.selector,
-          wait_condition: command.waitCondition || 'visible',
+          wait_condition: waitCondition,
           waited_ms: waitedMs,
           element_found: count > 0,
+          selector_match: match,
         },
       };
     }
 
     case 'query-all': {
-      const locator = page.locator(command.selector);
-      const count = await locator.count();
-      if (count === 0) {
-        throw new Error(`No elements found: ${command.selector}`);
+      const attributeNames = Array.isArray(command.attributeNames) ? [...command.attributeNames] : [];
+      if (command.attributeName && !attributeNames.includes(command.attributeName)) {
+        attributeNames.push(command.attributeName);
       }
 
-      const limit =
-        typeof command.limit === 'number' && command.limit > 0 ? Math.min(command.limit, count) : count;
-      const elements = [];
-      const shouldTrim = command.trim !== false;
-
-      for (let i = 0; i < limit; i += 1) {
-        const elementHandle = locator.nth(i);
-        const item = { index: i };
+      const { element: locator, match } = await locateElement(page, command, timeout);
+      const limit = typeof command.limit === 'number' && command.limit > 0 ? command.limit : 0;
+      const options = {
+        getText: Boolean(command.getText),
+        trim: command.trim !== false,
+        attributeNames,
+      };
 
-        if (command.getText) {
-          let text = await elementHandle.textContent({ timeout });
-          if (shouldTrim && text) {
-            text = text.replace(/^\s+|\s+$/g, '').replace(/\s+/g, ' ');
-          }
-          item.text = text;
+      if (!command.stream) {
+        const { count, items } = await collectElements(locator, { ...options, start: 0, end: limit });
+        if (count === 0) {
+          throw new Error(`No elements found: ${command.selector}`);
         }
+        return {
+          success: true,
+          data: {
+            selector: command.selector,
+            element_count: count,
+            limit: items.length,
+            elements: items,
+            selector_match: match,
+          },
+        };
+      }
 
-        if (command.attributeName) {
-          item.attributes = {
-            [command.attributeName]: await elementHandle.getAttribute(command.attributeName),
-          };
+      // Streaming: evaluate one range at a time and send it on before reading
+      // the next, so neither side holds the whole result
+      const count = await locator.count();
+      if (count === 0) {
+        throw new Error(`No elements found: ${command.selector}`);
+      }
+      const total = limit > 0 ? Math.min(limit, count) : count;
+      const chunkSize = command.chunkSize > 0 ? command.chunkSize : DEFAULT_CHUNK_SIZE;
+      let returned = 0;
+      let chunks = 0;
+      while (returned < total) {
+        const end = Math.min(returned + chunkSize, total);
+        const { items } = await collectElements(locator, { ...options, start: returned, end });
+        if (items.length === 0) {
+          // Elements were removed while streaming
+          break;
         }
-
-        elements.push(item);
+        emit({ elements: items });
+        returned += items.length;
+        chunks += 1;
       }
 
       return {
@@ -236,8 +738,10 @@ async function handleCommand(page, command) {
         data: {
           selector: command.selector,
           element_count: count,
-          limit,
-          elements,
+          limit: returned,
+          chunks,
+          elements: [],
+          selector_match: match,
         },
       };
     }
@@ -265,6 +769,136 @@ async function handleCommand(page, command) {
       };
     }
 
+    case 'extract-table': {
+      const maxPages = command.maxPages > 0 ? command.maxPages : 1;
+      const headerRows = Number.isInteger(command.headerRows) ? command.headerRows : null;
+      let headers = null;
+      let headerRowCount = 0;
+      let caption = null;
+      let pages = 0;
+      let stopReason = 'single_page';
+      const rows = [];
+
+      for (;;) {
+        const table = page.locator(command.selector).first();
+        await table.waitFor({ state: 'attached', timeout });
+        const grid = await table.evaluate(extractTableGrid, { headerRows });
+
+        if (headers === null) {
+          headers = grid.headers;
+          headerRowCount = grid.header_rows;
+          caption = grid.caption;
+        }
+        rows.push(...grid.rows);
+        pages++;
+
+        if (!command.nextSelector) {
+          break;
+        }
+        if (pages >= maxPages) {
+          stopReason = 'max_pages';
+          break;
+        }
+
+        const next = page.locator(command.nextSelector).first();
+        if ((await next.count()) === 0
+          || !(await next.isEnabled().catch(() => false))
+          || (await next.getAttribute('aria-disabled')) === 'true') {
+          stopReason = 'no_next_page';
+          break;
+        }
+
+        // Wait until the table content changes; some paginators re-render the
+        // table in place, others replace it
+        const before = await table.innerText();
+        await next.click({ timeout });
+        const deadline = Date.now() + timeout;
+        let changed = false;
+        while (Date.now() < deadline) {
+          const current = await page.locator(command.selector).first().innerText({ timeout: 1000 }).catch(() => null);
+          if (current !== null && current !== before) {
+            changed = true;
+            break;
+          }
+          await page.waitForTimeout(100);
+        }
+        if (!changed) {
+          stopReason = 'content_unchanged';
+          break;
+        }
+      }
+
+      return {
+        success: true,
+        data: {
+          headers,
+          header_rows: headerRowCount,
+          rows,
+          row_count: rows.length,
+          pages,
+          stop_reason: stopReason,
+          caption,
+          selector: command.selector,
+        },
+      };
+    }
+
+    case 'extract': {
+      await page.waitForLoadState('domcontentloaded', { timeout });
+      if (command.schema && command.schema.root && (await page.locator(command.schema.root).count()) === 0) {
+        // No records is a valid result; don't wait out the timeout for them
+        return {
+          success: true,
+          data: { url: page.url(), records: [], record_count: 0, root_count: 0, missing_fields: {} },
+        };
+      }
+      const results = await page.evaluate(extractWithSchema, command.schema);
+      return {
+        success: true,
+        data: {
+          url: page.url(),
+          ...results,
+        },
+      };
+    }
+
+    case 'paginate-step': {
+      const itemSelector = command.itemSelector || null;
+
+      if (command.stopSelector && (await page.locator(command.stopSelector).count()) === 0) {
+        return { success: true, data: { advanced: false, reason: 'stop_selector_gone' } };
+      }
+
+      const before = await pageSignature(page, itemSelector);
+
+      if (command.strategy === 'scroll') {
+        await page.evaluate(() => window.scrollTo(0, document.body.scrollHeight));
+      } else {
+        const next = page.locator(command.nextSelector).first();
+        if ((await next.count()) === 0 || !(await next.isVisible())) {
+          return { success: true, data: { advanced: false, reason: 'next_not_found' } };
+        }
+        if (!(await next.isEnabled().catch(() => false)) || (await next.getAttribute('aria-disabled')) === 'true') {
+          return { success: true, data: { advanced: false, reason: 'next_disabled' } };
+        }
+        await next.click({ timeout });
+      }
+
+      const changed = await waitForSignatureChange(page, itemSelector, before, timeout);
+      if (changed && command.settleMs > 0) {
+        await page.waitForTimeout(command.settleMs);
+      }
+
+      return {
+        success: true,
+        data: {
+          advanced: changed,
+          reason: changed ? null : command.strategy === 'scroll' ? 'no_more_content' : 'content_unchanged',
+          url: page.url(),
+        },
+      };
+    }
+
     case 'evaluate': {
       try {
         const result = await page.evaluate(command.script);
@@ -290,6 +924,346 @@ async function handleCommand(page, command) {
       }
     }
 
+    case 'accessibility': {
+      let root = null;
+      if (command.selector) {
+        root = page.locator(command.selector).first();
+        if ((await root.count()) === 0) {
+          throw new Error(`Element not found: ${command.selector}`);
+        }
+      }
+
+      // page.accessibility was removed in newer Playwright releases; fall back
+      // to the ARIA snapshot (YAML) there
+      if (page.accessibility && typeof page.accessibility.snapshot === 'function') {
+        const rootHandle = root ? await root.elementHandle({ timeout }) : undefined;
+        const snapshot = await page.accessibility.snapshot({
+          root: rootHandle || undefined,
+          interestingOnly: command.interestingOnly !== false,
+        });
+        return {
+          success: true,
+          data: {
+            format: 'tree',
+            snapshot,
+            node_count: countAccessibilityNodes(snapshot),
+            selector: command.selector || null,
+          },
+        };
+      }
+
+      const snapshot = await (root || page.locator('body')).ariaSnapshot({ timeout });
+      return {
+        success: true,
+        data: {
+          format: 'aria-yaml',
+          snapshot,
+          node_count: snapshot.split('\n').filter((line) => line.trim().startsWith('-')).length,
+          selector: command.selector || null,
+        },
+      };
+    }
+
+    case 'a11y-audit': {
+      // Audit the page once it has parsed, and the root element once it exists
+      await page.waitForLoadState('domcontentloaded', { timeout });
+      if (command.selector) {
+        await page.locator(command.selector).first().waitFor({ state: 'attached', timeout });
+      }
+      const results = await page.evaluate(auditPage, {
+        rules: command.rules || null,
+        selector: command.selector || null,
+      });
+      return {
+        success: true,
+        data: {
+          url: page.url(),
+          title: await page.title(),
+          ...results,
+        },
+      };
+    }
+
+    case 'select': {
+      // options: [{ value } | { label } | { index }]
+      const { element, match } = await locateElement(page, command, timeout);
+      await element.selectOption(command.options || [], { timeout });
+      const state = await element.evaluate((select) => ({
+        multiple: Boolean(select.multiple),
+        selected: Array.from(select.selectedOptions || []).map((option) => ({
+          value: option.value,
+          label: option.label,
+          index: option.index,
+        })),
+      }));
+      return {
+        success: true,
+        data: {
+          selector: command.selector,
+          ...state,
+          selector_match: match,
+        },
+      };
+    }
+
+    case 'check': {
+      // Works for checkboxes and radios; a radio cannot be unchecked directly
+      const { element, match } = await locateElement(page, command, timeout);
+      await element.setChecked(command.checked !== false, { timeout });
+      return {
+        success: true,
+        data: {
+          selector: command.selector,
+          checked: await element.isChecked({ timeout }),
+          selector_match: match,
+        },
+      };
+    }
+
+    case 'hover': {
+      const { element, match } = await locateElement(page, command, timeout);
+      const { modifiers, position } = pointerOptions(command);
+      await element.hover({ timeout, modifiers, position });
+      return {
+        success: true,
+        data: {
+          selector: command.selector,
+          hovered: true,
+          selector_match: match,
+        },
+      };
+    }
+
+    case 'drag': {
+      const { element, match } = await locateElement(page, command, timeout);
+      if (command.target) {
+        await element.dragTo(page.locator(command.target).first(), {
+          timeout,
+          sourcePosition: command.sourcePosition,
+          targetPosition: command.targetPosition,
+        });
+      } else {
+        // Drop on page coordinates with real mouse events
+        await element.hover({ timeout, position: command.sourcePosition });
+        await page.mouse.down();
+        await page.mouse.move(command.point.x, command.point.y, { steps: command.steps || 1 });
+        await page.mouse.up();
+      }
+      return {
+        success: true,
+        data: {
+          selector: command.selector,
+          target: command.target || null,
+          dropped: true,
+          selector_match: match,
+        },
+      };
+    }
+
+    case 'mouse-move': {
+      await page.mouse.move(command.x, command.y, { steps: command.steps || 1 });
+      return {
+        success: true,
+        data: {
+          x: command.x,
+          y: command.y,
+          element: await page.evaluate(describeElementAt, { x: command.x, y: command.y }),
+        },
+      };
+    }
+
+    case 'mouse-click': {
+      // Described before clicking, since the click may navigate away
+      const element = await page.evaluate(describeElementAt, { x: command.x, y: command.y });
+      const { modifiers = [], ...options } = pointerOptions(command);
+      for (const key of modifiers) {
+        await page.keyboard.down(key);
+      }
+      try {
+        await page.mouse.click(command.x, command.y, options);
+      } finally {
+        for (const key of [...modifiers].reverse()) {
+          await page.keyboard.up(key);
+        }
+      }
+      return {
+        success: true,
+        data: {
+          x: command.x,
+          y: command.y,
+          clicked: true,
+          element,
+        },
+      };
+    }
+
+    case 'keyboard-press': {
+      const match = await focusTarget(page, command, timeout);
+      const keys = command.keys || [];
+      const repeat = typeof command.repeat === 'number' ? command.repeat : 1;
+
+      for (let i = 0; i < repeat; i++) {
+        for (const key of keys) {
+          switch (command.action) {
+            case 'down':
+              // keyboard.down takes single keys; hold a chord's keys in order
+              for (const part of key.split(/\+(?!$)/)) {
+                await page.keyboard.down(part);
+              }
+              break;
+            case 'up':
+              for (const part of key.split(/\+(?!$)/).reverse()) {
+                await page.keyboard.up(part);
+              }
+              break;
+            default:
+              await page.keyboard.press(key, { delay: command.delay || 0 });
+          }
+        }
+      }
+      return {
+        success: true,
+        data: {
+          selector: command.selector || null,
+          keys,
+          action: command.action || 'press',
+          repeat,
+          focused_element: await page.evaluate(describeActiveElement).catch(() => null),
+          selector_match: match,
+        },
+      };
+    }
+
+    case 'keyboard-type': {
+      const match = await focusTarget(page, command, timeout);
+      await page.keyboard.type(command.text, { delay: command.delay || 0 });
+      return {
+        success: true,
+        data: {
+          selector: command.selector || null,
+          text: command.text,
+          focused_element: await page.evaluate(describeActiveElement).catch(() => null),
+          selector_match: match,
+        },
+      };
+    }
+
+    case 'storage-get':
+    case 'storage-set':
+    case 'storage-clear': {
+      const type = command.storageType === 'session' ? 'session' : 'local';
+      let op = command.command.slice('storage-'.length);
+      if (op === 'clear' && command.key) {
+        op = 'remove';
+      }
+      const data = await withOriginPage(page, command.origin, { timeout, tabOnly: type === 'session' }, (target) =>
+        target.evaluate(webStorage, {
+          type,
+          op,
+          key: command.key ?? null,
+          value: command.value ?? null,
+        })
+      );
+      return {
+        success: true,
+        data: { storage_type: type, ...data },
+      };
+    }
+
+    case 'storage-dump': {
+      // localStorage of every origin comes from the context's storage state;
+      // sessionStorage only exists for the session page's origin
+      const state = await page.context().storageState();
+      const localStorage = {};
+      for (const entry of state.origins || []) {
+        localStorage[entry.origin] = Object.fromEntries(entry.localStorage.map((item) => [item.name, item.value]));
+      }
+
+      const sessionStorage = {};
+      if (/^https?:/.test(page.url())) {
+        const current = await page.evaluate(webStorage, { type: 'session', op: 'get' });
+        if (current.item_count > 0) {
+          sessionStorage[current.origin] = current.items;
+        }
+      }
+
+      if (command.origin) {
+        const origin = new URL(command.origin).origin;
+        for (const map of [localStorage, sessionStorage]) {
+          for (const key of Object.keys(map)) {
+            if (key !== origin) {
+              delete map[key];
+            }
+          }
+        }
+      }
+      return {
+        success: true,
+        data: {
+          url: page.url(),
+          local_storage: localStorage,
+          session_storage: sessionStorage,
+        },
+      };
+    }
+
+    case 'indexeddb-dump': {
+      const data = await withOriginPage(page, command.origin, { timeout }, (target) =>
+        target.evaluate(dumpIndexedDB, {
+          database: command.database || null,
+          store: command.store || null,
+          lower: command.lower ?? null,
+          upper: command.upper ?? null,
+          limit: typeof command.limit === 'number' ? command.limit : 100,
+        })
+      );
+      return {
+        success: true,
+        data,
+      };
+    }
+
+    case 'cookies-get': {
+      const cookies = await page.context().cookies();
+      return {
+        success: true,
+        data: { cookies },
+      };
+    }
+
+    case 'cookies-add': {
+      await page.context().addCookies(command.cookies || []);
+      return {
+        success: true,
+        data: { added: (command.cookies || []).length },
+      };
+    }
+
+    case 'cookies-delete': {
+      // Cookies are identified by name, domain and path; the CLI resolves
+      // its filters to exact cookies first
+      for (const cookie of command.cookies || []) {
+        await page.context().clearCookies({
+          name: cookie.name,
+          domain: cookie.domain,
+          path: cookie.path,
+        });
+      }
+      return {
+        success: true,
+        data: { deleted: (command.cookies || []).length },
+      };
+    }
+
+    case 'cookies-clear': {
+      const count = (await page.context().cookies()).length;
+      await page.context().clearCookies();
+      return {
+        success: true,
+        data: { deleted: count },
+      };
+    }
+
     case 'ping':
       return {
         success: true,
@@ -305,10 +1279,112 @@ async function handleCommand(page, command) {
 }
 
 (async () => {
-  const { browserType, headless } = parseConfig();
+  const { browserType, headless, shared } = parseConfig();
   const launcher = resolveBrowserLauncher(browserType);
   const browser = await launcher.launch({ headless });
-  const page = await browser.newPage();
+
+  // Every session owns one browser context (cookies, storage, pages). A
+  // dedicated runner serves the 'default' context; a shared runner starts
+  // empty and hosts one context per session created with context-create.
+  const contexts = new Map();
+  let nextContextId = 1;
+
+  // options.proxy routes the context through a proxy server;
+  // options.storageState carries cookies and storage over from another context
+  const newContext = async (options = {}) => {
+    const contextOptions = {};
+    if (options.proxy) {
+      contextOptions.proxy = options.proxy;
+    }
+    if (options.storageState) {
+      contextOptions.storageState = options.storageState;
+    }
+    const context = await browser.newContext(contextOptions);
+    try {
+      return { context, page: await context.newPage() };
+    } catch (error) {
+      await context.close().catch(() => {});
+      throw error;
+    }
+  };
+
+  const openContext = async (id, options = {}) => {
+    contexts.set(id, await newContext(options));
+  };
+
+  const closeContext = async (id) => {
+    const entry = contexts.get(id);
+    contexts.delete(id);
+    if (entry) {
+      await entry.context.close().catch(() => {});
+    }
+  };
+
+  if (!shared) {
+    await openContext(DEFAULT_CONTEXT_ID);
+  }
+
+  // Context lifecycle commands; everything else goes to handleCommand with
+  // the page of the command's context
+  const handleContextCommand = async (command) => {
+    const id = command.contextId || DEFAULT_CONTEXT_ID;
+    switch (command.command) {
+      case 'context-create': {
+        const newId = `ctx_${nextContextId++}`;
+        await openContext(newId, { proxy: command.proxy });
+        return {
+          success: true,
+          data: { context_id: newId, context_count: contexts.size, version: browser.version() },
+        };
+      }
+      case 'context-close':
+        await closeContext(id);
+        return { success: true, data: { context_id: id, context_count: contexts.size } };
+      case 'ping':
+        // Answered here so a shared runner without contexts still responds
+        return { success: true, data: { status: 'alive', context_count: contexts.size } };
+      case 'reset-context':
+        // A pooled runner is handed out with no cookies, storage or open pages
+        await closeContext(id);
+        await openContext(id, { proxy: command.proxy });
+        return { success: true, data: { reset: true, context_id: id } };
+      case 'context-proxy': {
+        // Proxies are fixed per context: move cookies, storage and the
+        // current page to a new context using the new proxy. The old context
+        // is only closed once the new one is open, so a failed switch leaves
+        // the session as it was.
+        const entry = contexts.get(id);
+        if (!entry) {
+          throw new Error(`Browser context not found: ${id}`);
+        }
+        const storageState = await entry.context.storageState();
+        const url = entry.page.url();
+        const replacement = await newContext({ proxy: command.proxy, storageState });
+        contexts.set(id, replacement);
+        await entry.context.close().catch(() => {});
+
+        let restored = false;
+        if (/^https?:/.test(url)) {
+          restored = await replacement.page
+            .goto(url, { timeout: DEFAULT_TIMEOUT })
+            .then(() => true)
+            .catch(() => false);
+        }
+        return { success: true, data: { context_id: id, url, restored } };
+      }
+      default:
+        return null;
+    }
+  };
+
+  const pageFor = (command) => {
+    const entry = contexts.get(command.contextId || DEFAULT_CONTEXT_ID);
+    if (!entry) {
+      throw new Error(`Browser context not found: ${command.contextId || DEFAULT_CONTEXT_ID}`);
+    }
+    return entry.page;
+  };
+
   const version = await browser.version();
   const isConnected = browser.isConnected();
 
@@ -334,11 +1410,30 @@ async function handleCommand(page, command) {
           continue;
         }
 
+        const emit = (data) => {
+          socket.write(`${JSON.stringify({ success: true, chunk: true, data })}\n`);
+        };
+
         try {
-          const response = await handleCommand(page, command);
-          socket.write(`${JSON.stringify(response)}\n`);
+          const contextResponse = await handleContextCommand(command);
+          if (contextResponse) {
+            socket.write(`${JSON.stringify(contextResponse)}\n`);
+            continue;
+          }
         } catch (error) {
           socket.write(`${toCommandError(error)}\n`);
+          continue;
+        }
+
+        // Every page command reports the URL the page is on afterwards, so
+        // a click that left the site moves the session to the new domain
+        let page = null;
+        try {
+          page = pageFor(command);
+          const response = await handleCommand(page, command, emit);
+          socket.write(`${JSON.stringify({ ...response, url: page.url() })}\n`);
+        } catch (error) {
+          socket.write(`${toCommandError(error, page ? { url: page.url() } : {})}\n`);
         }
       }
     });
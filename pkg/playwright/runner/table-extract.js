@@ -0,0 +1,86 @@
+// Table extraction for the element-extract-table command. extractTableGrid
+// is serialized into the page by page.evaluate/locator.evaluate, so it must
+// stay self-contained.
+
+function extractTableGrid(table, options) {
+  const normalize = (value) => (value || '').replace(/\s+/g, ' ').trim();
+  const opts = options || {};
+
+  const rows = Array.from(table.rows || []);
+  const grid = [];
+  const headerFlags = [];
+
+  // Expand colspan/rowspan: every spanned position receives the text of the
+  // originating cell so the result is a rectangular grid
+  rows.forEach((row, rowIndex) => {
+    grid[rowIndex] = grid[rowIndex] || [];
+    let column = 0;
+    let allHeaders = row.cells.length > 0;
+
+    Array.from(row.cells).forEach((cell) => {
+      while (grid[rowIndex][column] !== undefined) {
+        column++;
+      }
+
+      const text = normalize(cell.innerText !== undefined ? cell.innerText : cell.textContent);
+      const colSpan = Math.max(1, cell.colSpan || 1);
+      // rowspan="0" spans the rest of the section
+      const rowSpan = cell.rowSpan === 0 ? rows.length - rowIndex : Math.max(1, cell.rowSpan || 1);
+
+      for (let r = 0; r < rowSpan && rowIndex + r < rows.length; r++) {
+        grid[rowIndex + r] = grid[rowIndex + r] || [];
+        for (let c = 0; c < colSpan; c++) {
+          grid[rowIndex + r][column + c] = text;
+        }
+      }
+
+      if (cell.tagName !== 'TH') {
+        allHeaders = false;
+      }
+      column += colSpan;
+    });
+
+    const inHead = row.parentElement && row.parentElement.tagName === 'THEAD';
+    headerFlags[rowIndex] = inHead || allHeaders;
+  });
+
+  const width = grid.reduce((max, row) => Math.max(max, row.length), 0);
+  const rectangular = grid.map((row) => {
+    const filled = [];
+    for (let c = 0; c < width; c++) {
+      filled.push(row[c] === undefined ? '' : row[c]);
+    }
+    return filled;
+  });
+
+  // Header rows: explicit count, otherwise the leading <thead>/all-<th> rows
+  let headerRowCount = 0;
+  if (Number.isInteger(opts.headerRows) && opts.headerRows >= 0) {
+    headerRowCount = Math.min(opts.headerRows, rectangular.length);
+  } else {
+    while (headerRowCount < rectangular.length && headerFlags[headerRowCount]) {
+      headerRowCount++;
+    }
+  }
+
+  const headers = [];
+  for (let c = 0; c < width; c++) {
+    const parts = [];
+    for (let r = 0; r < headerRowCount; r++) {
+      const value = rectangular[r][c];
+      if (value && parts[parts.length - 1] !== value) {
+        parts.push(value);
+      }
+    }
+    headers.push(parts.join(' / '));
+  }
+
+  return {
+    headers,
+    header_rows: headerRowCount,
+    rows: rectangular.slice(headerRowCount),
+    caption: table.caption ? normalize(table.caption.textContent) : null,
+  };
+}
+
+module.exports = { extractTableGrid };
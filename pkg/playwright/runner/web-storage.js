@@ -0,0 +1,182 @@
+// Web storage and IndexedDB access for the storage-* and indexeddb-dump
+// commands. The exported functions run inside the page via page.evaluate,
+// so they must stay self-contained.
+
+// Reads, writes or clears localStorage or sessionStorage of the page's
+// origin. op is 'get' (key optional), 'set', 'remove' or 'clear'.
+function webStorage({ type, op, key, value }) {
+  const storage = type === 'session' ? window.sessionStorage : window.localStorage;
+
+  const snapshot = () => {
+    const items = {};
+    for (let i = 0; i < storage.length; i++) {
+      const name = storage.key(i);
+      items[name] = storage.getItem(name);
+    }
+    return items;
+  };
+
+  switch (op) {
+    case 'get':
+      if (key !== null && key !== undefined) {
+        const item = storage.getItem(key);
+        return { origin: location.origin, key, value: item, found: item !== null };
+      }
+      return { origin: location.origin, items: snapshot(), item_count: storage.length };
+    case 'set':
+      storage.setItem(key, value);
+      return { origin: location.origin, key, value };
+    case 'remove': {
+      const found = storage.getItem(key) !== null;
+      storage.removeItem(key);
+      return { origin: location.origin, key, removed_count: found ? 1 : 0 };
+    }
+    case 'clear': {
+      const count = storage.length;
+      storage.clear();
+      return { origin: location.origin, removed_count: count };
+    }
+    default:
+      throw new Error(`Unknown storage operation: ${op}`);
+  }
+}
+
+// Dumps IndexedDB databases of the page's origin without modifying them.
+// Options: database and store restrict the dump, lower/upper bound the keys
+// (inclusive), limit caps the records per store.
+async function dumpIndexedDB({ database, store, lower, upper, limit }) {
+  const promisify = (request) =>
+    new Promise((resolve, reject) => {
+      request.onsuccess = () => resolve(request.result);
+      request.onerror = () => reject(request.error);
+    });
+
+  // Values are structured clones; keep what JSON can carry and describe
+  // binary data instead of copying it
+  const toJSON = (value, depth = 0) => {
+    if (value === null || value === undefined || depth > 20) {
+      return value === undefined ? null : value;
+    }
+    if (value instanceof Date) {
+      return value.toISOString();
+    }
+    if (typeof Blob !== 'undefined' && value instanceof Blob) {
+      return { $type: 'Blob', size: value.size, mime_type: value.type };
+    }
+    if (value instanceof ArrayBuffer || ArrayBuffer.isView(value)) {
+      return { $type: value.constructor.name, byte_length: value.byteLength };
+    }
+    if (value instanceof Map) {
+      return { $type: 'Map', entries: [...value].map(([k, v]) => [toJSON(k, depth + 1), toJSON(v, depth + 1)]) };
+    }
+    if (value instanceof Set) {
+      return { $type: 'Set', values: [...value].map((v) => toJSON(v, depth + 1)) };
+    }
+    if (Array.isArray(value)) {
+      return value.map((v) => toJSON(v, depth + 1));
+    }
+    if (typeof value === 'object') {
+      const result = {};
+      for (const [k, v] of Object.entries(value)) {
+        result[k] = toJSON(v, depth + 1);
+      }
+      return result;
+    }
+    if (typeof value === 'bigint') {
+      return value.toString();
+    }
+    return value;
+  };
+
+  // Opening a database that does not exist would create it; aborting the
+  // upgrade keeps the dump read-only
+  const openExisting = (name) =>
+    new Promise((resolve, reject) => {
+      const request = indexedDB.open(name);
+      request.onupgradeneeded = () => request.transaction.abort();
+      request.onsuccess = () => resolve(request.result);
+      request.onerror = () => reject(new Error(`IndexedDB database not found: ${name}`));
+    });
+
+  let names;
+  if (typeof indexedDB.databases === 'function') {
+    names = (await indexedDB.databases()).map((info) => info.name);
+    if (database) {
+      if (!names.includes(database)) {
+        throw new Error(`IndexedDB database not found: ${database}`);
+      }
+      names = [database];
+    }
+  } else if (database) {
+    names = [database];
+  } else {
+    throw new Error('This browser cannot list IndexedDB databases; pass a database name');
+  }
+
+  let range = null;
+  if (lower !== null && upper !== null) {
+    range = IDBKeyRange.bound(lower, upper);
+  } else if (lower !== null) {
+    range = IDBKeyRange.lowerBound(lower);
+  } else if (upper !== null) {
+    range = IDBKeyRange.upperBound(upper);
+  }
+
+  const databases = [];
+  for (const name of names) {
+    const db = await openExisting(name);
+    try {
+      let storeNames = [...db.objectStoreNames];
+      if (store) {
+        if (!storeNames.includes(store)) {
+          throw new Error(`Object store not found: ${name}/${store}`);
+        }
+        storeNames = [store];
+      }
+
+      const stores = [];
+      for (const storeName of storeNames) {
+        const objectStore = db.transaction(storeName, 'readonly').objectStore(storeName);
+        const total = await promisify(objectStore.count());
+        const matching = range ? await promisify(objectStore.count(range)) : total;
+
+        const records = [];
+        await new Promise((resolve, reject) => {
+          const cursorRequest = objectStore.openCursor(range);
+          cursorRequest.onerror = () => reject(cursorRequest.error);
+          cursorRequest.onsuccess = () => {
+            const cursor = cursorRequest.result;
+            if (!cursor || records.length >= limit) {
+              resolve();
+              return;
+            }
+            records.push({ key: toJSON(cursor.primaryKey), value: toJSON(cursor.value) });
+            cursor.continue();
+          };
+        });
+
+        stores.push({
+          name: storeName,
+          key_path: objectStore.keyPath,
+          auto_increment: objectStore.autoIncrement,
+          indexes: [...objectStore.indexNames].map((indexName) => {
+            const index = objectStore.index(indexName);
+            return { name: indexName, key_path: index.keyPath, unique: index.unique, multi_entry: index.multiEntry };
+          }),
+          record_count: total,
+          matching_count: matching,
+          records,
+          truncated: matching > records.length,
+        });
+      }
+
+      databases.push({ name, version: db.version, stores });
+    } finally {
+      db.close();
+    }
+  }
+
+  return { origin: location.origin, databases, database_count: databases.length };
+}
+
+module.exports = { webStorage, dumpIndexedDB };
@@ -1,12 +1,10 @@
 package playwright
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,9 +12,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/oa-plugins/webauto/internal/filelock"
 	"github.com/oa-plugins/webauto/pkg/bootstrap"
 	"github.com/oa-plugins/webauto/pkg/config"
 	"github.com/oa-plugins/webauto/pkg/ipc"
+	"github.com/oa-plugins/webauto/pkg/proxy"
+	"github.com/oa-plugins/webauto/pkg/ratelimit"
 )
 
 // Session represents a browser session
@@ -26,11 +27,44 @@ type Session struct {
 	Headless    bool        `json:"headless"`
 	CreatedAt   time.Time   `json:"created_at"`
 	LastUsedAt  time.Time   `json:"last_used_at"`
-	PID         int         `json:"pid"`  // Process ID for reconnection
-	Port        int         `json:"port"` // TCP port for IPC
-	Browser     interface{} `json:"-"`    // WebSocket endpoint (string) for browser reconnection
-	Page        interface{} `json:"-"`    // Page reference (for future use)
-	Process     interface{} `json:"-"`    // Node.js process reference (for cleanup)
+	PID         int         `json:"pid"`                   // Process ID for reconnection
+	Port        int         `json:"port"`                  // TCP port for IPC
+	CurrentURL  string      `json:"current_url,omitempty"` // Page URL after the last command (used for per-domain rate limiting)
+	Browser     interface{} `json:"-"`                     // WebSocket endpoint (string) for browser reconnection
+	Page        interface{} `json:"-"`                     // Page reference (for future use)
+	Process     interface{} `json:"-"`                     // Node.js process reference (for cleanup)
+	Pooled      bool        `json:"pooled,omitempty"`      // Handed out from the warm session pool
+
+	// Shared-browser sessions are browser contexts inside a runner hosting
+	// several sessions; RunnerID identifies the parent runner
+	RunnerID      string `json:"runner_id,omitempty"`
+	SharedBrowser bool   `json:"shared_browser,omitempty"`
+	ContextID     string `json:"context_id,omitempty"`
+
+	// Proxy describes the session's proxy without credentials;
+	// ProxyNavigations counts navigations since the proxy was last switched
+	Proxy            *proxy.Info `json:"proxy,omitempty"`
+	ProxyNavigations int         `json:"proxy_navigations,omitempty"`
+}
+
+// LaunchOptions describes the browser a new session runs in
+type LaunchOptions struct {
+	BrowserType string
+	Headless    bool
+
+	// SessionID is a custom session ID (generated when empty)
+	SessionID string
+
+	// SharedBrowser creates the session as a new browser context inside a
+	// running shared runner of the same browser type and mode, launching one
+	// if none exists
+	SharedBrowser bool
+
+	// Proxy routes the session's browser context through a proxy server.
+	// Without it the session takes the next entry of ProxyPoolPath (falling
+	// back to PROXY_POOL_PATH) when a pool is configured.
+	Proxy         *proxy.Proxy
+	ProxyPoolPath string
 }
 
 // sessionDir returns the directory path for session files
@@ -111,7 +145,11 @@ func deleteSession(sessionID string) error {
 type SessionManager struct {
 	cfg      *config.Config
 	sessions map[string]*managedSession
+	limiter  *ratelimit.Limiter
 	mu       sync.RWMutex
+
+	// refillOnce limits a process to one background pool refill
+	refillOnce sync.Once
 }
 
 // NewSessionManager creates a new SessionManager instance
@@ -119,11 +157,46 @@ func NewSessionManager(cfg *config.Config) *SessionManager {
 	return &SessionManager{
 		cfg:      cfg,
 		sessions: make(map[string]*managedSession),
+		limiter:  newRateLimiter(cfg),
 	}
 }
 
+// newRateLimiter builds the per-domain limiter shared by all webauto processes.
+// An unreadable rules file disables throttling rather than failing every command.
+func newRateLimiter(cfg *config.Config) *ratelimit.Limiter {
+	rules, err := ratelimit.LoadRules(cfg.RateLimitConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: rate limiting disabled: %v\n", err)
+		rules = nil
+	}
+
+	limiter := ratelimit.NewLimiter(
+		rules,
+		filepath.Join(bootstrap.GetCacheDir(), "ratelimit"),
+		time.Duration(cfg.SessionTimeoutSeconds)*time.Second,
+	)
+	limiter.SetPolicy(cfg.RateLimitPolicy)
+
+	return limiter
+}
+
 // Create creates a new browser session
 func (sm *SessionManager) Create(ctx context.Context, browserType string, headless bool, customSessionID string) (*Session, error) {
+	return sm.CreateWithOptions(ctx, LaunchOptions{
+		BrowserType: browserType,
+		Headless:    headless,
+		SessionID:   customSessionID,
+	})
+}
+
+// CreateWithOptions creates a new browser session
+func (sm *SessionManager) CreateWithOptions(ctx context.Context, opts LaunchOptions) (*Session, error) {
+	// An on-demand browser install can take minutes, so it runs before the
+	// session map is locked
+	if err := sm.ensureBrowser(opts.BrowserType); err != nil {
+		return nil, err
+	}
+
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -134,168 +207,237 @@ func (sm *SessionManager) Create(ctx context.Context, browserType string, headle
 
 	// Generate or use provided session ID
 	var sessionID string
-	if customSessionID != "" {
+	if opts.SessionID != "" {
 		// Use custom session ID (validate it doesn't already exist)
-		if _, exists := sm.sessions[customSessionID]; exists {
-			return nil, fmt.Errorf("session ID already exists: %s", customSessionID)
+		if _, exists := sm.sessions[opts.SessionID]; exists {
+			return nil, fmt.Errorf("session ID already exists: %s", opts.SessionID)
 		}
-		sessionID = customSessionID
+		sessionID = opts.SessionID
 	} else {
 		// Generate unique session ID
 		sessionID = "ses_" + uuid.New().String()[:8]
 	}
 
-	// Ensure session runner script is available and configure launch parameters
-	scriptPath, err := ensureSessionRunnerScript()
+	// Pick the proxy first so a broken pool fails before a browser starts
+	sessionProxy, proxyInfo, err := sm.resolveProxy(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	runnerConfig := map[string]interface{}{
-		"browserType": browserType,
-		"headless":    headless,
-	}
+	// Shared sessions join a running shared runner; dedicated sessions prefer
+	// a pre-launched runner from the warm pool. Otherwise launch a new one.
+	var runner *runnerProcess
+	pooled := false
+	if opts.SharedBrowser {
+		// Hold the shared runner lock until the new context is recorded, so
+		// no other process stops the runner after its last context closes
+		lock, err := lockSharedRunners(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Release()
 
-	configJSON, err := json.Marshal(runnerConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode runner config: %w", err)
+		runner = sm.findSharedRunner(opts)
+	} else {
+		runner, pooled = sm.claimWarmRunner(opts.BrowserType, opts.Headless)
+		if pooled {
+			// Replace the claimed runner once this session is set up
+			defer sm.refillOnce.Do(func() { sm.refillPool(opts.BrowserType, opts.Headless) })
+		}
+	}
+	launched := false
+	if runner == nil {
+		runner, err = launchRunner(ctx, sm.cfg.PlaywrightNodePath, opts)
+		if err != nil {
+			return nil, err
+		}
+		launched = true
 	}
 
-	// Create command to run Node.js script
-	cmd := exec.CommandContext(ctx, sm.cfg.PlaywrightNodePath, scriptPath)
-
-	// Set working directory to cache dir so Node.js can find playwright module
-	cmd.Dir = bootstrap.GetCacheDir()
-
-	// Set environment variables required by the Playwright runner
-	browsersDir := bootstrap.GetBrowsersDir()
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("PLAYWRIGHT_BROWSERS_PATH=%s", browsersDir),
-		fmt.Sprintf("WEBAUTO_RUNNER_CONFIG=%s", string(configJSON)),
-	)
+	// Create session with browser info
+	session := &Session{
+		ID:            sessionID,
+		BrowserType:   opts.BrowserType,
+		Headless:      opts.Headless,
+		CreatedAt:     time.Now(),
+		LastUsedAt:    time.Now(),
+		PID:           runner.pid,     // Store PID for process reconnection
+		Port:          runner.port,    // Store TCP port for IPC
+		Browser:       runner.version, // Store browser version for info
+		Process:       runner.process, // Store process for cleanup
+		Pooled:        pooled,
+		RunnerID:      runner.id,
+		SharedBrowser: opts.SharedBrowser,
+		Proxy:         proxyInfo,
+	}
+
+	// Only a runner owned by this session may be killed when setup fails
+	abort := func(worker *sessionWorker, err error) (*Session, error) {
+		if worker != nil {
+			worker.Close()
+		}
+		if !opts.SharedBrowser || launched {
+			runner.kill()
+		}
+		return nil, err
+	}
 
-	// Get stdout pipe for reading launch response
-	stdout, err := cmd.StdoutPipe()
+	worker, err := newSessionWorker(ctx, session)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		return abort(nil, fmt.Errorf("failed to establish session worker: %w", err))
 	}
 
-	// Get stderr pipe for error messages
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	// The proxy is a browser context option, so a proxied session always
+	// starts from a new context
+	contextCommand := func(name string) map[string]interface{} {
+		command := map[string]interface{}{"command": name}
+		if sessionProxy != nil {
+			command["proxy"] = sessionProxy.Playwright()
+		}
+		return command
 	}
 
-	// Start the process (non-blocking)
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start browser process: %w", err)
+	switch {
+	case opts.SharedBrowser:
+		resp, err := worker.Send(ctx, contextCommand("context-create"))
+		if err != nil || !resp.Success {
+			return abort(worker, fmt.Errorf("failed to create browser context: %s", describeFailure(resp, err)))
+		}
+		session.ContextID, _ = resp.Data["context_id"].(string)
+		if version, ok := resp.Data["version"].(string); ok {
+			session.Browser = version
+		}
+	case pooled || sessionProxy != nil:
+		// A pooled runner must start from a clean browser context
+		resp, err := worker.Send(ctx, contextCommand("reset-context"))
+		if err != nil || !resp.Success {
+			return abort(worker, fmt.Errorf("failed to prepare browser context: %s", describeFailure(resp, err)))
+		}
 	}
 
-	// Read first line of output (launch response) with timeout
-	type scanResult struct {
-		data []byte
-		err  error
+	// Save session to file
+	if err := session.saveSession(); err != nil {
+		if opts.SharedBrowser && !launched {
+			_, _ = closeSharedContext(session, worker)
+		}
+		return abort(worker, fmt.Errorf("failed to save session: %w", err))
 	}
-	scanChan := make(chan scanResult, 1)
 
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		if scanner.Scan() {
-			scanChan <- scanResult{data: scanner.Bytes(), err: nil}
-		} else {
-			scanChan <- scanResult{data: nil, err: scanner.Err()}
+	// Store session in memory
+	sm.sessions[sessionID] = &managedSession{
+		session: session,
+		worker:  worker,
+	}
+
+	return session, nil
+}
+
+// findSharedRunner returns a live shared runner matching the launch options.
+// The caller holds sm.mu.
+func (sm *SessionManager) findSharedRunner(opts LaunchOptions) *runnerProcess {
+	checked := make(map[string]bool)
+	for _, session := range sm.listAllLocked() {
+		if !session.SharedBrowser || session.RunnerID == "" || checked[session.RunnerID] {
+			continue
 		}
-	}()
+		if session.BrowserType != opts.BrowserType || session.Headless != opts.Headless {
+			continue
+		}
+		checked[session.RunnerID] = true
 
-	// Wait for scan result or timeout
-	var scanData []byte
-	select {
-	case result := <-scanChan:
-		if result.err != nil || result.data == nil {
-			// Read stderr for error details
-			stderrData, _ := io.ReadAll(stderr)
-			cmd.Process.Kill()
-			if len(stderrData) > 0 {
-				return nil, fmt.Errorf("failed to read browser launch response, stderr: %s", string(stderrData))
-			}
-			return nil, fmt.Errorf("failed to read browser launch response")
+		if !runnerAlive(session.Port) {
+			continue
 		}
-		scanData = result.data
-	case <-time.After(30 * time.Second):
-		// Timeout waiting for response
-		stderrData, _ := io.ReadAll(stderr)
-		cmd.Process.Kill()
-		if len(stderrData) > 0 {
-			return nil, fmt.Errorf("timeout waiting for browser launch response, stderr: %s", string(stderrData))
+		process, err := os.FindProcess(session.PID)
+		if err != nil {
+			continue
+		}
+		return &runnerProcess{
+			id:      session.RunnerID,
+			pid:     session.PID,
+			port:    session.Port,
+			process: process,
 		}
-		return nil, fmt.Errorf("timeout waiting for browser launch response")
 	}
+	return nil
+}
 
-	// Parse launch response
-	var response ipc.NodeResponse
-	if err := json.Unmarshal(scanData, &response); err != nil {
-		cmd.Process.Kill()
-		return nil, fmt.Errorf("failed to parse launch response: %w", err)
-	}
+// releaseRunner ends a session's use of its runner. A shared runner only
+// loses the session's browser context and is stopped with its last context;
+// when the context cannot be closed the runner is left running for the other
+// sessions and the error is returned.
+func (sm *SessionManager) releaseRunner(session *Session, worker *sessionWorker) error {
+	if session.SharedBrowser && session.ContextID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Closing the last context and stopping the runner must not
+		// interleave with another process attaching a new context
+		lock, err := lockSharedRunners(ctx)
+		if err != nil {
+			return err
+		}
+		defer lock.Release()
 
-	if !response.Success {
-		cmd.Process.Kill()
-		errMsg := "unknown error"
-		if response.Error != "" {
-			errMsg = response.Error
+		remaining, err := closeSharedContext(session, worker)
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return nil
 		}
-		return nil, fmt.Errorf("browser launch failed: %s", errMsg)
 	}
 
-	// Extract browser info from response
-	browserVersion, _ := response.Data["version"].(string)
-	isConnected, _ := response.Data["isConnected"].(bool)
-	port, _ := response.Data["port"].(float64) // JSON numbers are float64
-
-	// Log successful launch (for debugging)
-	if !isConnected {
-		cmd.Process.Kill()
-		return nil, fmt.Errorf("browser launched but is not connected")
+	if session.Process != nil {
+		if cmd, ok := session.Process.(*exec.Cmd); ok {
+			if cmd.Process != nil {
+				return cmd.Process.Kill()
+			}
+		} else if proc, ok := session.Process.(*os.Process); ok {
+			return proc.Kill()
+		}
 	}
+	return nil
+}
 
-	if port == 0 {
-		cmd.Process.Kill()
-		return nil, fmt.Errorf("failed to get TCP port from browser launch response")
+// lockSharedRunners takes the cross-process lock that serializes attaching
+// contexts to shared runners with tearing them down
+func lockSharedRunners(ctx context.Context) (*filelock.Lock, error) {
+	if err := os.MkdirAll(sessionDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
 	}
-
-	// Create session with browser info
-	session := &Session{
-		ID:          sessionID,
-		BrowserType: browserType,
-		Headless:    headless,
-		CreatedAt:   time.Now(),
-		LastUsedAt:  time.Now(),
-		PID:         cmd.Process.Pid, // Store PID for process reconnection
-		Port:        int(port),       // Store TCP port for IPC
-		Browser:     browserVersion,  // Store browser version for info
-		Process:     cmd,             // Store process for cleanup
+	lock, err := filelock.Acquire(ctx, filepath.Join(sessionDir(), "shared-runners.lock"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock shared runners: %w", err)
 	}
+	return lock, nil
+}
 
-	// Save session to file
-	if err := session.saveSession(); err != nil {
-		cmd.Process.Kill()
-		return nil, fmt.Errorf("failed to save session: %w", err)
-	}
+// closeSharedContext closes the session's context in its shared runner and
+// returns how many contexts the runner still hosts
+func closeSharedContext(session *Session, worker *sessionWorker) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	worker, err := newSessionWorker(ctx, session)
-	if err != nil {
-		cmd.Process.Kill()
-		_ = deleteSession(sessionID)
-		return nil, fmt.Errorf("failed to establish session worker: %w", err)
+	if worker == nil || worker.isClosed() {
+		temp, err := newSessionWorker(ctx, session)
+		if err != nil {
+			return 0, err
+		}
+		defer temp.Close()
+		worker = temp
 	}
 
-	// Store session in memory
-	sm.sessions[sessionID] = &managedSession{
-		session: session,
-		worker:  worker,
+	resp, err := worker.Send(ctx, map[string]interface{}{
+		"command":   "context-close",
+		"contextId": session.ContextID,
+	})
+	if err != nil || !resp.Success {
+		return 0, fmt.Errorf("failed to close browser context: %s", describeFailure(resp, err))
 	}
 
-	return session, nil
+	remaining, _ := resp.Data["context_count"].(float64)
+	return int(remaining), nil
 }
 
 // Get retrieves a session by ID
@@ -343,10 +485,10 @@ func (sm *SessionManager) Close(sessionID string) error {
 
 	var session *Session
 
+	var worker *sessionWorker
+
 	if managed != nil {
-		if managed.worker != nil {
-			managed.worker.Close()
-		}
+		worker = managed.worker
 		session = managed.session
 	} else {
 		loadedSession, err := loadSession(sessionID)
@@ -356,24 +498,22 @@ func (sm *SessionManager) Close(sessionID string) error {
 		session = loadedSession
 	}
 
-	// Kill the browser process if it exists
-	if session.Process != nil {
-		if cmd, ok := session.Process.(*exec.Cmd); ok {
-			if cmd.Process != nil {
-				if err := cmd.Process.Kill(); err != nil {
-					fmt.Printf("Warning: failed to kill browser process: %v\n", err)
-				}
-			}
-		} else if proc, ok := session.Process.(*os.Process); ok {
-			if err := proc.Kill(); err != nil {
-				fmt.Printf("Warning: failed to kill browser process: %v\n", err)
-			}
-		}
+	// Kill the browser process (or close the context of a shared runner)
+	if err := sm.releaseRunner(session, worker); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to release browser: %v\n", err)
+	}
+	if worker != nil {
+		worker.Close()
 	}
 
 	// Delete session file
 	if err := deleteSession(sessionID); err != nil {
-		fmt.Printf("Warning: failed to delete session file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to delete session file: %v\n", err)
+	}
+
+	// Free the session's per-domain rate limit slot
+	if err := sm.limiter.Release(context.Background(), sessionID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to release rate limit slot: %v\n", err)
 	}
 
 	return nil
@@ -397,6 +537,11 @@ func (sm *SessionManager) ListAll() []*Session {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
+	return sm.listAllLocked()
+}
+
+// listAllLocked implements ListAll; the caller holds sm.mu
+func (sm *SessionManager) listAllLocked() []*Session {
 	// Start with sessions in memory
 	sessionMap := make(map[string]*Session)
 	for id, managed := range sm.sessions {
@@ -454,24 +599,19 @@ func (sm *SessionManager) CleanupExpired() int {
 
 	for sessionID, managed := range sm.sessions {
 		if now.Sub(managed.session.LastUsedAt) > timeout {
+			if err := sm.releaseRunner(managed.session, managed.worker); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to release browser: %v\n", err)
+			}
 			if managed.worker != nil {
 				managed.worker.Close()
 			}
 
-			if managed.session.Process != nil {
-				if cmd, ok := managed.session.Process.(*exec.Cmd); ok {
-					if cmd.Process != nil {
-						_ = cmd.Process.Kill()
-					}
-				} else if proc, ok := managed.session.Process.(*os.Process); ok {
-					_ = proc.Kill()
-				}
-			}
-
 			if err := deleteSession(sessionID); err != nil {
-				fmt.Printf("Warning: failed to delete session file: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Warning: failed to delete session file: %v\n", err)
 			}
 
+			_ = sm.limiter.Release(context.Background(), sessionID)
+
 			delete(sm.sessions, sessionID)
 			cleaned++
 		}
@@ -480,6 +620,41 @@ func (sm *SessionManager) CleanupExpired() int {
 	return cleaned
 }
 
+// LiveSessions returns the sessions, from any process, whose runner still
+// answers
+func (sm *SessionManager) LiveSessions() []*Session {
+	live := []*Session{}
+	for _, session := range sm.ListAll() {
+		if runnerAlive(session.Port) {
+			live = append(live, session)
+		}
+	}
+	return live
+}
+
+// StaleSession is a saved session that can no longer be used
+type StaleSession struct {
+	Session *Session
+	Reason  string
+}
+
+// StaleSessions returns saved sessions whose runner no longer answers or
+// which have been idle longer than SESSION_TIMEOUT_SECONDS
+func (sm *SessionManager) StaleSessions() []StaleSession {
+	timeout := time.Duration(sm.cfg.SessionTimeoutSeconds) * time.Second
+
+	stale := []StaleSession{}
+	for _, session := range sm.ListAll() {
+		switch {
+		case !runnerAlive(session.Port):
+			stale = append(stale, StaleSession{Session: session, Reason: "runner not responding"})
+		case time.Since(session.LastUsedAt) > timeout:
+			stale = append(stale, StaleSession{Session: session, Reason: "idle longer than session timeout"})
+		}
+	}
+	return stale
+}
+
 // Count returns the number of active sessions
 func (sm *SessionManager) Count() int {
 	sm.mu.RLock()
@@ -488,13 +663,36 @@ func (sm *SessionManager) Count() int {
 	return len(sm.sessions)
 }
 
-// SendCommand sends a command to a browser session via the session worker queue
+// Available returns how many more sessions Create will accept
+func (sm *SessionManager) Available() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if available := sm.cfg.SessionMaxCount - len(sm.sessions); available > 0 {
+		return available
+	}
+	return 0
+}
+
+// SendCommand sends a command to a browser session via the session worker queue.
+// Commands are throttled by the per-domain rate limiter before being queued.
 func (sm *SessionManager) SendCommand(ctx context.Context, sessionID string, command map[string]interface{}) (*ipc.NodeResponse, error) {
 	managed, err := sm.getOrCreateManagedSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Route the command to the session's context in a shared runner
+	if managed.session.ContextID != "" {
+		command["contextId"] = managed.session.ContextID
+	}
+
+	if err := sm.throttle(ctx, managed.session, command); err != nil {
+		return nil, err
+	}
+
+	pool := sm.beforeNavigate(ctx, managed, command)
+
 	resp, err := managed.worker.Send(ctx, command)
 	if err != nil {
 		if errors.Is(err, errSessionClosed) {
@@ -511,9 +709,56 @@ func (sm *SessionManager) SendCommand(ctx context.Context, sessionID string, com
 	managed.session.LastUsedAt = time.Now()
 	sm.mu.Unlock()
 
+	sm.afterNavigate(ctx, managed, pool, command, resp)
+	sm.trackPageURL(managed.session, resp)
+
 	return resp, nil
 }
 
+// throttle waits for (or rejects on) the per-domain budget of a command.
+// Navigations are charged to the target URL's domain, every other command to
+// the domain of the page the session is currently on.
+func (sm *SessionManager) throttle(ctx context.Context, session *Session, command map[string]interface{}) error {
+	if !sm.limiter.Enabled() {
+		return nil
+	}
+
+	req := ratelimit.Request{SessionID: session.ID}
+	if command["command"] == "navigate" {
+		targetURL, _ := command["url"].(string)
+		req.Host = ratelimit.HostFromURL(targetURL)
+		req.Navigation = true
+	} else {
+		sm.mu.RLock()
+		req.Host = ratelimit.HostFromURL(session.CurrentURL)
+		sm.mu.RUnlock()
+	}
+
+	_, err := sm.limiter.Acquire(ctx, req)
+	return err
+}
+
+// trackPageURL records the page URL the runner reports after every command,
+// so that later commands (possibly from other processes) know which domain
+// they hit, also after a click or form submit left the site
+func (sm *SessionManager) trackPageURL(session *Session, resp *ipc.NodeResponse) {
+	if resp == nil || resp.URL == "" {
+		return
+	}
+
+	sm.mu.Lock()
+	changed := session.CurrentURL != resp.URL
+	session.CurrentURL = resp.URL
+	sm.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	if err := session.saveSession(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save session: %v\n", err)
+	}
+}
+
 func (sm *SessionManager) getOrCreateManagedSession(ctx context.Context, sessionID string) (*managedSession, error) {
 	if ctx == nil {
 		ctx = context.Background()
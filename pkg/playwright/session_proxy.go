@@ -0,0 +1,159 @@
+package playwright
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oa-plugins/webauto/pkg/ipc"
+	"github.com/oa-plugins/webauto/pkg/proxy"
+)
+
+// Proxy rotation reasons reported in warnings
+const (
+	rotateNavigations  = "navigation limit reached"
+	rotateBotDetection = "bot detection triggered"
+)
+
+// resolveProxy returns the proxy a new session uses: the explicit proxy, the
+// next entry of the requested or configured pool, or none
+func (sm *SessionManager) resolveProxy(ctx context.Context, opts LaunchOptions) (*proxy.Proxy, *proxy.Info, error) {
+	if opts.Proxy != nil {
+		return opts.Proxy, opts.Proxy.Info(proxy.SourceFlag), nil
+	}
+
+	poolPath := opts.ProxyPoolPath
+	if poolPath == "" {
+		poolPath = sm.cfg.ProxyPoolPath
+	}
+	if poolPath == "" {
+		return nil, nil, nil
+	}
+
+	pool, err := proxy.LoadPool(poolPath, sm.cfg.ProxyStatePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, next, info, err := pool.Next(ctx, -1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return next, info, nil
+}
+
+// sessionPool loads the pool a session's proxy came from, or nil
+func (sm *SessionManager) sessionPool(session *Session) *proxy.Pool {
+	sm.mu.RLock()
+	info := session.Proxy
+	sm.mu.RUnlock()
+
+	if info == nil || info.Source != proxy.SourcePool || info.PoolPath == "" {
+		return nil
+	}
+
+	pool, err := proxy.LoadPool(info.PoolPath, sm.cfg.ProxyStatePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: proxy rotation disabled: %v\n", err)
+		return nil
+	}
+	return pool
+}
+
+// beforeNavigate applies the pool's rotation policy to a navigation: the
+// session moves to the next proxy once it has made rotate_every_navigations
+// navigations, and the runner is asked to detect bot challenges when the
+// pool rotates on them. Returns the session's pool (nil without one).
+func (sm *SessionManager) beforeNavigate(ctx context.Context, managed *managedSession, command map[string]interface{}) *proxy.Pool {
+	if command["command"] != "navigate" {
+		return nil
+	}
+
+	pool := sm.sessionPool(managed.session)
+	if pool == nil {
+		return nil
+	}
+
+	sm.mu.RLock()
+	navigations := managed.session.ProxyNavigations
+	sm.mu.RUnlock()
+
+	if pool.RotateEveryNavigations > 0 && navigations >= pool.RotateEveryNavigations {
+		if err := sm.rotateProxy(ctx, managed, pool); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to rotate proxy (%s): %v\n", rotateNavigations, err)
+		}
+	}
+
+	if pool.RotateOnBotDetection {
+		command["detectBot"] = true
+	}
+	return pool
+}
+
+// afterNavigate counts successful navigations on the current proxy and
+// rotates the proxy when the runner reported a bot challenge
+func (sm *SessionManager) afterNavigate(ctx context.Context, managed *managedSession, pool *proxy.Pool, command map[string]interface{}, resp *ipc.NodeResponse) {
+	if pool == nil || command["command"] != "navigate" || resp == nil {
+		return
+	}
+
+	if resp.Success {
+		sm.mu.Lock()
+		managed.session.ProxyNavigations++
+		sm.mu.Unlock()
+		return
+	}
+
+	if pool.RotateOnBotDetection && isBotDetection(resp.Error) {
+		if err := sm.rotateProxy(ctx, managed, pool); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to rotate proxy (%s): %v\n", rotateBotDetection, err)
+		}
+	}
+}
+
+// rotateProxy moves the session's browser context to the next proxy of its
+// pool, keeping cookies, storage and the current page
+func (sm *SessionManager) rotateProxy(ctx context.Context, managed *managedSession, pool *proxy.Pool) error {
+	sm.mu.RLock()
+	current := -1
+	if managed.session.Proxy != nil && managed.session.Proxy.PoolIndex != nil {
+		current = *managed.session.Proxy.PoolIndex
+	}
+	rotations := 0
+	if managed.session.Proxy != nil {
+		rotations = managed.session.Proxy.Rotations
+	}
+	sm.mu.RUnlock()
+
+	_, next, info, err := pool.Next(ctx, current)
+	if err != nil {
+		return err
+	}
+
+	command := map[string]interface{}{
+		"command": "context-proxy",
+		"proxy":   next.Playwright(),
+	}
+	if managed.session.ContextID != "" {
+		command["contextId"] = managed.session.ContextID
+	}
+
+	resp, err := managed.worker.Send(ctx, command)
+	if err != nil || !resp.Success {
+		return fmt.Errorf("%s", describeFailure(resp, err))
+	}
+
+	info.Rotations = rotations + 1
+
+	sm.mu.Lock()
+	managed.session.Proxy = info
+	managed.session.ProxyNavigations = 0
+	sm.mu.Unlock()
+
+	return managed.session.saveSession()
+}
+
+// isBotDetection reports whether a runner error is a detected bot challenge
+func isBotDetection(message string) bool {
+	return strings.HasPrefix(message, "Bot detection triggered")
+}
@@ -1,7 +1,7 @@
 package playwright
 
 import (
-	_ "embed"
+	"embed"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,8 +10,10 @@ import (
 	"github.com/oa-plugins/webauto/pkg/bootstrap"
 )
 
-//go:embed runner/session-server.js
-var sessionRunnerSource string
+// runnerFiles holds the Node.js session runner and the modules it requires
+//
+//go:embed runner/*.js
+var runnerFiles embed.FS
 
 var (
 	sessionRunnerOnce sync.Once
@@ -29,13 +31,26 @@ func ensureSessionRunnerScript() (string, error) {
 			return
 		}
 
-		targetPath := filepath.Join(runnerDir, "session-server.js")
-		if err := os.WriteFile(targetPath, []byte(sessionRunnerSource), 0644); err != nil {
-			sessionRunnerErr = fmt.Errorf("failed to write runner script: %w", err)
+		entries, err := runnerFiles.ReadDir("runner")
+		if err != nil {
+			sessionRunnerErr = fmt.Errorf("failed to read embedded runner files: %w", err)
 			return
 		}
 
-		sessionRunnerPath = targetPath
+		for _, entry := range entries {
+			source, err := runnerFiles.ReadFile("runner/" + entry.Name())
+			if err != nil {
+				sessionRunnerErr = fmt.Errorf("failed to read embedded runner file %s: %w", entry.Name(), err)
+				return
+			}
+
+			if err := os.WriteFile(filepath.Join(runnerDir, entry.Name()), source, 0644); err != nil {
+				sessionRunnerErr = fmt.Errorf("failed to write runner script: %w", err)
+				return
+			}
+		}
+
+		sessionRunnerPath = filepath.Join(runnerDir, "session-server.js")
 	})
 
 	return sessionRunnerPath, sessionRunnerErr
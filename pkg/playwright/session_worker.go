@@ -137,6 +137,25 @@ func (w *sessionWorker) writeCommand(ctx context.Context, payload map[string]int
 }
 
 func (w *sessionWorker) readResponse(ctx context.Context) (*ipc.NodeResponse, error) {
+	// Streamed results arrive as chunk lines ahead of the final response
+	for {
+		resp, err := w.readLine(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Chunk {
+			return resp, nil
+		}
+
+		handler := chunkHandlerFrom(ctx)
+		if handler == nil {
+			return nil, fmt.Errorf("received a streamed chunk without a chunk handler")
+		}
+		handler(resp.Data)
+	}
+}
+
+func (w *sessionWorker) readLine(ctx context.Context) (*ipc.NodeResponse, error) {
 	deadline := deadlineFromContext(ctx, 30*time.Second)
 	if err := w.conn.SetReadDeadline(deadline); err != nil {
 		return nil, fmt.Errorf("failed to set read deadline: %w", err)
@@ -0,0 +1,22 @@
+package playwright
+
+import "context"
+
+// ChunkHandler receives the data of every chunk a command streams ahead of
+// its final response. It runs on the session worker while the runner is
+// still producing the rest, so it should hand the data off quickly.
+type ChunkHandler func(data map[string]interface{})
+
+type chunkHandlerKey struct{}
+
+// WithChunkHandler returns a context under which streamed chunks of a
+// command are passed to handler as they arrive instead of being rejected.
+// Only commands sent with this context may ask the runner to stream.
+func WithChunkHandler(ctx context.Context, handler ChunkHandler) context.Context {
+	return context.WithValue(ctx, chunkHandlerKey{}, handler)
+}
+
+func chunkHandlerFrom(ctx context.Context) ChunkHandler {
+	handler, _ := ctx.Value(chunkHandlerKey{}).(ChunkHandler)
+	return handler
+}
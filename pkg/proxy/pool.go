@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oa-plugins/webauto/internal/filelock"
+)
+
+// Strategies for picking the next proxy from a pool
+const (
+	// StrategyRoundRobin hands out the pool entries in order, continuing
+	// where the previous webauto process stopped
+	StrategyRoundRobin = "round-robin"
+
+	// StrategyRandom picks a random entry other than the current one
+	StrategyRandom = "random"
+)
+
+// Pool is the proxy pool file format.
+//
+// Every session launched with the pool gets the next proxy (per-session
+// rotation). RotateEveryNavigations additionally switches a session to the
+// next proxy after that many navigations, and RotateOnBotDetection when a
+// navigation fails with BOT_DETECTION_TRIGGERED.
+type Pool struct {
+	Strategy               string  `json:"strategy"`
+	RotateEveryNavigations int     `json:"rotate_every_navigations"`
+	RotateOnBotDetection   bool    `json:"rotate_on_bot_detection"`
+	Proxies                []Proxy `json:"proxies"`
+
+	path      string
+	statePath string
+}
+
+// UnmarshalJSON accepts a pool entry as a proxy URL string or as an object
+// with server, username, password and bypass
+func (p *Proxy) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		parsed, err := Parse(raw)
+		if err != nil {
+			return err
+		}
+		*p = *parsed
+		return nil
+	}
+
+	type plain Proxy
+	var entry plain
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("proxy entry must be a URL string or an object: %w", err)
+	}
+	*p = Proxy(entry)
+	return p.Validate()
+}
+
+// LoadPool reads a pool file. statePath is where the round-robin cursor is
+// shared between processes.
+func LoadPool(path, statePath string) (*Pool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy pool path: %w", err)
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy pool: %w", err)
+	}
+
+	pool := &Pool{Strategy: StrategyRoundRobin}
+	if err := json.Unmarshal(data, pool); err != nil {
+		// Parse errors never echo the file content, which holds credentials
+		return nil, fmt.Errorf("failed to parse proxy pool %s: %v", absPath, err)
+	}
+
+	switch {
+	case len(pool.Proxies) == 0:
+		return nil, fmt.Errorf("proxy pool %s has no proxies", absPath)
+	case pool.Strategy != StrategyRoundRobin && pool.Strategy != StrategyRandom:
+		return nil, fmt.Errorf("unknown proxy pool strategy %q (use %s or %s)", pool.Strategy, StrategyRoundRobin, StrategyRandom)
+	case pool.RotateEveryNavigations < 0:
+		return nil, fmt.Errorf("rotate_every_navigations must not be negative")
+	}
+
+	pool.path = absPath
+	pool.statePath = statePath
+	return pool, nil
+}
+
+// Path returns the absolute path of the pool file
+func (p *Pool) Path() string {
+	return p.path
+}
+
+// Next picks the proxy for a new session, or the replacement for the entry
+// at current when rotating (-1 for a new session). It returns the entry's
+// index, the proxy and its credential-free description.
+func (p *Pool) Next(ctx context.Context, current int) (int, *Proxy, *Info, error) {
+	var index int
+	switch {
+	case len(p.Proxies) == 1:
+		index = 0
+	case p.Strategy == StrategyRandom:
+		index = rand.Intn(len(p.Proxies))
+		for index == current {
+			index = rand.Intn(len(p.Proxies))
+		}
+	default:
+		var err error
+		if index, err = p.advance(ctx); err != nil {
+			return 0, nil, nil, err
+		}
+		if index == current {
+			if index, err = p.advance(ctx); err != nil {
+				return 0, nil, nil, err
+			}
+		}
+	}
+
+	proxy := p.Proxies[index]
+	info := proxy.Info(SourcePool)
+	info.PoolPath = p.path
+	info.PoolIndex = &index
+	return index, &proxy, info, nil
+}
+
+// poolState is the rotation bookkeeping shared between webauto processes
+type poolState struct {
+	// Cursors holds the next round-robin index per pool file
+	Cursors map[string]int `json:"cursors"`
+}
+
+// advance returns the round-robin cursor and moves it to the next entry
+func (p *Pool) advance(ctx context.Context) (int, error) {
+	if p.statePath == "" {
+		return 0, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p.statePath), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create proxy state directory: %w", err)
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	lock, err := filelock.Acquire(lockCtx, p.statePath+".lock")
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock proxy state: %w", err)
+	}
+	defer lock.Release()
+
+	state := poolState{Cursors: map[string]int{}}
+	if data, err := os.ReadFile(p.statePath); err == nil {
+		_ = json.Unmarshal(data, &state)
+		if state.Cursors == nil {
+			state.Cursors = map[string]int{}
+		}
+	}
+
+	index := state.Cursors[p.path] % len(p.Proxies)
+	state.Cursors[p.path] = (index + 1) % len(p.Proxies)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal proxy state: %w", err)
+	}
+	// Writers hold the lock, so a fixed temporary path is safe
+	tmpPath := p.statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	if err := os.Rename(tmpPath, p.statePath); err != nil {
+		return 0, fmt.Errorf("failed to write proxy state: %w", err)
+	}
+
+	return index, nil
+}
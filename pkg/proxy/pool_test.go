@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePool writes a pool file and returns its path
+func writePool(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pool.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{"url entries", `{"proxies": ["http://a:1", "socks5://b:2"]}`, 2, false},
+		{"object entry", `{"proxies": [{"server": "c:3", "username": "u", "password": "p"}]}`, 1, false},
+		{"empty pool", `{"proxies": []}`, 0, true},
+		{"unknown strategy", `{"strategy": "weighted", "proxies": ["a:1"]}`, 0, true},
+		{"negative rotation", `{"rotate_every_navigations": -1, "proxies": ["a:1"]}`, 0, true},
+		{"bad entry", `{"proxies": ["ftp://a:1"]}`, 0, true},
+		{"not json", `proxies: a:1`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := LoadPool(writePool(t, tt.content), "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadPool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(pool.Proxies) != tt.want {
+				t.Errorf("LoadPool() proxies = %d, want %d", len(pool.Proxies), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPoolErrorHidesCredentials(t *testing.T) {
+	_, err := LoadPool(writePool(t, `{"proxies": [{"server": "http://u:secret@a:1"}]}`), "")
+	if err == nil {
+		t.Fatal("LoadPool() accepted credentials in server")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("LoadPool() error %q leaks the password", err)
+	}
+}
+
+func TestPoolNextRoundRobin(t *testing.T) {
+	path := writePool(t, `{"proxies": ["a:1", "b:2", "c:3"]}`)
+	statePath := filepath.Join(t.TempDir(), "proxy-state.json")
+	ctx := context.Background()
+
+	// A second process loading the same pool continues the rotation
+	first, err := LoadPool(path, statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadPool(path, statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for _, pool := range []*Pool{first, second, first, second} {
+		index, proxy, info, err := pool.Next(ctx, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxy.Server != pool.Proxies[index].Server || *info.PoolIndex != index || info.Source != SourcePool {
+			t.Errorf("Next() = %d, %+v, %+v", index, proxy, info)
+		}
+		got = append(got, index)
+	}
+
+	want := []int{0, 1, 2, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Next() indexes = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPoolNextSkipsCurrent(t *testing.T) {
+	tests := []struct {
+		strategy string
+	}{
+		{StrategyRoundRobin},
+		{StrategyRandom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			path := writePool(t, `{"strategy": "`+tt.strategy+`", "proxies": ["a:1", "b:2"]}`)
+			pool, err := LoadPool(path, filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 10; i++ {
+				index, _, _, err := pool.Next(context.Background(), 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if index != 1 {
+					t.Fatalf("Next(0) = %d, want the other proxy", index)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oa-plugins/webauto/internal/filelock"
+)
+
+// Policies applied when a domain budget is exhausted
+const (
+	// PolicyWait blocks the command until the budget allows it (up to MaxWaitMs)
+	PolicyWait = "wait"
+
+	// PolicyFail rejects the command immediately with ErrLimitExceeded
+	PolicyFail = "fail"
+)
+
+// Budget limit names reported in ExceededError
+const (
+	LimitNavigationsPerMinute  = "navigations_per_minute"
+	LimitMinDelay              = "min_delay_ms"
+	LimitMaxConcurrentSessions = "max_concurrent_sessions"
+)
+
+const (
+	// DefaultMaxWait is the longest a command waits for budget under PolicyWait
+	DefaultMaxWait = 60 * time.Second
+
+	// sessionPollInterval is how often a command waiting for a free
+	// per-domain session slot re-checks the shared state
+	sessionPollInterval = time.Second
+)
+
+// ErrLimitExceeded is returned (wrapped in *ExceededError) when a domain
+// budget is exhausted and the policy does not allow waiting
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// Rule is a per-domain request budget. Zero values disable the limit.
+type Rule struct {
+	NavigationsPerMinute  int `json:"navigations_per_minute"`
+	MinDelayMs            int `json:"min_delay_ms"`
+	MaxConcurrentSessions int `json:"max_concurrent_sessions"`
+}
+
+func (r Rule) isZero() bool {
+	return r.NavigationsPerMinute <= 0 && r.MinDelayMs <= 0 && r.MaxConcurrentSessions <= 0
+}
+
+// Rules is the rate limit configuration file format.
+//
+// Domain keys match the host itself and all of its subdomains, so a rule for
+// "hometax.go.kr" also covers "www.hometax.go.kr". Hosts matching the same key
+// share one budget.
+type Rules struct {
+	Policy    string          `json:"policy"`
+	MaxWaitMs int             `json:"max_wait_ms"`
+	Default   Rule            `json:"default"`
+	Domains   map[string]Rule `json:"domains"`
+}
+
+// LoadRules reads rules from a JSON file. A missing file yields empty rules
+// (no throttling).
+func LoadRules(path string) (*Rules, error) {
+	rules := &Rules{Policy: PolicyWait, Domains: map[string]Rule{}}
+	if path == "" {
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rules, nil
+		}
+		return nil, fmt.Errorf("failed to read rate limit config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit config: %w", err)
+	}
+
+	if rules.Domains == nil {
+		rules.Domains = map[string]Rule{}
+	}
+
+	normalized := make(map[string]Rule, len(rules.Domains))
+	for domain, rule := range rules.Domains {
+		normalized[strings.ToLower(strings.TrimPrefix(domain, "."))] = rule
+	}
+	rules.Domains = normalized
+
+	return rules, nil
+}
+
+// ruleFor returns the budget key and rule that apply to a host
+func (r *Rules) ruleFor(host string) (string, Rule, bool) {
+	best := ""
+	for domain := range r.Domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			if len(domain) > len(best) {
+				best = domain
+			}
+		}
+	}
+
+	if best != "" {
+		return best, r.Domains[best], true
+	}
+
+	if !r.Default.isZero() {
+		return host, r.Default, true
+	}
+
+	return "", Rule{}, false
+}
+
+// ExceededError describes which budget rejected a command
+type ExceededError struct {
+	Domain     string
+	Limit      string
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s (%s), retry after %dms", e.Domain, e.Limit, e.RetryAfter.Milliseconds())
+}
+
+// Is reports whether target is ErrLimitExceeded
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Request describes a command about to be sent to a browser session
+type Request struct {
+	SessionID  string
+	Host       string
+	Navigation bool
+}
+
+// Limiter enforces per-domain budgets. Budget state lives in a file shared by
+// every webauto process, guarded by a lock file.
+type Limiter struct {
+	rules      *Rules
+	policy     string
+	maxWait    time.Duration
+	statePath  string
+	lockPath   string
+	sessionTTL time.Duration
+}
+
+// NewLimiter creates a Limiter that keeps shared state in stateDir.
+// Session slots not refreshed within sessionTTL are reclaimed.
+func NewLimiter(rules *Rules, stateDir string, sessionTTL time.Duration) *Limiter {
+	if rules == nil {
+		rules = &Rules{Domains: map[string]Rule{}}
+	}
+
+	policy := rules.Policy
+	if policy != PolicyFail {
+		policy = PolicyWait
+	}
+
+	maxWait := DefaultMaxWait
+	if rules.MaxWaitMs > 0 {
+		maxWait = time.Duration(rules.MaxWaitMs) * time.Millisecond
+	}
+
+	return &Limiter{
+		rules:      rules,
+		policy:     policy,
+		maxWait:    maxWait,
+		statePath:  filepath.Join(stateDir, "state.json"),
+		lockPath:   filepath.Join(stateDir, "state.lock"),
+		sessionTTL: sessionTTL,
+	}
+}
+
+// SetPolicy overrides the policy from the rules file
+func (l *Limiter) SetPolicy(policy string) {
+	if policy == PolicyWait || policy == PolicyFail {
+		l.policy = policy
+	}
+}
+
+// Enabled reports whether any rule is configured
+func (l *Limiter) Enabled() bool {
+	return len(l.rules.Domains) > 0 || !l.rules.Default.isZero()
+}
+
+// Acquire blocks until the request fits in its domain budget and records it.
+// It returns the time spent waiting, or an *ExceededError when the policy is
+// PolicyFail or the wait would exceed the configured maximum.
+func (l *Limiter) Acquire(ctx context.Context, req Request) (time.Duration, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	host := strings.ToLower(req.Host)
+	key, rule, ok := l.rules.ruleFor(host)
+	if !ok || host == "" {
+		return 0, nil
+	}
+
+	started := time.Now()
+	for {
+		retryAfter, limit, err := l.tryAcquire(ctx, key, rule, req)
+		if err != nil {
+			return time.Since(started), err
+		}
+		if retryAfter <= 0 {
+			return time.Since(started), nil
+		}
+
+		exceeded := &ExceededError{Domain: key, Limit: limit, RetryAfter: retryAfter}
+		if l.policy == PolicyFail || time.Since(started)+retryAfter > l.maxWait {
+			return time.Since(started), exceeded
+		}
+
+		select {
+		case <-ctx.Done():
+			return time.Since(started), ctx.Err()
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// Release frees the per-domain session slot held by a session
+func (l *Limiter) Release(ctx context.Context, sessionID string) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	return l.update(ctx, func(st *state, now time.Time) {
+		for _, ds := range st.Domains {
+			delete(ds.Sessions, sessionID)
+		}
+	})
+}
+
+// tryAcquire checks the budget once; a positive duration means "retry later"
+func (l *Limiter) tryAcquire(ctx context.Context, key string, rule Rule, req Request) (time.Duration, string, error) {
+	var retryAfter time.Duration
+	var limit string
+
+	err := l.update(ctx, func(st *state, now time.Time) {
+		ds := st.domain(key)
+		ds.prune(now, l.sessionTTL)
+
+		retryAfter, limit = ds.check(rule, req, now)
+		if retryAfter > 0 {
+			return
+		}
+
+		ds.LastActionAt = now
+		if req.Navigation {
+			ds.Navigations = append(ds.Navigations, now)
+
+			// A session occupies a slot only on the domain it navigated to last
+			for other, otherState := range st.Domains {
+				if other != key {
+					delete(otherState.Sessions, req.SessionID)
+				}
+			}
+			ds.Sessions[req.SessionID] = now
+		} else if _, held := ds.Sessions[req.SessionID]; held {
+			ds.Sessions[req.SessionID] = now
+		}
+	})
+
+	return retryAfter, limit, err
+}
+
+// update runs fn on the shared state while holding the cross-process lock
+func (l *Limiter) update(ctx context.Context, fn func(st *state, now time.Time)) error {
+	if err := os.MkdirAll(filepath.Dir(l.statePath), 0755); err != nil {
+		return fmt.Errorf("failed to create rate limit directory: %w", err)
+	}
+
+	lock, err := filelock.Acquire(ctx, l.lockPath)
+	if err != nil {
+		return fmt.Errorf("failed to lock rate limit state: %w", err)
+	}
+	defer lock.Release()
+
+	st, err := readState(l.statePath)
+	if err != nil {
+		return err
+	}
+
+	fn(st, time.Now())
+
+	return writeState(l.statePath, st)
+}
+
+// HostFromURL extracts the lower-cased host name from a URL. Non-network
+// URLs such as about:blank yield an empty string.
+func HostFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
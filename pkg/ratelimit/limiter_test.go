@@ -0,0 +1,182 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDomainStateCheck(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		state     domainState
+		req       Request
+		wantWait  time.Duration
+		wantLimit string
+	}{
+		{
+			name:  "no limits",
+			rule:  Rule{},
+			state: domainState{LastActionAt: ago(time.Millisecond)},
+			req:   Request{SessionID: "a", Navigation: true},
+		},
+		{
+			name:      "min delay not yet passed",
+			rule:      Rule{MinDelayMs: 1000},
+			state:     domainState{LastActionAt: ago(300 * time.Millisecond)},
+			req:       Request{SessionID: "a"},
+			wantWait:  700 * time.Millisecond,
+			wantLimit: LimitMinDelay,
+		},
+		{
+			name:  "min delay passed",
+			rule:  Rule{MinDelayMs: 1000},
+			state: domainState{LastActionAt: ago(2 * time.Second)},
+			req:   Request{SessionID: "a"},
+		},
+		{
+			name:  "navigation budget ignores other commands",
+			rule:  Rule{NavigationsPerMinute: 1},
+			state: domainState{Navigations: []time.Time{ago(10 * time.Second)}},
+			req:   Request{SessionID: "a"},
+		},
+		{
+			name:      "navigation budget exhausted",
+			rule:      Rule{NavigationsPerMinute: 2},
+			state:     domainState{Navigations: []time.Time{ago(50 * time.Second), ago(10 * time.Second)}},
+			req:       Request{SessionID: "a", Navigation: true},
+			wantWait:  10 * time.Second,
+			wantLimit: LimitNavigationsPerMinute,
+		},
+		{
+			name:  "navigation budget left",
+			rule:  Rule{NavigationsPerMinute: 3},
+			state: domainState{Navigations: []time.Time{ago(50 * time.Second), ago(10 * time.Second)}},
+			req:   Request{SessionID: "a", Navigation: true},
+		},
+		{
+			name:      "session slots taken",
+			rule:      Rule{MaxConcurrentSessions: 1},
+			state:     domainState{Sessions: map[string]time.Time{"b": ago(time.Second)}},
+			req:       Request{SessionID: "a", Navigation: true},
+			wantWait:  sessionPollInterval,
+			wantLimit: LimitMaxConcurrentSessions,
+		},
+		{
+			name:  "session already holds a slot",
+			rule:  Rule{MaxConcurrentSessions: 1},
+			state: domainState{Sessions: map[string]time.Time{"a": ago(time.Second)}},
+			req:   Request{SessionID: "a", Navigation: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, limit := tt.state.check(tt.rule, tt.req, now)
+			if wait != tt.wantWait || limit != tt.wantLimit {
+				t.Errorf("check() = %v, %q, want %v, %q", wait, limit, tt.wantWait, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestDomainStatePrune(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	tests := []struct {
+		name            string
+		sessionTTL      time.Duration
+		wantNavigations int
+		wantSessions    []string
+	}{
+		{"drops old navigations and idle sessions", time.Minute, 2, []string{"fresh"}},
+		{"keeps sessions without a ttl", 0, 2, []string{"fresh", "idle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &domainState{
+				Navigations: []time.Time{ago(2 * time.Minute), ago(61 * time.Second), ago(59 * time.Second), ago(time.Second)},
+				Sessions:    map[string]time.Time{"fresh": ago(10 * time.Second), "idle": ago(5 * time.Minute)},
+			}
+			ds.prune(now, tt.sessionTTL)
+
+			if len(ds.Navigations) != tt.wantNavigations {
+				t.Errorf("kept %d navigations, want %d", len(ds.Navigations), tt.wantNavigations)
+			}
+			if len(ds.Sessions) != len(tt.wantSessions) {
+				t.Errorf("kept sessions %v, want %v", ds.Sessions, tt.wantSessions)
+			}
+			for _, id := range tt.wantSessions {
+				if _, ok := ds.Sessions[id]; !ok {
+					t.Errorf("session %s was pruned", id)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterAcquirePolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		maxWaitMs int
+		wantErr   bool
+		minWait   time.Duration
+	}{
+		{"fail rejects at once", PolicyFail, 0, true, 0},
+		{"wait blocks until the budget allows", PolicyWait, 0, false, 150 * time.Millisecond},
+		{"wait gives up beyond the maximum", PolicyWait, 50, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(&Rules{
+				Policy:    tt.policy,
+				MaxWaitMs: tt.maxWaitMs,
+				Domains:   map[string]Rule{"example.com": {MinDelayMs: 200}},
+			}, t.TempDir(), time.Minute)
+
+			req := Request{SessionID: "a", Host: "www.example.com", Navigation: true}
+			if _, err := limiter.Acquire(context.Background(), req); err != nil {
+				t.Fatalf("first Acquire() = %v", err)
+			}
+
+			waited, err := limiter.Acquire(context.Background(), req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("second Acquire() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var exceeded *ExceededError
+				if !errors.As(err, &exceeded) || !errors.Is(err, ErrLimitExceeded) {
+					t.Fatalf("second Acquire() error = %v, want an *ExceededError", err)
+				}
+				if exceeded.Domain != "example.com" || exceeded.Limit != LimitMinDelay || exceeded.RetryAfter <= 0 {
+					t.Errorf("ExceededError = %+v", exceeded)
+				}
+			}
+			if waited < tt.minWait {
+				t.Errorf("waited %v, want at least %v", waited, tt.minWait)
+			}
+		})
+	}
+}
+
+func TestLimiterAcquireUnlimitedHost(t *testing.T) {
+	limiter := NewLimiter(&Rules{
+		Policy:  PolicyFail,
+		Domains: map[string]Rule{"example.com": {NavigationsPerMinute: 1}},
+	}, t.TempDir(), time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Acquire(context.Background(), Request{SessionID: "a", Host: "other.org", Navigation: true}); err != nil {
+			t.Fatalf("Acquire() on a host without a rule = %v", err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// state is the budget bookkeeping shared between webauto processes
+type state struct {
+	Domains map[string]*domainState `json:"domains"`
+}
+
+// domainState tracks recent activity for one budget key
+type domainState struct {
+	Navigations  []time.Time          `json:"navigations,omitempty"`
+	LastActionAt time.Time            `json:"last_action_at"`
+	Sessions     map[string]time.Time `json:"sessions,omitempty"`
+}
+
+func (st *state) domain(key string) *domainState {
+	ds, ok := st.Domains[key]
+	if !ok {
+		ds = &domainState{}
+		st.Domains[key] = ds
+	}
+	if ds.Sessions == nil {
+		ds.Sessions = map[string]time.Time{}
+	}
+	return ds
+}
+
+// prune drops navigations older than the one minute window and session slots
+// that have not been used within sessionTTL
+func (ds *domainState) prune(now time.Time, sessionTTL time.Duration) {
+	windowStart := now.Add(-time.Minute)
+	kept := ds.Navigations[:0]
+	for _, t := range ds.Navigations {
+		if t.After(windowStart) {
+			kept = append(kept, t)
+		}
+	}
+	ds.Navigations = kept
+
+	if sessionTTL > 0 {
+		for id, lastSeen := range ds.Sessions {
+			if now.Sub(lastSeen) > sessionTTL {
+				delete(ds.Sessions, id)
+			}
+		}
+	}
+}
+
+// check returns how long the request must wait and which limit applies
+func (ds *domainState) check(rule Rule, req Request, now time.Time) (time.Duration, string) {
+	if rule.MinDelayMs > 0 && !ds.LastActionAt.IsZero() {
+		minDelay := time.Duration(rule.MinDelayMs) * time.Millisecond
+		if elapsed := now.Sub(ds.LastActionAt); elapsed < minDelay {
+			return minDelay - elapsed, LimitMinDelay
+		}
+	}
+
+	if !req.Navigation {
+		return 0, ""
+	}
+
+	if rule.NavigationsPerMinute > 0 && len(ds.Navigations) >= rule.NavigationsPerMinute {
+		oldest := ds.Navigations[len(ds.Navigations)-rule.NavigationsPerMinute]
+		return oldest.Add(time.Minute).Sub(now), LimitNavigationsPerMinute
+	}
+
+	if rule.MaxConcurrentSessions > 0 {
+		if _, held := ds.Sessions[req.SessionID]; !held && len(ds.Sessions) >= rule.MaxConcurrentSessions {
+			return sessionPollInterval, LimitMaxConcurrentSessions
+		}
+	}
+
+	return 0, ""
+}
+
+func readState(path string) (*state, error) {
+	st := &state{Domains: map[string]*domainState{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, st); err != nil {
+		// A corrupted state file only loses recent history; start over
+		return &state{Domains: map[string]*domainState{}}, nil
+	}
+
+	if st.Domains == nil {
+		st.Domains = map[string]*domainState{}
+	}
+
+	return st, nil
+}
+
+func writeState(path string, st *state) error {
+	for key, ds := range st.Domains {
+		if len(ds.Navigations) == 0 && len(ds.Sessions) == 0 && time.Since(ds.LastActionAt) > time.Minute {
+			delete(st.Domains, key)
+		}
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate limit state: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rate limit state: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace rate limit state: %w", err)
+	}
+
+	return nil
+}
@@ -24,9 +24,20 @@ type ErrorInfo struct {
 
 // Metadata contains plugin metadata
 type Metadata struct {
-	Plugin          string `json:"plugin"`
-	Version         string `json:"version"`
-	ExecutionTimeMs int64  `json:"execution_time_ms"`
+	Plugin          string         `json:"plugin"`
+	Version         string         `json:"version"`
+	ExecutionTimeMs int64          `json:"execution_time_ms"`
+	Attempts        int            `json:"attempts,omitempty"`
+	AttemptErrors   []AttemptError `json:"attempt_errors,omitempty"`
+}
+
+// AttemptError describes a failed attempt of a browser command
+type AttemptError struct {
+	Attempt    int    `json:"attempt"`
+	Command    string `json:"command"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	DurationMs int64  `json:"duration_ms"`
 }
 
 // Success creates a success response
@@ -68,3 +79,9 @@ func (r *StandardResponse) Print() {
 	encoder.SetIndent("", "  ")
 	encoder.Encode(r)
 }
+
+// PrintLine outputs the response as a single line of JSON, for streamed
+// (NDJSON) output
+func (r *StandardResponse) PrintLine() {
+	json.NewEncoder(os.Stdout).Encode(r)
+}
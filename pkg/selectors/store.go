@@ -0,0 +1,135 @@
+package selectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oa-plugins/webauto/internal/filelock"
+)
+
+// lockTimeout bounds how long Put waits for another process updating the store
+const lockTimeout = 5 * time.Second
+
+// Entry is the last known-good way to find a named element
+type Entry struct {
+	// Selector describes the locator that matched on the last success
+	// (e.g. role=button[name="Save"]); it is for display only
+	Selector string `json:"selector"`
+
+	// Locator is the runner locator that matched, replayed as a candidate
+	// on the next lookup
+	Locator map[string]interface{} `json:"locator,omitempty"`
+
+	// Strategy is how the selector was found (primary, fallback, fingerprint)
+	Strategy string `json:"strategy"`
+
+	// Fingerprint describes the element (tag, text, attributes, position) as
+	// captured by the runner; it is used to re-find the element when every
+	// candidate selector breaks
+	Fingerprint map[string]interface{} `json:"fingerprint,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists named selector entries in a JSON file
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store backed by the given file
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Get returns the entry for key, or nil if none has been recorded
+func (s *Store) Get(key string) (*Entry, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := entries[key]
+	if !ok {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// Put records the entry for key. The store is locked while it is rewritten
+// so concurrent webauto processes do not drop each other's entries.
+func (s *Store) Put(key string, entry *Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create selector store directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	lock, err := filelock.Acquire(ctx, s.path+".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock selector store: %w", err)
+	}
+	defer lock.Release()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	entries[key] = entry
+	return s.save(entries)
+}
+
+func (s *Store) load() (map[string]*Entry, error) {
+	entries := map[string]*Entry{}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, fmt.Errorf("failed to read selector store: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse selector store: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) save(entries map[string]*Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal selector store: %w", err)
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write selector store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write selector store: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write selector store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write selector store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace selector store: %w", err)
+	}
+
+	return nil
+}
@@ -20,7 +20,7 @@ func BenchmarkBrowserLaunch(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		session, err := sessionMgr.Create(ctx, "chromium", true)
+		session, err := sessionMgr.Create(ctx, "chromium", true, "")
 		if err != nil {
 			b.Fatalf("Failed to launch browser: %v", err)
 		}
@@ -40,7 +40,7 @@ func BenchmarkBrowserClose(b *testing.B) {
 	// Pre-create sessions
 	sessions := make([]string, b.N)
 	for i := 0; i < b.N; i++ {
-		session, err := sessionMgr.Create(ctx, "chromium", true)
+		session, err := sessionMgr.Create(ctx, "chromium", true, "")
 		if err != nil {
 			b.Fatalf("Failed to launch browser: %v", err)
 		}
@@ -65,7 +65,7 @@ func BenchmarkSessionList(b *testing.B) {
 
 	// Create 5 sessions
 	for i := 0; i < 5; i++ {
-		_, err := sessionMgr.Create(ctx, "chromium", true)
+		_, err := sessionMgr.Create(ctx, "chromium", true, "")
 		if err != nil {
 			b.Fatalf("Failed to create session: %v", err)
 		}
@@ -88,7 +88,7 @@ func BenchmarkPageNavigate(b *testing.B) {
 	ctx := context.Background()
 
 	// Create a single session for all navigations
-	session, err := sessionMgr.Create(ctx, "chromium", true)
+	session, err := sessionMgr.Create(ctx, "chromium", true, "")
 	if err != nil {
 		b.Fatalf("Failed to launch browser: %v", err)
 	}
@@ -118,7 +118,7 @@ func BenchmarkElementClick(b *testing.B) {
 	ctx := context.Background()
 
 	// Create session and navigate to test page
-	session, err := sessionMgr.Create(ctx, "chromium", true)
+	session, err := sessionMgr.Create(ctx, "chromium", true, "")
 	if err != nil {
 		b.Fatalf("Failed to launch browser: %v", err)
 	}
@@ -159,7 +159,7 @@ func BenchmarkElementType(b *testing.B) {
 	ctx := context.Background()
 
 	// Create session and navigate to test page
-	session, err := sessionMgr.Create(ctx, "chromium", true)
+	session, err := sessionMgr.Create(ctx, "chromium", true, "")
 	if err != nil {
 		b.Fatalf("Failed to launch browser: %v", err)
 	}
@@ -201,7 +201,7 @@ func BenchmarkPageScreenshot(b *testing.B) {
 	ctx := context.Background()
 
 	// Create session and navigate to test page
-	session, err := sessionMgr.Create(ctx, "chromium", true)
+	session, err := sessionMgr.Create(ctx, "chromium", true, "")
 	if err != nil {
 		b.Fatalf("Failed to launch browser: %v", err)
 	}
@@ -242,7 +242,7 @@ func BenchmarkSendCommand(b *testing.B) {
 	ctx := context.Background()
 
 	// Create session
-	session, err := sessionMgr.Create(ctx, "chromium", true)
+	session, err := sessionMgr.Create(ctx, "chromium", true, "")
 	if err != nil {
 		b.Fatalf("Failed to launch browser: %v", err)
 	}